@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"strings"
 )
 
 // SearchEngine represents a search engine identifier.
@@ -18,6 +19,29 @@ type SearchAdapter interface {
 	Search(ctx context.Context, request *SearchRequest) (*SearchResponse, error)
 }
 
+// Paginator is an optional interface for search adapters that can fetch further result pages.
+// An adapter that supports pagination sets SearchResponse.NextPageToken when more results are
+// available, and fetches the next page when the token is passed back in SearchRequest.PageToken.
+type Paginator interface {
+	// SupportsPagination reports whether the adapter can fetch further result pages.
+	SupportsPagination() bool
+}
+
+// SupportsPagination reports whether the adapter, or the adapter it decorates, supports pagination.
+func SupportsPagination(adapter SearchAdapter) bool {
+	for adapter != nil {
+		if paginator, ok := adapter.(Paginator); ok {
+			return paginator.SupportsPagination()
+		}
+		wrapper, ok := adapter.(interface{ Unwrap() SearchAdapter })
+		if !ok {
+			return false
+		}
+		adapter = wrapper.Unwrap()
+	}
+	return false
+}
+
 // SearchRequest represents a search request.
 type SearchRequest struct {
 	// Basic parameters
@@ -26,6 +50,7 @@ type SearchRequest struct {
 
 	// Common options
 	Offset     int    `json:"offset,omitempty"`
+	PageToken  string `json:"page_token,omitempty"` // Token of the page to fetch, from SearchResponse.NextPageToken.
 	Lang       string `json:"lang,omitempty"`
 	Region     string `json:"region,omitempty"`
 	SafeSearch string `json:"safe_search,omitempty"`
@@ -37,6 +62,9 @@ type SearchRequest struct {
 
 	// Engine-specific parameters
 	EngineParams map[string]interface{} `json:"engine_params,omitempty"`
+
+	// Caching options
+	BypassCache bool `json:"bypass_cache,omitempty"` // Skip cached responses and fetch fresh results.
 }
 
 // SearchResponse represents a search response.
@@ -45,6 +73,58 @@ type SearchResponse struct {
 	Results    []*SearchResultItem `json:"results"`
 	TotalCount int                 `json:"total_count,omitempty"`
 	TimeTaken  int64               `json:"time_taken_ms,omitempty"`
+
+	// NextPageToken fetches the next page of results when passed in SearchRequest.PageToken.
+	// It is empty if there are no more results or the adapter does not support pagination.
+	NextPageToken string `json:"next_page_token,omitempty"`
+
+	// Route is the query class chosen by a routing strategy, e.g., "news"; empty if the query was not routed.
+	Route string `json:"route,omitempty"`
+
+	// Direct answers and query hints, for engines that provide them.
+	Answers     []*DirectAnswer `json:"answers,omitempty"`     // Instant answers to the query.
+	Infoboxes   []*Infobox      `json:"infoboxes,omitempty"`   // Knowledge panels about the entity the query refers to.
+	Suggestions []string        `json:"suggestions,omitempty"` // Related queries suggested by the engine.
+	Corrections []string        `json:"corrections,omitempty"` // Spelling corrections of the query.
+
+	// UnresponsiveEngines lists the upstream engines of a metasearch engine that failed to answer.
+	UnresponsiveEngines []*EngineDiagnostic `json:"unresponsive_engines,omitempty"`
+}
+
+// DirectAnswer is an instant answer to the query, e.g., a definition or a unit conversion.
+type DirectAnswer struct {
+	Answer string `json:"answer"`
+	URL    string `json:"url,omitempty"`
+	Engine string `json:"engine,omitempty"`
+}
+
+// Infobox is a knowledge panel about the entity a query refers to.
+type Infobox struct {
+	Title      string              `json:"title"`
+	Content    string              `json:"content,omitempty"`
+	URL        string              `json:"url,omitempty"`
+	ImageURL   string              `json:"image_url,omitempty"`
+	Attributes []*InfoboxAttribute `json:"attributes,omitempty"`
+	Links      []*InfoboxLink      `json:"links,omitempty"`
+	Engine     string              `json:"engine,omitempty"`
+}
+
+// InfoboxAttribute is a labeled fact in an infobox, e.g., "Born: 1879".
+type InfoboxAttribute struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// InfoboxLink is a link listed in an infobox.
+type InfoboxLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// EngineDiagnostic reports an upstream engine that failed to answer and why.
+type EngineDiagnostic struct {
+	Engine string `json:"engine"`
+	Reason string `json:"reason"`
 }
 
 // SearchResultItem represents a single search result item.
@@ -64,4 +144,13 @@ type SearchResultItem struct {
 	PublishDate string                 `json:"publish_date,omitempty"`
 	FileType    string                 `json:"file_type,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+
+	// Content is the full page content in markdown, for engines that scrape the pages they return.
+	// Results with content do not need to be scraped again.
+	Content string `json:"content,omitempty"`
+}
+
+// HasContent reports whether the result carries the full page content.
+func (r *SearchResultItem) HasContent() bool {
+	return strings.TrimSpace(r.Content) != ""
 }
@@ -1,7 +1,10 @@
 package search
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
@@ -10,34 +13,146 @@ import (
 // AdapterCreatorFunc defines the function signature for an adapter creator.
 type AdapterCreatorFunc func(engineConfig types.EngineConfig) (SearchAdapter, error)
 
-// adapterCreators is a global registry for adapter creator functions.
-var adapterCreators = make(map[string]AdapterCreatorFunc)
+// AdapterCapabilities describes the optional features a search adapter supports.
+type AdapterCapabilities struct {
+	// Pagination reports whether the adapter returns further pages of results.
+	Pagination bool `json:"pagination"`
+
+	// Filters lists the search filters (see FilterSite and friends) the adapter honours.
+	Filters []string `json:"filters,omitempty"`
+
+	// Answers reports whether the adapter returns direct answers, infoboxes or query suggestions.
+	Answers bool `json:"answers"`
+
+	// Content reports whether the adapter returns the content of the result pages.
+	Content bool `json:"content"`
+}
+
+// AdapterDescriptor describes a search adapter implementation.
+type AdapterDescriptor struct {
+	// Description is a short human-readable description of the adapter.
+	Description string `json:"description,omitempty"`
+
+	// Capabilities lists the optional features the adapter supports.
+	Capabilities AdapterCapabilities `json:"capabilities"`
+
+	// ConfigSchema describes the keys of the engine-specific `config` section.
+	ConfigSchema []types.ConfigField `json:"config_schema,omitempty"`
+}
+
+var (
+	// creatorsMu guards adapterCreators and adapterDescriptors.
+	creatorsMu sync.RWMutex
+
+	// adapterCreators is a global registry for adapter creator functions.
+	adapterCreators = make(map[string]AdapterCreatorFunc)
+
+	// adapterDescriptors holds the descriptions registered by the adapter packages.
+	adapterDescriptors = make(map[string]AdapterDescriptor)
+)
+
+var (
+	// configMu serializes RegisterAllSearchAdapters.
+	configMu sync.Mutex
+
+	// configEngines holds the engines registered from the configuration by the last RegisterAllSearchAdapters.
+	configEngines = make(map[string]bool)
+)
 
 // RegisterAdapterCreator registers an adapter creator function for a given engine name.
 func RegisterAdapterCreator(engineName string, creator AdapterCreatorFunc) {
+	creatorsMu.Lock()
+	defer creatorsMu.Unlock()
 	adapterCreators[engineName] = creator
 }
 
+// RegisterAdapterDescriptor registers the description of the adapter of a given engine name,
+// which is reported by DescribeSearchAdapter and ListSearchAdapters.
+func RegisterAdapterDescriptor(engineName string, descriptor AdapterDescriptor) {
+	creatorsMu.Lock()
+	defer creatorsMu.Unlock()
+	adapterDescriptors[engineName] = descriptor
+}
+
+// getAdapterCreator returns the adapter creator registered for the engine name.
+func getAdapterCreator(engineName string) (AdapterCreatorFunc, bool) {
+	creatorsMu.RLock()
+	defer creatorsMu.RUnlock()
+	creator, exists := adapterCreators[engineName]
+	return creator, exists
+}
+
+// hasAdapterCreator reports whether an adapter creator is registered for the engine name.
+func hasAdapterCreator(engineName string) bool {
+	_, exists := getAdapterCreator(engineName)
+	return exists
+}
+
+// getAdapterDescriptor returns the description registered for the engine name.
+func getAdapterDescriptor(engineName string) (AdapterDescriptor, bool) {
+	creatorsMu.RLock()
+	defer creatorsMu.RUnlock()
+	descriptor, exists := adapterDescriptors[engineName]
+	return descriptor, exists
+}
+
+// adapterCreatorNames returns the engine names that have an adapter creator, sorted by name.
+func adapterCreatorNames() []string {
+	creatorsMu.RLock()
+	defer creatorsMu.RUnlock()
+
+	names := make([]string, 0, len(adapterCreators))
+	for name := range adapterCreators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // RegisterAllSearchAdapters registers all search adapters based on the application configuration.
+// It can be called again after the configuration was updated (see config.UpdateConfig): engines
+// enabled in the configuration are registered anew, so that the strategies recreate their adapters
+// with the new settings, and engines that are no longer enabled are unregistered. The configuration of
+// every enabled engine is validated first; engines with an invalid configuration are not registered and
+// their errors are returned together.
 func RegisterAllSearchAdapters() error {
 	searchConfig := config.GetSearchConfig()
 	if searchConfig == nil {
 		return fmt.Errorf("search configuration not initialized")
 	}
 
+	configMu.Lock()
+	defer configMu.Unlock()
+
 	// Iterate through the engines in the config and dynamically register enabled adapters.
+	enabled := make(map[string]bool)
+	var errs []error
 	for engineName, engineConfig := range searchConfig.Engines {
 		if engineConfig.Enabled {
 			// Use a local variable to avoid closure capture issues.
 			name := engineName
+			// Create the adapter once so that configuration errors surface at registration.
+			if _, err := createAdapterFromConfig(name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
 			// Register the adapter using a generic factory function.
 			RegisterSearchAdapter(SearchEngine(name), func() (SearchAdapter, error) {
 				return createAdapterFromConfig(name)
 			})
+			enabled[name] = true
 		}
 	}
 
-	return nil
+	// Unregister the engines that were registered from an earlier configuration but are no longer enabled.
+	for name := range configEngines {
+		if !enabled[name] {
+			UnregisterSearchAdapter(SearchEngine(name))
+		}
+	}
+	configEngines = enabled
+
+	return errors.Join(errs...)
 }
 
 // createAdapterFromConfig is a generic adapter creation function.
@@ -58,7 +173,7 @@ func createAdapterFromConfig(engineName string) (SearchAdapter, error) {
 	}
 
 	// Find the corresponding adapter creator.
-	creator, exists := adapterCreators[engineName]
+	creator, exists := getAdapterCreator(engineName)
 	if !exists {
 		return nil, fmt.Errorf("adapter creator not registered for %s", engineName)
 	}
@@ -0,0 +1,508 @@
+package search
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anboat/strato-sdk/config/types"
+	"github.com/anboat/strato-sdk/pkg/logging"
+)
+
+// Cache backend names.
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendDisk   = "disk"
+)
+
+// Default cache settings.
+const (
+	DefaultCacheTTL        = time.Hour
+	DefaultCacheMaxEntries = 1000
+	DefaultCacheDirectory  = ".cache/search"
+
+	// DefaultDiskCacheMaxEntries is the number of files the disk backend keeps by default.
+	DefaultDiskCacheMaxEntries = 10000
+	// DefaultDiskCacheSweepInterval is how often the disk backend removes expired files.
+	DefaultDiskCacheSweepInterval = 10 * time.Minute
+)
+
+// MetadataCacheHit is the metadata key that marks whether a result was served from the cache.
+const MetadataCacheHit = "cache_hit"
+
+// strategyCacheScope is the engine part of cache keys for responses cached at the strategy level.
+const strategyCacheScope = "strategy"
+
+// CacheBackend stores search responses for a limited time.
+// Implementations must be safe for concurrent use.
+type CacheBackend interface {
+	// Get returns the response stored under the key, if it exists and has not expired.
+	Get(key string) (*SearchResponse, bool)
+
+	// Set stores the response under the key for the given time to live.
+	Set(key string, response *SearchResponse, ttl time.Duration)
+}
+
+// CacheConfig configures the caching decorators.
+type CacheConfig struct {
+	// TTL is how long responses are cached. Defaults to DefaultCacheTTL.
+	TTL time.Duration
+
+	// TimeRangeTTLs overrides the TTL for requests with a time range, keyed by normalized
+	// time range (day, week, month, year), so that recency-sensitive searches expire sooner.
+	TimeRangeTTLs map[string]time.Duration
+}
+
+// ttlFor returns the time to live of the response to a request.
+func (c CacheConfig) ttlFor(request *SearchRequest) time.Duration {
+	if ttl, ok := c.TimeRangeTTLs[NormalizeTimeRange(request.TimeRange)]; ok && ttl > 0 {
+		return ttl
+	}
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return DefaultCacheTTL
+}
+
+// CacheKey returns the cache key of a request sent to an engine. The query is normalized
+// (trimmed, lowercased, whitespace collapsed) and the time range is normalized, so that
+// near-identical requests share an entry.
+func CacheKey(engine SearchEngine, request *SearchRequest) string {
+	parts := []string{
+		string(engine),
+		strings.Join(strings.Fields(strings.ToLower(request.Query)), " "),
+		strings.ToLower(strings.TrimSpace(request.Lang)),
+		strings.ToLower(strings.TrimSpace(request.Region)),
+		NormalizeTimeRange(request.TimeRange),
+		strings.ToLower(strings.TrimSpace(request.SafeSearch)),
+		strings.ToLower(strings.TrimSpace(request.Site)),
+		strings.ToLower(strings.TrimSpace(request.FileType)),
+		strconv.Itoa(request.Num),
+		strconv.Itoa(request.Offset),
+		request.PageToken,
+	}
+	if len(request.EngineParams) > 0 {
+		// Engine parameters are encoded with sorted keys, so equal maps produce equal keys.
+		if params, err := json.Marshal(request.EngineParams); err == nil {
+			parts = append(parts, string(params))
+		}
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+// CachedStrategy is a SearchStrategy decorator that serves repeated requests from a cache.
+type CachedStrategy struct {
+	strategy SearchStrategy
+	backend  CacheBackend
+	config   CacheConfig
+}
+
+// NewCachedStrategy wraps a search strategy with a cache.
+func NewCachedStrategy(strategy SearchStrategy, backend CacheBackend, config CacheConfig) *CachedStrategy {
+	return &CachedStrategy{strategy: strategy, backend: backend, config: config}
+}
+
+// Execute returns the cached response to the request if there is one, and otherwise executes
+// the wrapped strategy and caches its response. Requests with BypassCache set always execute
+// the strategy, but their response still refreshes the cache.
+func (s *CachedStrategy) Execute(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+	return cachedSearch(s.backend, s.config, CacheKey(strategyCacheScope, request), request, func() (*SearchResponse, error) {
+		return s.strategy.Execute(ctx, request)
+	})
+}
+
+// ExecuteStream sends the cached response to the request as a single batch if there is one,
+// and otherwise streams the batches of the wrapped strategy, see StreamSearch. Streamed batches
+// do not fill the cache, since they hold the unfused results of single engines.
+func (s *CachedStrategy) ExecuteStream(ctx context.Context, request *SearchRequest) (<-chan *SearchBatch, error) {
+	if !request.BypassCache {
+		if cached, ok := s.backend.Get(CacheKey(strategyCacheScope, request)); ok {
+			response := cloneResponse(cached)
+			markCacheHit(response, true)
+			return singleBatch(response, nil), nil
+		}
+	}
+	return StreamSearch(ctx, s.strategy, request)
+}
+
+// Unwrap returns the wrapped search strategy.
+func (s *CachedStrategy) Unwrap() SearchStrategy {
+	return s.strategy
+}
+
+// CachedAdapter is a SearchAdapter decorator that serves repeated requests to an engine from a cache.
+type CachedAdapter struct {
+	adapter SearchAdapter
+	engine  SearchEngine
+	backend CacheBackend
+	config  CacheConfig
+}
+
+// NewCachedAdapter wraps the search adapter of an engine with a cache.
+func NewCachedAdapter(engine SearchEngine, adapter SearchAdapter, backend CacheBackend, config CacheConfig) *CachedAdapter {
+	return &CachedAdapter{adapter: adapter, engine: engine, backend: backend, config: config}
+}
+
+// Search returns the cached response to the request if there is one, and otherwise performs
+// the search and caches its response. Requests with BypassCache set always perform the search.
+func (a *CachedAdapter) Search(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+	return cachedSearch(a.backend, a.config, CacheKey(a.engine, request), request, func() (*SearchResponse, error) {
+		return a.adapter.Search(ctx, request)
+	})
+}
+
+// Unwrap returns the wrapped search adapter.
+func (a *CachedAdapter) Unwrap() SearchAdapter {
+	return a.adapter
+}
+
+// cachedSearch serves a request from the cache or performs it and caches the response.
+// Responses are copied in and out of the cache, so callers may modify them freely.
+func cachedSearch(backend CacheBackend, config CacheConfig, key string, request *SearchRequest, search func() (*SearchResponse, error)) (*SearchResponse, error) {
+	if !request.BypassCache {
+		if cached, ok := backend.Get(key); ok {
+			response := cloneResponse(cached)
+			markCacheHit(response, true)
+			return response, nil
+		}
+	}
+
+	response, err := search()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only responses with results are cached, so that an empty answer is retried next time.
+	if response != nil && len(response.Results) > 0 {
+		markCacheHit(response, false)
+		backend.Set(key, cloneResponse(response), config.ttlFor(request))
+	}
+	return response, nil
+}
+
+// markCacheHit records in the metadata of every result whether it was served from the cache.
+func markCacheHit(response *SearchResponse, hit bool) {
+	for _, result := range response.Results {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
+		result.Metadata[MetadataCacheHit] = hit
+	}
+}
+
+// cloneResponse returns a copy of a response whose results and their metadata, direct answers,
+// infoboxes, query hints and engine diagnostics can be modified without affecting the original.
+func cloneResponse(response *SearchResponse) *SearchResponse {
+	clone := *response
+	clone.Results = make([]*SearchResultItem, len(response.Results))
+	for i, result := range response.Results {
+		item := *result
+		if result.Metadata != nil {
+			item.Metadata = make(map[string]interface{}, len(result.Metadata))
+			for k, v := range result.Metadata {
+				item.Metadata[k] = v
+			}
+		}
+		clone.Results[i] = &item
+	}
+
+	clone.Answers = clonePointers(response.Answers)
+	clone.UnresponsiveEngines = clonePointers(response.UnresponsiveEngines)
+	clone.Infoboxes = clonePointers(response.Infoboxes)
+	for _, infobox := range clone.Infoboxes {
+		infobox.Attributes = clonePointers(infobox.Attributes)
+		infobox.Links = clonePointers(infobox.Links)
+	}
+	clone.Suggestions = slices.Clone(response.Suggestions)
+	clone.Corrections = slices.Clone(response.Corrections)
+	return &clone
+}
+
+// clonePointers returns a slice holding copies of the values the pointers point to, or nil for a nil slice.
+func clonePointers[T any](values []*T) []*T {
+	if values == nil {
+		return nil
+	}
+	clones := make([]*T, len(values))
+	for i, value := range values {
+		if value != nil {
+			clone := *value
+			clones[i] = &clone
+		}
+	}
+	return clones
+}
+
+// MemoryCache is an in-memory LRU cache backend.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // Most recently used entries first.
+}
+
+// memoryCacheEntry is an entry of the in-memory cache.
+type memoryCacheEntry struct {
+	key       string
+	response  *SearchResponse
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an in-memory LRU cache that holds up to maxEntries responses.
+// A non-positive maxEntries uses DefaultCacheMaxEntries.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get implements CacheBackend.
+func (c *MemoryCache) Get(key string) (*SearchResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.response, true
+}
+
+// Set implements CacheBackend.
+func (c *MemoryCache) Set(key string, response *SearchResponse, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*memoryCacheEntry)
+		entry.response = response
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{key: key, response: response, expiresAt: expiresAt})
+
+	// Evict the least recently used entries.
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// DiskCache is a cache backend that stores each response as a JSON file in a directory,
+// so that cached responses survive restarts and are shared between processes.
+// The modification time of each file is set to the expiry of its entry. Expired files are
+// removed on write at most once per sweep interval. Whenever the cache grows beyond maxEntries
+// files, the files that expire first are removed as well, down to 90% of maxEntries, so that
+// a full cache is not swept again on every write.
+type DiskCache struct {
+	directory     string
+	maxEntries    int
+	sweepInterval time.Duration
+
+	mu        sync.Mutex
+	entries   int // Files in the directory, counted at the last sweep plus the files written since.
+	lastSweep time.Time
+}
+
+// diskCacheEntry is the file format of the disk cache.
+type diskCacheEntry struct {
+	ExpiresAt time.Time       `json:"expires_at"`
+	Response  *SearchResponse `json:"response"`
+}
+
+// NewDiskCache creates a disk cache in the given directory that holds up to maxEntries responses,
+// creating the directory if needed. Expired files left by earlier runs are removed right away.
+// An empty directory uses DefaultCacheDirectory, and a non-positive maxEntries uses
+// DefaultDiskCacheMaxEntries.
+func NewDiskCache(directory string, maxEntries int) (*DiskCache, error) {
+	if directory == "" {
+		directory = DefaultCacheDirectory
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultDiskCacheMaxEntries
+	}
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", directory, err)
+	}
+
+	cache := &DiskCache{
+		directory:     directory,
+		maxEntries:    maxEntries,
+		sweepInterval: DefaultDiskCacheSweepInterval,
+	}
+	cache.mu.Lock()
+	cache.sweep()
+	cache.mu.Unlock()
+	return cache, nil
+}
+
+// Get implements CacheBackend.
+func (c *DiskCache) Get(key string) (*SearchResponse, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry diskCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Response == nil {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return entry.Response, true
+}
+
+// Set implements CacheBackend. Failures are logged, as the cache is only an optimization.
+func (c *DiskCache) Set(key string, response *SearchResponse, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+	data, err := json.Marshal(diskCacheEntry{ExpiresAt: expiresAt, Response: response})
+	if err != nil {
+		logging.Warnf("Failed to encode search cache entry: %v", err)
+		return
+	}
+
+	// Write to a temporary file first, so that readers never see a partial entry.
+	tmp, err := os.CreateTemp(c.directory, "entry-*.tmp")
+	if err != nil {
+		logging.Warnf("Failed to write search cache entry: %v", err)
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		logging.Warnf("Failed to write search cache entry: %v", errors.Join(writeErr, closeErr))
+		return
+	}
+	path := c.path(key)
+	_, statErr := os.Stat(path)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		logging.Warnf("Failed to write search cache entry: %v", err)
+		return
+	}
+	// The modification time records the expiry, so that sweeps need not read the files.
+	_ = os.Chtimes(path, time.Now(), expiresAt)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if statErr != nil {
+		c.entries++
+	}
+	if c.entries > c.maxEntries || time.Since(c.lastSweep) >= c.sweepInterval {
+		c.sweep()
+	}
+}
+
+// sweep removes the expired files and leftover temporary files of the cache directory. If more
+// than maxEntries files remain, the files that expire first are removed down to the low-water
+// mark of 90% of maxEntries. The caller must hold c.mu.
+func (c *DiskCache) sweep() {
+	c.lastSweep = time.Now()
+
+	dirEntries, err := os.ReadDir(c.directory)
+	if err != nil {
+		logging.Warnf("Failed to sweep search cache directory %s: %v", c.directory, err)
+		return
+	}
+
+	type cacheFile struct {
+		path      string
+		expiresAt time.Time
+	}
+	var files []cacheFile
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		info, err := dirEntry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		path := filepath.Join(c.directory, name)
+		switch {
+		case strings.HasSuffix(name, ".tmp"):
+			// Temporary files are renamed right after they are written; older ones were abandoned.
+			if time.Since(info.ModTime()) > time.Minute {
+				_ = os.Remove(path)
+			}
+		case strings.HasSuffix(name, ".json"):
+			if c.lastSweep.After(info.ModTime()) {
+				_ = os.Remove(path)
+				continue
+			}
+			files = append(files, cacheFile{path: path, expiresAt: info.ModTime()})
+		}
+	}
+
+	if len(files) > c.maxEntries {
+		lowWater := c.maxEntries - c.maxEntries/10
+		slices.SortFunc(files, func(a, b cacheFile) int { return a.expiresAt.Compare(b.expiresAt) })
+		for _, file := range files[:len(files)-lowWater] {
+			_ = os.Remove(file.path)
+		}
+		files = files[len(files)-lowWater:]
+	}
+	c.entries = len(files)
+}
+
+// path returns the file that stores the entry for the key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.directory, hex.EncodeToString(sum[:])+".json")
+}
+
+// NewCacheBackendFromConfig creates the cache backend described by the search cache configuration.
+func NewCacheBackendFromConfig(cacheConfig *types.SearchCacheConfig) (CacheBackend, error) {
+	switch strings.ToLower(strings.TrimSpace(cacheConfig.Backend)) {
+	case "", CacheBackendMemory:
+		return NewMemoryCache(cacheConfig.MaxEntries), nil
+	case CacheBackendDisk:
+		return NewDiskCache(cacheConfig.Directory, cacheConfig.MaxEntries)
+	default:
+		return nil, fmt.Errorf("unknown search cache backend: %s", cacheConfig.Backend)
+	}
+}
+
+// newCacheConfig converts the search cache configuration into a CacheConfig.
+func newCacheConfig(cacheConfig *types.SearchCacheConfig) CacheConfig {
+	config := CacheConfig{TTL: time.Duration(cacheConfig.TTLSeconds) * time.Second}
+	if len(cacheConfig.TimeRangeTTLSeconds) > 0 {
+		config.TimeRangeTTLs = make(map[string]time.Duration, len(cacheConfig.TimeRangeTTLSeconds))
+		for timeRange, seconds := range cacheConfig.TimeRangeTTLSeconds {
+			config.TimeRangeTTLs[NormalizeTimeRange(timeRange)] = time.Duration(seconds) * time.Second
+		}
+	}
+	return config
+}
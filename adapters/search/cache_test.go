@@ -0,0 +1,270 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCacheKey(t *testing.T) {
+	base := &SearchRequest{Query: "Go  Generics", Lang: "en", TimeRange: "w", Num: 10}
+	same := []*SearchRequest{
+		{Query: "  go generics ", Lang: " EN ", TimeRange: "week", Num: 10},
+		{Query: "GO\tgenerics", Lang: "en", TimeRange: "Week", Num: 10, BypassCache: true},
+	}
+	for _, request := range same {
+		if CacheKey("a", request) != CacheKey("a", base) {
+			t.Errorf("CacheKey(%+v) differs from CacheKey(%+v)", request, base)
+		}
+	}
+
+	different := []*SearchRequest{
+		{Query: "go generics tutorial", Lang: "en", TimeRange: "w", Num: 10},
+		{Query: "go generics", Lang: "de", TimeRange: "w", Num: 10},
+		{Query: "go generics", Lang: "en", TimeRange: "m", Num: 10},
+		{Query: "go generics", Lang: "en", TimeRange: "w", Num: 20},
+		{Query: "go generics", Lang: "en", TimeRange: "w", Num: 10, Offset: 10},
+		{Query: "go generics", Lang: "en", TimeRange: "w", Num: 10, PageToken: "next"},
+		{Query: "go generics", Lang: "en", TimeRange: "w", Num: 10, Site: "go.dev"},
+		{Query: "go generics", Lang: "en", TimeRange: "w", Num: 10, EngineParams: map[string]interface{}{"x": 1}},
+	}
+	for _, request := range different {
+		if CacheKey("a", request) == CacheKey("a", base) {
+			t.Errorf("CacheKey(%+v) equals CacheKey(%+v)", request, base)
+		}
+	}
+	if CacheKey("a", base) == CacheKey("b", base) {
+		t.Error("CacheKey() is the same for different engines")
+	}
+
+	params := func(m map[string]interface{}) *SearchRequest { return &SearchRequest{Query: "q", EngineParams: m} }
+	if CacheKey("a", params(map[string]interface{}{"a": 1, "b": 2})) != CacheKey("a", params(map[string]interface{}{"b": 2, "a": 1})) {
+		t.Error("CacheKey() depends on the order of the engine parameters")
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	cache := NewMemoryCache(10)
+	cache.Set("short", responseFor("q", "https://a.com"), 20*time.Millisecond)
+	cache.Set("long", responseFor("q", "https://b.com"), time.Hour)
+
+	if _, ok := cache.Get("short"); !ok {
+		t.Fatal("Get() missed an entry before its TTL")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	if _, ok := cache.Get("long"); !ok {
+		t.Error("Get() missed an entry within its TTL")
+	}
+	if _, exists := cache.entries["short"]; exists {
+		t.Error("expired entry was not removed")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", responseFor("a"), time.Hour)
+	cache.Set("b", responseFor("b"), time.Hour)
+	cache.Get("a") // "b" is now the least recently used entry.
+	cache.Set("c", responseFor("c"), time.Hour)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := cache.Get(key); ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+
+	// Setting an existing key updates it without evicting anything.
+	cache.Set("a", responseFor("updated"), time.Hour)
+	if response, ok := cache.Get("a"); !ok || response.Query != "updated" {
+		t.Errorf("Get(a) = %+v, %v, want the updated response", response, ok)
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("updating an entry evicted another one")
+	}
+}
+
+func TestCachedAdapter(t *testing.T) {
+	adapter := &fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+		return responseFor(request.Query, "https://example.com/"+request.Query), nil
+	}}
+	cached := NewCachedAdapter("fake", adapter, NewMemoryCache(10), CacheConfig{})
+	ctx := context.Background()
+
+	first, err := cached.Search(ctx, &SearchRequest{Query: "go"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if hit := first.Results[0].Metadata[MetadataCacheHit]; hit != false {
+		t.Errorf("cache_hit of a fresh result = %v, want false", hit)
+	}
+
+	// Callers may modify the response without affecting the cached copy.
+	first.Results[0].Title = "modified"
+
+	second, err := cached.Search(ctx, &SearchRequest{Query: " GO "})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if n := adapter.calls.Load(); n != 1 {
+		t.Errorf("adapter called %d times, want the second search served from the cache", n)
+	}
+	if hit := second.Results[0].Metadata[MetadataCacheHit]; hit != true {
+		t.Errorf("cache_hit of a cached result = %v, want true", hit)
+	}
+	if second.Results[0].Title == "modified" {
+		t.Error("modifying a returned response changed the cached one")
+	}
+
+	// BypassCache performs the search, and its response refreshes the cache.
+	if _, err := cached.Search(ctx, &SearchRequest{Query: "go", BypassCache: true}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if n := adapter.calls.Load(); n != 2 {
+		t.Errorf("adapter called %d times, want BypassCache to search again", n)
+	}
+}
+
+func TestCachedAdapterSkipsEmptyResponses(t *testing.T) {
+	adapter := &fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+		return &SearchResponse{Query: request.Query}, nil
+	}}
+	cached := NewCachedAdapter("fake", adapter, NewMemoryCache(10), CacheConfig{})
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Search(context.Background(), &SearchRequest{Query: "nothing"}); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+	if n := adapter.calls.Load(); n != 2 {
+		t.Errorf("adapter called %d times, want empty responses not cached", n)
+	}
+}
+
+func TestCacheConfigTTL(t *testing.T) {
+	config := CacheConfig{TTL: time.Minute, TimeRangeTTLs: map[string]time.Duration{"day": time.Second}}
+	tests := []struct {
+		timeRange string
+		want      time.Duration
+	}{
+		{timeRange: "", want: time.Minute},
+		{timeRange: "d", want: time.Second},
+		{timeRange: "week", want: time.Minute},
+	}
+	for _, tt := range tests {
+		if got := config.ttlFor(&SearchRequest{TimeRange: tt.timeRange}); got != tt.want {
+			t.Errorf("ttlFor(%q) = %v, want %v", tt.timeRange, got, tt.want)
+		}
+	}
+	if got := (CacheConfig{}).ttlFor(&SearchRequest{}); got != DefaultCacheTTL {
+		t.Errorf("default ttlFor() = %v, want %v", got, DefaultCacheTTL)
+	}
+}
+
+func TestCloneResponse(t *testing.T) {
+	original := &SearchResponse{
+		Query:               "q",
+		Results:             []*SearchResultItem{{URL: "https://a.com", Metadata: map[string]interface{}{"k": "v"}}},
+		Answers:             []*DirectAnswer{{Answer: "42"}},
+		Infoboxes:           []*Infobox{{Title: "Box", Attributes: []*InfoboxAttribute{{Label: "l", Value: "v"}}, Links: []*InfoboxLink{{URL: "https://b.com"}}}},
+		Suggestions:         []string{"suggestion"},
+		Corrections:         []string{"correction"},
+		UnresponsiveEngines: []*EngineDiagnostic{{Engine: "e", Reason: "timeout"}},
+	}
+	clone := cloneResponse(original)
+	if !reflect.DeepEqual(clone, original) {
+		t.Fatalf("cloneResponse() = %+v, want a copy of %+v", clone, original)
+	}
+
+	clone.Results[0].Metadata["k"] = "changed"
+	clone.Answers[0].Answer = "changed"
+	clone.Infoboxes[0].Title = "changed"
+	clone.Infoboxes[0].Attributes[0].Value = "changed"
+	clone.Infoboxes[0].Links[0].URL = "changed"
+	clone.Suggestions[0] = "changed"
+	clone.Corrections[0] = "changed"
+	clone.UnresponsiveEngines[0].Reason = "changed"
+
+	if original.Results[0].Metadata["k"] != "v" || original.Answers[0].Answer != "42" ||
+		original.Infoboxes[0].Title != "Box" || original.Infoboxes[0].Attributes[0].Value != "v" ||
+		original.Infoboxes[0].Links[0].URL != "https://b.com" || original.Suggestions[0] != "suggestion" ||
+		original.Corrections[0] != "correction" || original.UnresponsiveEngines[0].Reason != "timeout" {
+		t.Errorf("modifying the clone changed the original: %+v", original)
+	}
+}
+
+func TestDiskCacheRoundTrip(t *testing.T) {
+	directory := t.TempDir()
+	cache, err := NewDiskCache(directory, 10)
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+
+	response := responseFor("q", "https://a.com")
+	response.Answers = []*DirectAnswer{{Answer: "42"}}
+	cache.Set("key", response, time.Hour)
+	cache.Set("expiring", responseFor("q", "https://b.com"), 20*time.Millisecond)
+
+	// A new cache on the same directory, as after a restart, reads the entries back.
+	reopened, err := NewDiskCache(directory, 10)
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	got, ok := reopened.Get("key")
+	if !ok || !reflect.DeepEqual(got, response) {
+		t.Errorf("Get() = %+v, %v, want %+v", got, ok, response)
+	}
+	if _, ok := reopened.Get("missing"); ok {
+		t.Error("Get() found a missing key")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := reopened.Get("expiring"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	if _, err := os.Stat(reopened.path("expiring")); !os.IsNotExist(err) {
+		t.Errorf("expired entry file still exists: %v", err)
+	}
+
+	// Corrupt files are ignored and removed.
+	if err := os.WriteFile(reopened.path("corrupt"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reopened.Get("corrupt"); ok {
+		t.Error("Get() returned a corrupt entry")
+	}
+}
+
+func TestDiskCacheEvictsToLowWaterMark(t *testing.T) {
+	directory := t.TempDir()
+	cache, err := NewDiskCache(directory, 10)
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+
+	// Later entries expire later, so the first ones are evicted.
+	for i := 0; i < 11; i++ {
+		cache.Set("key"+strconv.Itoa(i), responseFor("q", "https://a.com"), time.Hour+time.Duration(i)*time.Minute)
+	}
+	files, _ := filepath.Glob(filepath.Join(directory, "*.json"))
+	if len(files) != 9 {
+		t.Fatalf("%d files after exceeding maxEntries, want 9", len(files))
+	}
+	for i := 0; i < 11; i++ {
+		_, ok := cache.Get("key" + strconv.Itoa(i))
+		if want := i >= 2; ok != want {
+			t.Errorf("Get(key%d) found = %v, want %v", i, ok, want)
+		}
+	}
+
+	// The next write below the limit does not sweep again.
+	cache.Set("key11", responseFor("q", "https://a.com"), 2*time.Hour)
+	if files, _ := filepath.Glob(filepath.Join(directory, "*.json")); len(files) != 10 {
+		t.Errorf("%d files after one more write, want 10", len(files))
+	}
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Common values for SearchRequest.TimeRange.
+// Adapters translate these into the native form of their engine.
+const (
+	TimeRangeDay   = "day"
+	TimeRangeWeek  = "week"
+	TimeRangeMonth = "month"
+	TimeRangeYear  = "year"
+)
+
+// publishDateLayouts lists the date formats returned by the supported engines, most common first.
+var publishDateLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.000000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC822,
+	time.RFC822Z,
+	time.RFC850,
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// NormalizeTimeRange maps a time range to one of the TimeRange constants.
+// It accepts aliases such as "past_week", "w" or "7d". Unknown values are returned unchanged.
+func NormalizeTimeRange(timeRange string) string {
+	switch strings.ToLower(strings.TrimSpace(timeRange)) {
+	case "day", "d", "1d", "24h", "past_day", "last_day":
+		return TimeRangeDay
+	case "week", "w", "7d", "past_week", "last_week":
+		return TimeRangeWeek
+	case "month", "m", "30d", "past_month", "last_month":
+		return TimeRangeMonth
+	case "year", "y", "365d", "past_year", "last_year":
+		return TimeRangeYear
+	default:
+		return timeRange
+	}
+}
+
+// TimeRangeDuration returns the length of the window described by a time range.
+// The second return value is false if the time range is empty or unknown.
+func TimeRangeDuration(timeRange string) (time.Duration, bool) {
+	switch NormalizeTimeRange(timeRange) {
+	case TimeRangeDay:
+		return 24 * time.Hour, true
+	case TimeRangeWeek:
+		return 7 * 24 * time.Hour, true
+	case TimeRangeMonth:
+		return 30 * 24 * time.Hour, true
+	case TimeRangeYear:
+		return 365 * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
+
+// ParsePublishDate parses a publish date in any of the formats returned by the supported engines,
+// including Unix timestamps in seconds or milliseconds.
+func ParsePublishDate(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range publishDateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	if timestamp, err := strconv.ParseInt(raw, 10, 64); err == nil && timestamp > 0 {
+		if timestamp > 1e12 {
+			return time.UnixMilli(timestamp).UTC(), true
+		}
+		return time.Unix(timestamp, 0).UTC(), true
+	}
+
+	return time.Time{}, false
+}
+
+// NormalizePublishDate converts a publish date to RFC 3339 in UTC.
+// Dates that cannot be parsed are returned unchanged.
+func NormalizePublishDate(raw string) string {
+	t, ok := ParsePublishDate(raw)
+	if !ok {
+		return raw
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,176 @@
+package search
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultDomainWeight is the credibility weight applied to results whose domain has no entry in the weight table.
+const DefaultDomainWeight = 1.0
+
+// domainPolicy filters and weights search results based on the domain of their URL.
+// A rule such as "example.com" matches the domain itself and all of its subdomains,
+// and a suffix rule such as "gov" or "edu.cn" matches every domain under that suffix.
+type domainPolicy struct {
+	allowlist []string
+	denylist  []string
+	weights   map[string]float64
+}
+
+// newDomainPolicy creates a domain policy from the strategy configuration.
+// It returns nil if the configuration does not define any domain rules.
+func newDomainPolicy(config *SearchStrategyConfig) *domainPolicy {
+	if len(config.DomainAllowlist) == 0 && len(config.DomainDenylist) == 0 && len(config.DomainWeights) == 0 {
+		return nil
+	}
+
+	policy := &domainPolicy{
+		allowlist: normalizeDomainRules(config.DomainAllowlist),
+		denylist:  normalizeDomainRules(config.DomainDenylist),
+		weights:   make(map[string]float64, len(config.DomainWeights)),
+	}
+	for domain, weight := range config.DomainWeights {
+		if rule := normalizeDomainRule(domain); rule != "" {
+			policy.weights[rule] = weight
+		}
+	}
+
+	return policy
+}
+
+// filter removes the results that are not allowed by the policy and records the credibility
+// weight of the remaining results in Metadata[MetadataDomainWeight], keeping their order.
+// Mixed searches filter each engine's results and leave the weighting to the result merger.
+func (p *domainPolicy) filter(results []*SearchResultItem) []*SearchResultItem {
+	if p == nil || len(results) == 0 {
+		return results
+	}
+
+	filtered := make([]*SearchResultItem, 0, len(results))
+	for _, result := range results {
+		host := resultHost(result)
+		if !p.isAllowed(host) {
+			continue
+		}
+
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
+		result.Metadata[MetadataDomainWeight] = p.weightFor(host)
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
+
+// apply filters the results of a single engine and reorders them so that results from preferred
+// domains move up. The reordering uses the weight divided by the original position, so a weight
+// only overrides the engine's ranking when the difference in credibility is large enough.
+func (p *domainPolicy) apply(results []*SearchResultItem) []*SearchResultItem {
+	if p == nil || len(results) == 0 {
+		return results
+	}
+
+	type weightedResult struct {
+		item  *SearchResultItem
+		score float64
+	}
+
+	allowed := p.filter(results)
+	weighted := make([]weightedResult, len(allowed))
+	for i, result := range allowed {
+		weighted[i] = weightedResult{
+			item:  result,
+			score: resultDomainWeight(result) / float64(i+1),
+		}
+	}
+
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].score > weighted[j].score
+	})
+
+	filtered := make([]*SearchResultItem, len(weighted))
+	for i, result := range weighted {
+		filtered[i] = result.item
+	}
+
+	return filtered
+}
+
+// isAllowed reports whether results from the given host may be used.
+func (p *domainPolicy) isAllowed(host string) bool {
+	if host == "" {
+		// Results without a URL cannot be attributed to a domain; keep them only if no allowlist is set.
+		return len(p.allowlist) == 0
+	}
+
+	for _, rule := range p.denylist {
+		if matchesDomain(host, rule) {
+			return false
+		}
+	}
+
+	if len(p.allowlist) == 0 {
+		return true
+	}
+
+	for _, rule := range p.allowlist {
+		if matchesDomain(host, rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// weightFor returns the weight of the most specific rule matching the host.
+func (p *domainPolicy) weightFor(host string) float64 {
+	weight := DefaultDomainWeight
+	longestMatch := -1
+	for rule, ruleWeight := range p.weights {
+		if len(rule) > longestMatch && matchesDomain(host, rule) {
+			weight = ruleWeight
+			longestMatch = len(rule)
+		}
+	}
+	return weight
+}
+
+// resultHost returns the normalized host name of a search result.
+func resultHost(result *SearchResultItem) string {
+	rawURL := result.URL
+	if rawURL == "" {
+		rawURL = result.Link
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
+// matchesDomain reports whether the host equals the domain rule or is a subdomain of it.
+func matchesDomain(host, rule string) bool {
+	return host == rule || strings.HasSuffix(host, "."+rule)
+}
+
+// normalizeDomainRules normalizes a list of domain rules, dropping empty entries.
+func normalizeDomainRules(domains []string) []string {
+	var rules []string
+	for _, domain := range domains {
+		if rule := normalizeDomainRule(domain); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// normalizeDomainRule lowercases a domain rule and strips wildcards, leading dots and a "www." prefix.
+func normalizeDomainRule(domain string) string {
+	rule := strings.ToLower(strings.TrimSpace(domain))
+	rule = strings.TrimPrefix(rule, "*.")
+	rule = strings.TrimPrefix(rule, ".")
+	return strings.TrimPrefix(rule, "www.")
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// resultsFor builds search results from URLs.
+func resultsFor(urls ...string) []*SearchResultItem {
+	results := make([]*SearchResultItem, len(urls))
+	for i, url := range urls {
+		results[i] = &SearchResultItem{URL: url}
+	}
+	return results
+}
+
+func TestDomainPolicyApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SearchStrategyConfig
+		urls   []string
+		want   []string
+	}{
+		{
+			name:   "allowlist",
+			config: SearchStrategyConfig{DomainAllowlist: []string{"example.com", ".gov"}},
+			urls:   []string{"https://example.com/a", "https://news.example.com/b", "https://other.com/c", "https://data.gov/d", ""},
+			want:   []string{"https://example.com/a", "https://news.example.com/b", "https://data.gov/d"},
+		},
+		{
+			name:   "denylist",
+			config: SearchStrategyConfig{DomainDenylist: []string{"*.spam.com", "WWW.Ads.com"}},
+			urls:   []string{"https://spam.com/a", "https://www.spam.com/b", "https://ads.com/c", "https://notspam.com/d", ""},
+			want:   []string{"https://notspam.com/d", ""},
+		},
+		{
+			name:   "denylist wins over allowlist",
+			config: SearchStrategyConfig{DomainAllowlist: []string{"example.com"}, DomainDenylist: []string{"bad.example.com"}},
+			urls:   []string{"https://bad.example.com/a", "https://good.example.com/b"},
+			want:   []string{"https://good.example.com/b"},
+		},
+		{
+			name:   "large weight moves a result up",
+			config: SearchStrategyConfig{DomainWeights: map[string]float64{"trusted.org": 4}},
+			urls:   []string{"https://a.com/1", "https://b.com/2", "https://trusted.org/3"},
+			want:   []string{"https://trusted.org/3", "https://a.com/1", "https://b.com/2"},
+		},
+		{
+			name:   "small weight keeps the engine ranking",
+			config: SearchStrategyConfig{DomainWeights: map[string]float64{"trusted.org": 1.2}},
+			urls:   []string{"https://a.com/1", "https://b.com/2", "https://trusted.org/3"},
+			want:   []string{"https://a.com/1", "https://b.com/2", "https://trusted.org/3"},
+		},
+		{
+			name:   "low weight moves a result down",
+			config: SearchStrategyConfig{DomainWeights: map[string]float64{"content-farm.com": 0.1}},
+			urls:   []string{"https://content-farm.com/1", "https://a.com/2", "https://b.com/3"},
+			want:   []string{"https://a.com/2", "https://b.com/3", "https://content-farm.com/1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newDomainPolicy(&tt.config)
+			if got := resultURLs(policy.apply(resultsFor(tt.urls...))); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainPolicyWeights(t *testing.T) {
+	policy := newDomainPolicy(&SearchStrategyConfig{DomainWeights: map[string]float64{
+		"gov":              1.5,
+		"example.gov":      2,
+		"blog.example.gov": 0.5,
+	}})
+
+	results := resultsFor("https://blog.example.gov/a", "https://www.example.gov/b", "https://other.gov/c", "https://example.com/d")
+	filtered := policy.filter(results)
+	if got := resultURLs(filtered); !reflect.DeepEqual(got, resultURLs(results)) {
+		t.Errorf("filter() = %v, want the results in the engine's order", got)
+	}
+
+	// The most specific rule applies.
+	want := []float64{0.5, 2, 1.5, DefaultDomainWeight}
+	for i, result := range filtered {
+		if got := resultDomainWeight(result); got != want[i] {
+			t.Errorf("weight of %s = %v, want %v", result.URL, got, want[i])
+		}
+	}
+}
+
+func TestNewDomainPolicyWithoutRules(t *testing.T) {
+	policy := newDomainPolicy(&SearchStrategyConfig{})
+	if policy != nil {
+		t.Fatalf("newDomainPolicy() = %+v, want nil without rules", policy)
+	}
+
+	results := resultsFor("https://a.com", "https://b.com")
+	if got := policy.apply(results); !reflect.DeepEqual(got, results) {
+		t.Errorf("apply() of a nil policy = %v, want the results unchanged", resultURLs(got))
+	}
+	if results[0].Metadata != nil {
+		t.Errorf("apply() of a nil policy recorded metadata %v", results[0].Metadata)
+	}
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"strings"
+)
+
+// Names of the common request filters, as reported by FilterCapabilities.
+const (
+	FilterSite      = "site"
+	FilterFileType  = "file_type"
+	FilterRegion    = "region"
+	FilterTimeRange = "time_range"
+	FilterLang      = "lang"
+)
+
+// FilterCapabilities is an optional interface for search adapters that cannot honor every
+// common filter of SearchRequest (Site, FileType, Region, TimeRange and Lang).
+// Adapters that do not implement it are assumed to honor all of them.
+type FilterCapabilities interface {
+	// UnsupportedFilters returns the names of the filters the adapter ignores, e.g., FilterSite.
+	UnsupportedFilters() []string
+}
+
+// UnsupportedFilters returns the filters set on the request that the adapter, or the adapter
+// it decorates, cannot honor.
+//
+// Parameters:
+//   - adapter: The search adapter.
+//   - request: The search request.
+//
+// Returns:
+//   - []string: The names of the ignored filters, in the order of the Filter constants; empty if all are honored.
+func UnsupportedFilters(adapter SearchAdapter, request *SearchRequest) []string {
+	var capabilities FilterCapabilities
+	for adapter != nil {
+		if c, ok := adapter.(FilterCapabilities); ok {
+			capabilities = c
+			break
+		}
+		wrapper, ok := adapter.(interface{ Unwrap() SearchAdapter })
+		if !ok {
+			break
+		}
+		adapter = wrapper.Unwrap()
+	}
+	if capabilities == nil {
+		return nil
+	}
+
+	unsupported := capabilities.UnsupportedFilters()
+	var ignored []string
+	for _, filter := range activeFilters(request) {
+		if containsString(unsupported, filter) {
+			ignored = append(ignored, filter)
+		}
+	}
+	return ignored
+}
+
+// activeFilters returns the names of the common filters set on the request.
+func activeFilters(request *SearchRequest) []string {
+	var filters []string
+	if strings.TrimSpace(request.Site) != "" {
+		filters = append(filters, FilterSite)
+	}
+	if strings.TrimSpace(request.FileType) != "" {
+		filters = append(filters, FilterFileType)
+	}
+	if strings.TrimSpace(request.Region) != "" {
+		filters = append(filters, FilterRegion)
+	}
+	if strings.TrimSpace(request.TimeRange) != "" {
+		filters = append(filters, FilterTimeRange)
+	}
+	if strings.TrimSpace(request.Lang) != "" {
+		filters = append(filters, FilterLang)
+	}
+	return filters
+}
+
+// QueryWithOperators appends the Site and FileType filters of the request to its query as
+// "site:" and "filetype:" operators, the form understood by most web search engines.
+// Operators already present in the query are not added twice.
+func QueryWithOperators(request *SearchRequest) string {
+	query := strings.TrimSpace(request.Query)
+	lowerQuery := strings.ToLower(query)
+
+	if site := normalizeSite(request.Site); site != "" && !strings.Contains(lowerQuery, "site:") {
+		query += " site:" + site
+	}
+	if fileType := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(request.FileType)), "."); fileType != "" && !strings.Contains(lowerQuery, "filetype:") {
+		query += " filetype:" + fileType
+	}
+	return query
+}
+
+// normalizeSite reduces a site filter to a host name, stripping any scheme, path and "site:" prefix.
+func normalizeSite(site string) string {
+	site = strings.TrimSpace(strings.ToLower(site))
+	site = strings.TrimPrefix(site, "site:")
+	if i := strings.Index(site, "://"); i >= 0 {
+		site = site[i+3:]
+	}
+	if i := strings.IndexAny(site, "/?#"); i >= 0 {
+		site = site[:i]
+	}
+	return site
+}
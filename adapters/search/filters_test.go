@@ -0,0 +1,88 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// limitedAdapter is a fake adapter that cannot honor some filters.
+type limitedAdapter struct {
+	*fakeAdapter
+	unsupported []string
+}
+
+// UnsupportedFilters implements FilterCapabilities.
+func (a limitedAdapter) UnsupportedFilters() []string { return a.unsupported }
+
+func TestNormalizeSite(t *testing.T) {
+	tests := []struct {
+		site string
+		want string
+	}{
+		{"", ""},
+		{"go.dev", "go.dev"},
+		{"  Go.Dev  ", "go.dev"},
+		{"site:go.dev", "go.dev"},
+		{"https://go.dev/doc/gc-guide", "go.dev"},
+		{"http://example.com?q=1", "example.com"},
+		{"example.com#top", "example.com"},
+		{"blog.example.com/posts/", "blog.example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeSite(tt.site); got != tt.want {
+			t.Errorf("normalizeSite(%q) = %q, want %q", tt.site, got, tt.want)
+		}
+	}
+}
+
+func TestQueryWithOperators(t *testing.T) {
+	tests := []struct {
+		name    string
+		request SearchRequest
+		want    string
+	}{
+		{"no filters", SearchRequest{Query: " go gc "}, "go gc"},
+		{"site", SearchRequest{Query: "go gc", Site: "https://go.dev/doc"}, "go gc site:go.dev"},
+		{"file type", SearchRequest{Query: "go gc", FileType: ".PDF"}, "go gc filetype:pdf"},
+		{"both", SearchRequest{Query: "go gc", Site: "go.dev", FileType: "pdf"}, "go gc site:go.dev filetype:pdf"},
+		{"site already in query", SearchRequest{Query: "go gc Site:go.dev", Site: "example.com"}, "go gc Site:go.dev"},
+		{"file type already in query", SearchRequest{Query: "go gc filetype:pdf", FileType: "doc", Site: "go.dev"}, "go gc filetype:pdf site:go.dev"},
+		{"blank filters", SearchRequest{Query: "go gc", Site: " ", FileType: "."}, "go gc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QueryWithOperators(&tt.request); got != tt.want {
+				t.Errorf("QueryWithOperators() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsupportedFilters(t *testing.T) {
+	limited := limitedAdapter{answering(0), []string{FilterSite, FilterRegion, FilterLang}}
+	allFilters := &SearchRequest{Query: "q", Site: "go.dev", FileType: "pdf", Region: "US", TimeRange: "week", Lang: "en"}
+
+	tests := []struct {
+		name    string
+		adapter SearchAdapter
+		request *SearchRequest
+		want    []string
+	}{
+		{"adapter honoring every filter", answering(0), allFilters, nil},
+		{"ignored filters in order", limited, allFilters, []string{FilterSite, FilterRegion, FilterLang}},
+		{"only the filters set", limited, &SearchRequest{Query: "q", Region: "US", TimeRange: "week"}, []string{FilterRegion}},
+		{"blank filters are not set", limited, &SearchRequest{Query: "q", Site: " ", Lang: ""}, nil},
+		{"decorated adapter", NewCachedAdapter("fake", limited, NewMemoryCache(10), CacheConfig{}), allFilters, []string{FilterSite, FilterRegion, FilterLang}},
+		{"no adapter", nil, allFilters, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnsupportedFilters(tt.adapter, tt.request); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnsupportedFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -52,6 +54,8 @@ type FirecrawlSearchRequest struct {
 	Query             string         `json:"query"`
 	Limit             int            `json:"limit,omitempty"`
 	Location          string         `json:"location,omitempty"`
+	Country           string         `json:"country,omitempty"`
+	Lang              string         `json:"lang,omitempty"`
 	Tbs               string         `json:"tbs,omitempty"`
 	Timeout           int            `json:"timeout,omitempty"`
 	IgnoreInvalidURLs bool           `json:"ignoreInvalidURLs,omitempty"`
@@ -85,11 +89,12 @@ func (f *FirecrawlAdapter) Search(ctx context.Context, request *search.SearchReq
 	startTime := time.Now()
 
 	firecrawlReq := FirecrawlSearchRequest{
-		Query:    request.Query,
-		Limit:    request.Num,
-		Location: request.Region,
-		Tbs:      request.TimeRange,
+		Query: search.QueryWithOperators(request),
+		Limit: request.Num,
+		Lang:  request.Lang,
+		Tbs:   convertTimeRange(request.TimeRange),
 	}
+	setRegion(&firecrawlReq, request.Region)
 
 	// Parse scrape_options and other Firecrawl-specific parameters from EngineParams.
 	if request.EngineParams != nil {
@@ -149,7 +154,7 @@ func (f *FirecrawlAdapter) Search(ctx context.Context, request *search.SearchReq
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("firecrawl API returned error status: %d, body: %s", resp.StatusCode, string(body))
+		return nil, resilience.NewStatusError(resp, fmt.Sprintf("firecrawl API returned error status: %d, body: %s", resp.StatusCode, string(body)))
 	}
 
 	var firecrawlResp FirecrawlSearchResponse
@@ -165,6 +170,44 @@ func (f *FirecrawlAdapter) Search(ctx context.Context, request *search.SearchReq
 	return response, nil
 }
 
+// convertTimeRange converts a common time range into a Google-style "tbs" value.
+// Values that are not a common time range (e.g., a custom "cdr" range) are passed through unchanged.
+func convertTimeRange(timeRange string) string {
+	switch search.NormalizeTimeRange(timeRange) {
+	case search.TimeRangeDay:
+		return "qdr:d"
+	case search.TimeRangeWeek:
+		return "qdr:w"
+	case search.TimeRangeMonth:
+		return "qdr:m"
+	case search.TimeRangeYear:
+		return "qdr:y"
+	default:
+		return timeRange
+	}
+}
+
+// setRegion sets the region of the request: a two-letter code is sent as the country,
+// anything else, such as "San Francisco,California,United States", as the location.
+func setRegion(firecrawlReq *FirecrawlSearchRequest, region string) {
+	region = strings.TrimSpace(region)
+	if len(region) == 2 {
+		firecrawlReq.Country = strings.ToLower(region)
+		return
+	}
+	firecrawlReq.Location = region
+}
+
+// extractPublishDate looks up the publish date in the page metadata returned by Firecrawl.
+func extractPublishDate(metadata map[string]interface{}) string {
+	for _, key := range []string{"publishedTime", "publishedDate", "article:published_time", "datePublished", "date"} {
+		if value, ok := metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 func (f *FirecrawlAdapter) convertToSearchResponse(firecrawlResp *FirecrawlSearchResponse, query string, timeTaken time.Duration) *search.SearchResponse {
 	results := make([]*search.SearchResultItem, 0, len(firecrawlResp.Data))
 	for i, item := range firecrawlResp.Data {
@@ -173,6 +216,13 @@ func (f *FirecrawlAdapter) convertToSearchResponse(firecrawlResp *FirecrawlSearc
 			metadata = make(map[string]interface{})
 		}
 
+		// The page content is only present when scrape options are requested.
+		content := item.Markdown
+		if content == "" {
+			content = item.Content
+		}
+		// Deprecated: Metadata["markdown"] is kept for one release for existing readers;
+		// use SearchResultItem.Content instead.
 		if item.Markdown != "" {
 			metadata["markdown"] = item.Markdown
 		}
@@ -187,7 +237,9 @@ func (f *FirecrawlAdapter) convertToSearchResponse(firecrawlResp *FirecrawlSearc
 			Link:        item.URL,
 			Snippet:     item.Description,
 			Rank:        i + 1,
+			PublishDate: extractPublishDate(metadata),
 			Metadata:    metadata,
+			Content:     content,
 		})
 	}
 
@@ -0,0 +1,74 @@
+package firecrawl
+
+import (
+	"testing"
+)
+
+func TestConvertTimeRange(t *testing.T) {
+	tests := []struct {
+		timeRange string
+		want      string
+	}{
+		{"", ""},
+		{"day", "qdr:d"},
+		{"past_week", "qdr:w"},
+		{"30d", "qdr:m"},
+		{"Year", "qdr:y"},
+		{"cdr:1,cd_min:1/1/2024", "cdr:1,cd_min:1/1/2024"},
+	}
+
+	for _, tt := range tests {
+		if got := convertTimeRange(tt.timeRange); got != tt.want {
+			t.Errorf("convertTimeRange(%q) = %q, want %q", tt.timeRange, got, tt.want)
+		}
+	}
+}
+
+func TestSetRegion(t *testing.T) {
+	tests := []struct {
+		region       string
+		wantCountry  string
+		wantLocation string
+	}{
+		{"", "", ""},
+		{"US", "us", ""},
+		{" de ", "de", ""},
+		{"San Francisco,California,United States", "", "San Francisco,California,United States"},
+	}
+
+	for _, tt := range tests {
+		var request FirecrawlSearchRequest
+		setRegion(&request, tt.region)
+		if request.Country != tt.wantCountry || request.Location != tt.wantLocation {
+			t.Errorf("setRegion(%q) = country %q, location %q, want %q, %q", tt.region, request.Country, request.Location, tt.wantCountry, tt.wantLocation)
+		}
+	}
+}
+
+func TestConvertToSearchResponseContent(t *testing.T) {
+	adapter := NewFirecrawlAdapter(&FirecrawlConfig{APIKey: "key"})
+	response := adapter.convertToSearchResponse(&FirecrawlSearchResponse{Data: []FirecrawlSearchResult{
+		{URL: "https://a.example/", Markdown: "# A", Content: "plain A"},
+		{URL: "https://b.example/", Content: "plain B"},
+		{URL: "https://c.example/", Description: "snippet only"},
+	}}, "q", 0)
+
+	tests := []struct {
+		content     string
+		hasContent  bool
+		hasMarkdown bool
+	}{
+		{"# A", true, true},
+		{"plain B", true, false},
+		{"", false, false},
+	}
+	for i, tt := range tests {
+		result := response.Results[i]
+		if result.Content != tt.content || result.HasContent() != tt.hasContent {
+			t.Errorf("result %s content = %q, HasContent() = %v, want %q, %v", result.URL, result.Content, result.HasContent(), tt.content, tt.hasContent)
+		}
+		if _, ok := result.Metadata["markdown"]; ok != tt.hasMarkdown {
+			t.Errorf("result %s has markdown metadata = %v, want %v", result.URL, ok, tt.hasMarkdown)
+		}
+	}
+}
@@ -1,52 +1,69 @@
 package firecrawl
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
 )
 
+// engineName is the name the Firecrawl search adapter is registered under.
+const engineName = "firecrawl"
+
+// FirecrawlOptions holds the keys of the `config` section of the Firecrawl search engine.
+type FirecrawlOptions struct {
+	UserAgent string        `mapstructure:"user_agent" description:"User-Agent header sent to the API"`
+	Timeout   time.Duration `mapstructure:"timeout" description:"Request timeout, in seconds or as a duration string"`
+}
+
+// defaultFirecrawlOptions returns the options used for the keys that are not configured.
+func defaultFirecrawlOptions() FirecrawlOptions {
+	return FirecrawlOptions{
+		UserAgent: "Strato-SDK-Bot/1.0",
+		Timeout:   30 * time.Second,
+	}
+}
+
+// Validate implements config.ConfigValidator.
+func (o *FirecrawlOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return config.InvalidConfigValue("timeout", "must be positive, got %s", o.Timeout)
+	}
+	return nil
+}
+
 // FirecrawlAdapterCreator creates Firecrawl search adapter instances.
 type FirecrawlAdapterCreator struct{}
 
 // CreateAdapter creates a new Firecrawl search adapter instance.
 func (c *FirecrawlAdapterCreator) CreateAdapter(engineConfig types.EngineConfig) (search.SearchAdapter, error) {
-	firecrawlConfig := &FirecrawlConfig{}
-
-	firecrawlConfig.APIKey = engineConfig.APIKey
-	if firecrawlConfig.APIKey == "" {
-		return nil, fmt.Errorf("firecrawl engine is missing the required api_key parameter")
-	}
-
-	if engineConfig.BaseURL != "" {
-		firecrawlConfig.BaseURL = engineConfig.BaseURL
+	if engineConfig.APIKey == "" {
+		return nil, &config.ConfigError{Component: config.ComponentSearchEngine, Name: engineName, Key: "api_key", Err: config.ErrMissingConfigValue}
 	}
 
-	if engineConfig.Config != nil {
-		if userAgent, ok := engineConfig.Config["user_agent"].(string); ok {
-			firecrawlConfig.UserAgent = userAgent
-		}
-
-		if timeout, ok := engineConfig.Config["timeout"]; ok {
-			switch t := timeout.(type) {
-			case int:
-				firecrawlConfig.Timeout = time.Duration(t) * time.Second
-			case float64:
-				firecrawlConfig.Timeout = time.Duration(t) * time.Second
-			case string:
-				if duration, err := time.ParseDuration(t); err == nil {
-					firecrawlConfig.Timeout = duration
-				}
-			}
-		}
+	options := defaultFirecrawlOptions()
+	if err := config.DecodeAdapterConfig(config.ComponentSearchEngine, engineName, engineConfig.Config, &options); err != nil {
+		return nil, err
 	}
 
-	return NewFirecrawlAdapter(firecrawlConfig), nil
+	return NewFirecrawlAdapter(&FirecrawlConfig{
+		APIKey:    engineConfig.APIKey,
+		BaseURL:   engineConfig.BaseURL,
+		Timeout:   options.Timeout,
+		UserAgent: options.UserAgent,
+	}), nil
 }
 
 func init() {
 	creator := &FirecrawlAdapterCreator{}
-	search.RegisterAdapterCreator("firecrawl", creator.CreateAdapter)
+	search.RegisterAdapterCreator(engineName, creator.CreateAdapter)
+	search.RegisterAdapterDescriptor(engineName, search.AdapterDescriptor{
+		Description: "Firecrawl search API, returning the content of the result pages",
+		Capabilities: search.AdapterCapabilities{
+			Filters: []string{search.FilterSite, search.FilterFileType, search.FilterRegion, search.FilterTimeRange, search.FilterLang},
+			Content: true,
+		},
+		ConfigSchema: config.ConfigSchema(defaultFirecrawlOptions()),
+	})
 }
@@ -0,0 +1,211 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Result merger names.
+const (
+	// MergerRRF fuses results with reciprocal rank fusion.
+	MergerRRF = "rrf"
+	// MergerScore fuses results by summing the per-engine normalized scores.
+	MergerScore = "score"
+)
+
+// DefaultRRFK is the rank constant of reciprocal rank fusion. Larger values flatten
+// the difference between top-ranked and lower-ranked results.
+const DefaultRRFK = 60
+
+// EngineResults is the ranked result list returned by a single engine in a mixed search.
+type EngineResults struct {
+	// Engine is the engine that returned the results.
+	Engine SearchEngine
+	// Results are the results in the engine's ranking order.
+	Results []*SearchResultItem
+}
+
+// ResultMerger fuses the ranked result lists of several engines into a single list.
+// Implementations must be deterministic: the same input lists must always produce the same order.
+type ResultMerger interface {
+	// Merge fuses the result lists, given in engine configuration order, into a single ranked list.
+	// Results with the same URL key (Metadata[MetadataURLKey], or the URL) are merged into one result
+	// whose Metadata["engines"] lists every engine that returned it, and its fused score is stored
+	// in Metadata["fusion_score"].
+	Merge(lists []EngineResults) []*SearchResultItem
+}
+
+// NewResultMerger creates the result merger with the given name.
+// An empty name selects reciprocal rank fusion.
+//
+// Parameters:
+//   - name: The merger name, MergerRRF or MergerScore.
+//   - rrfK: The rank constant of reciprocal rank fusion; non-positive values use DefaultRRFK.
+//
+// Returns:
+//   - ResultMerger: The result merger.
+//   - error: An error if the name is unknown.
+func NewResultMerger(name string, rrfK int) (ResultMerger, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", MergerRRF:
+		return &RRFMerger{K: float64(rrfK)}, nil
+	case MergerScore:
+		return &ScoreMerger{}, nil
+	default:
+		return nil, fmt.Errorf("unknown result merger: %s", name)
+	}
+}
+
+// RRFMerger fuses results with reciprocal rank fusion: each engine contributes
+// weight / (K + rank) to a result, where rank is the 1-based position in its list
+// and weight is the domain credibility weight of the result, if any.
+// Results returned by several engines accumulate the contributions and move up.
+type RRFMerger struct {
+	// K is the rank constant; non-positive values use DefaultRRFK.
+	K float64
+}
+
+// Merge implements ResultMerger.
+func (m *RRFMerger) Merge(lists []EngineResults) []*SearchResultItem {
+	k := m.K
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+
+	return fuseResults(lists, func(list EngineResults) []float64 {
+		scores := make([]float64, len(list.Results))
+		for i := range list.Results {
+			scores[i] = 1 / (k + float64(i+1))
+		}
+		return scores
+	})
+}
+
+// ScoreMerger fuses results by summing their scores after min-max normalizing them per engine,
+// multiplied by the domain credibility weight of the result, if any.
+// Engines that do not report scores are scored by position instead.
+type ScoreMerger struct{}
+
+// Merge implements ResultMerger.
+func (m *ScoreMerger) Merge(lists []EngineResults) []*SearchResultItem {
+	return fuseResults(lists, func(list EngineResults) []float64 {
+		scores := make([]float64, len(list.Results))
+		if len(list.Results) == 0 {
+			return scores
+		}
+
+		// Fall back to positions when the engine does not report scores.
+		minScore, maxScore := list.Results[0].Score, list.Results[0].Score
+		for _, result := range list.Results {
+			minScore = min(minScore, result.Score)
+			maxScore = max(maxScore, result.Score)
+		}
+		if maxScore <= 0 {
+			for i := range list.Results {
+				scores[i] = 1 - float64(i)/float64(len(list.Results))
+			}
+			return scores
+		}
+
+		for i, result := range list.Results {
+			if maxScore == minScore {
+				scores[i] = 1
+				continue
+			}
+			scores[i] = (result.Score - minScore) / (maxScore - minScore)
+		}
+		return scores
+	})
+}
+
+// fusedResult accumulates the contributions of the engines to a single result.
+type fusedResult struct {
+	item     *SearchResultItem
+	engines  []string
+	score    float64
+	bestRank int
+	key      string
+}
+
+// fuseResults merges the result lists using the per-result scores computed by scoreList.
+// Results are keyed by URL key; the result from the first engine to return a URL is kept.
+// The fused list is ordered by score, then by best rank, then by URL, so it is deterministic.
+func fuseResults(lists []EngineResults, scoreList func(EngineResults) []float64) []*SearchResultItem {
+	fused := make(map[string]*fusedResult)
+	var order []*fusedResult
+
+	for _, list := range lists {
+		scores := scoreList(list)
+		for i, result := range list.Results {
+			key := fusionKey(result, list.Engine, i)
+			// This is the only place the domain weight applies to mixed results: the strategy
+			// filters each engine's list by the domain policy but keeps the engine's ranking.
+			score := scores[i] * resultDomainWeight(result)
+
+			entry, exists := fused[key]
+			if !exists {
+				entry = &fusedResult{item: result, bestRank: i + 1, key: key}
+				fused[key] = entry
+				order = append(order, entry)
+			}
+			if !containsString(entry.engines, string(list.Engine)) {
+				entry.engines = append(entry.engines, string(list.Engine))
+				entry.score += score
+			}
+			entry.bestRank = min(entry.bestRank, i+1)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].score != order[j].score {
+			return order[i].score > order[j].score
+		}
+		if order[i].bestRank != order[j].bestRank {
+			return order[i].bestRank < order[j].bestRank
+		}
+		return order[i].key < order[j].key
+	})
+
+	merged := make([]*SearchResultItem, len(order))
+	for i, entry := range order {
+		if entry.item.Metadata == nil {
+			entry.item.Metadata = make(map[string]interface{})
+		}
+		entry.item.Metadata["engines"] = entry.engines
+		entry.item.Metadata["fusion_score"] = entry.score
+		entry.item.Rank = i + 1
+		merged[i] = entry.item
+	}
+	return merged
+}
+
+// fusionKey returns the key under which results are merged: the canonical URL key recorded
+// by the strategy, the URL, or a per-engine position key for results without one, which are never merged.
+func fusionKey(result *SearchResultItem, engine SearchEngine, position int) string {
+	if key, ok := result.Metadata[MetadataURLKey].(string); ok && key != "" {
+		return key
+	}
+	if result.URL != "" {
+		return result.URL
+	}
+	return fmt.Sprintf("%s#%d", engine, position)
+}
+
+// resultDomainWeight returns the domain credibility weight recorded by the domain policy, or 1.
+func resultDomainWeight(result *SearchResultItem) float64 {
+	if weight, ok := result.Metadata[MetadataDomainWeight].(float64); ok {
+		return weight
+	}
+	return DefaultDomainWeight
+}
+
+// containsString reports whether the slice contains the value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
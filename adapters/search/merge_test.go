@@ -0,0 +1,165 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// resultURLs returns the URLs of the results, in order.
+func resultURLs(results []*SearchResultItem) []string {
+	urls := make([]string, len(results))
+	for i, result := range results {
+		urls[i] = result.URL
+	}
+	return urls
+}
+
+// engineResults builds the result list of an engine from URLs, with descending scores.
+func engineResults(engine SearchEngine, urls ...string) EngineResults {
+	list := EngineResults{Engine: engine}
+	for i, url := range urls {
+		list.Results = append(list.Results, &SearchResultItem{URL: url, Score: float64(len(urls) - i)})
+	}
+	return list
+}
+
+// weighted sets the domain weight of the result with the URL in every list.
+func weighted(lists []EngineResults, url string, weight float64) []EngineResults {
+	for _, list := range lists {
+		for _, result := range list.Results {
+			if result.URL == url {
+				result.Metadata = map[string]interface{}{MetadataDomainWeight: weight}
+			}
+		}
+	}
+	return lists
+}
+
+func TestFuseResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		merger ResultMerger
+		lists  func() []EngineResults
+		want   []string
+	}{
+		{
+			name:   "rrf single engine keeps its ranking",
+			merger: &RRFMerger{},
+			lists:  func() []EngineResults { return []EngineResults{engineResults("a", "u1", "u2", "u3")} },
+			want:   []string{"u1", "u2", "u3"},
+		},
+		{
+			name:   "rrf results of several engines move up",
+			merger: &RRFMerger{},
+			lists: func() []EngineResults {
+				return []EngineResults{engineResults("a", "u1", "u2", "u3"), engineResults("b", "u3", "u4")}
+			},
+			want: []string{"u3", "u1", "u2", "u4"},
+		},
+		{
+			name:   "rrf ties break by best rank, then URL",
+			merger: &RRFMerger{},
+			lists: func() []EngineResults {
+				return []EngineResults{engineResults("a", "u2", "u4"), engineResults("b", "u1", "u3")}
+			},
+			want: []string{"u1", "u2", "u3", "u4"},
+		},
+		{
+			name:   "rrf domain weight",
+			merger: &RRFMerger{},
+			lists: func() []EngineResults {
+				return weighted([]EngineResults{engineResults("a", "u1", "u2")}, "u2", 2)
+			},
+			want: []string{"u2", "u1"},
+		},
+		{
+			name:   "score fusion normalizes per engine",
+			merger: &ScoreMerger{},
+			lists: func() []EngineResults {
+				high := EngineResults{Engine: "a", Results: []*SearchResultItem{{URL: "u1", Score: 100}, {URL: "u2", Score: 50}, {URL: "u3", Score: 10}, {URL: "u5", Score: 0}}}
+				low := EngineResults{Engine: "b", Results: []*SearchResultItem{{URL: "u3", Score: 0.9}, {URL: "u4", Score: 0.1}}}
+				return []EngineResults{high, low}
+			},
+			want: []string{"u3", "u1", "u2", "u4", "u5"},
+		},
+		{
+			name:   "score fusion without scores uses positions",
+			merger: &ScoreMerger{},
+			lists: func() []EngineResults {
+				return []EngineResults{{Engine: "a", Results: []*SearchResultItem{{URL: "u1"}, {URL: "u2"}}}}
+			},
+			want: []string{"u1", "u2"},
+		},
+		{
+			name:   "score fusion domain weight",
+			merger: &ScoreMerger{},
+			lists: func() []EngineResults {
+				return weighted([]EngineResults{engineResults("a", "u1", "u2", "u3")}, "u1", 0.1)
+			},
+			want: []string{"u2", "u1", "u3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := tt.merger.Merge(tt.lists())
+			if got := resultURLs(merged); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %v, want %v", got, tt.want)
+			}
+			for i, result := range merged {
+				if result.Rank != i+1 {
+					t.Errorf("Rank of %s = %d, want %d", result.URL, result.Rank, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestFuseResultsMetadata(t *testing.T) {
+	a := engineResults("a", "u1", "u2")
+	b := engineResults("b", "u1", "u3")
+	// Results are merged by URL key, and results without a URL are never merged.
+	a.Results[1].Metadata = map[string]interface{}{MetadataURLKey: "same"}
+	b.Results[1].Metadata = map[string]interface{}{MetadataURLKey: "same"}
+	b.Results = append(b.Results, &SearchResultItem{Title: "no url"}, &SearchResultItem{Title: "no url either"})
+	first := a.Results[0]
+
+	merged := (&RRFMerger{K: 1}).Merge([]EngineResults{a, b})
+	if len(merged) != 4 {
+		t.Fatalf("Merge() returned %d results, want 4", len(merged))
+	}
+	if merged[0] != first {
+		t.Errorf("first result = %+v, want the result of the first engine to return the URL", merged[0])
+	}
+	if engines := merged[0].Metadata["engines"]; !reflect.DeepEqual(engines, []string{"a", "b"}) {
+		t.Errorf("engines = %v, want [a b]", engines)
+	}
+	if score := merged[0].Metadata["fusion_score"]; score != 1.0 {
+		t.Errorf("fusion_score = %v, want 1", score)
+	}
+	if merged[1].URL != "u2" || !reflect.DeepEqual(merged[1].Metadata["engines"], []string{"a", "b"}) {
+		t.Errorf("results with the same URL key were not merged: %+v", merged[1])
+	}
+}
+
+func TestNewResultMerger(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ResultMerger
+		wantErr bool
+	}{
+		{name: "", want: &RRFMerger{K: 10}},
+		{name: " RRF ", want: &RRFMerger{K: 10}},
+		{name: "score", want: &ScoreMerger{}},
+		{name: "borda", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NewResultMerger(tt.name, 10)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewResultMerger(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewResultMerger(%q) = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// engineRateLimiter returns the rate limiter configured for the engine, or nil, which never blocks,
+// if rate limiting is disabled or the engine is not configured. The limiter is shared by every
+// strategy and goroutine, so they draw from the same request budget.
+func engineRateLimiter(engine SearchEngine) *resilience.RateLimiter {
+	searchConfig := config.GetSearchConfig()
+	if searchConfig == nil {
+		return nil
+	}
+
+	rateLimit := searchConfig.Engines[string(engine)].RateLimit
+	if !rateLimit.Enabled || rateLimit.RequestsPerSecond <= 0 {
+		return nil
+	}
+	return resilience.SharedRateLimiter("search:"+string(engine), float64(rateLimit.RequestsPerSecond), rateLimit.BurstSize)
+}
+
+// waitForRateLimit waits until the engine's rate limit allows a request. Strategies call it before
+// every attempt, outside the attempt timeout and the circuit breaker, so that waiting for the
+// limiter neither fails attempts nor counts against the engine's health.
+func waitForRateLimit(ctx context.Context, engine SearchEngine) error {
+	if err := engineRateLimiter(engine).Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait for engine %s aborted: %w", engine, err)
+	}
+	return nil
+}
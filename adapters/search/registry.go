@@ -1,55 +1,215 @@
 package search
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
+// ErrEngineDisabled is returned when a search engine that was disabled at runtime is used.
+var ErrEngineDisabled = errors.New("search engine is disabled")
+
 // AdapterFactory defines the function signature for a search adapter factory.
 type AdapterFactory func() (SearchAdapter, error)
 
-// SearchRegistry is a registry for search adapters.
+// registryEntry is a registered factory together with the revision it was registered at.
+type registryEntry struct {
+	factory  AdapterFactory
+	revision uint64
+}
+
+// SearchRegistry is a concurrency-safe registry for search adapters.
+// Engines can be registered, replaced, unregistered and disabled at runtime; strategies
+// pick up these changes on their next search.
 type SearchRegistry struct {
-	mu        sync.RWMutex
-	factories map[SearchEngine]AdapterFactory
+	mu       sync.RWMutex
+	entries  map[SearchEngine]registryEntry
+	disabled map[SearchEngine]bool
+	revision uint64
 }
 
 // NewSearchRegistry creates a new search adapter registry.
 func NewSearchRegistry() *SearchRegistry {
 	return &SearchRegistry{
-		factories: make(map[SearchEngine]AdapterFactory),
+		entries:  make(map[SearchEngine]registryEntry),
+		disabled: make(map[SearchEngine]bool),
 	}
 }
 
-// Register registers a search adapter factory.
+// Register registers a search adapter factory, replacing the factory already registered for the engine.
 func (r *SearchRegistry) Register(engine SearchEngine, factory AdapterFactory) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.factories[engine] = factory
+	r.revision++
+	r.entries[engine] = registryEntry{factory: factory, revision: r.revision}
+}
+
+// Unregister removes the factory of the engine and reports whether one was registered.
+func (r *SearchRegistry) Unregister(engine SearchEngine) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.entries[engine]
+	delete(r.entries, engine)
+	return exists
 }
 
 // Create creates a search adapter instance.
 func (r *SearchRegistry) Create(engine SearchEngine) (SearchAdapter, error) {
+	adapter, _, err := r.create(engine)
+	return adapter, err
+}
+
+// create creates a search adapter instance and returns the revision of the factory that created it.
+func (r *SearchRegistry) create(engine SearchEngine) (SearchAdapter, uint64, error) {
 	r.mu.RLock()
-	factory, exists := r.factories[engine]
+	entry, exists := r.entries[engine]
+	disabled := r.disabled[engine]
 	r.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("unregistered search engine: %s", engine)
+		return nil, 0, fmt.Errorf("unregistered search engine: %s", engine)
+	}
+	if disabled {
+		return nil, 0, fmt.Errorf("%w: %s", ErrEngineDisabled, engine)
 	}
 
-	return factory()
+	adapter, err := entry.factory()
+	return adapter, entry.revision, err
+}
+
+// List returns the registered engines, sorted by name.
+func (r *SearchRegistry) List() []SearchEngine {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	engines := make([]SearchEngine, 0, len(r.entries))
+	for engine := range r.entries {
+		engines = append(engines, engine)
+	}
+	sort.Slice(engines, func(i, j int) bool { return engines[i] < engines[j] })
+	return engines
+}
+
+// IsRegistered reports whether a factory is registered for the engine.
+func (r *SearchRegistry) IsRegistered(engine SearchEngine) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.entries[engine]
+	return exists
+}
+
+// SetEnabled enables or disables the engine. A disabled engine stays registered but is skipped
+// by the strategies until it is enabled again; the setting survives re-registration.
+func (r *SearchRegistry) SetEnabled(engine SearchEngine, enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if enabled {
+		delete(r.disabled, engine)
+	} else {
+		r.disabled[engine] = true
+	}
+}
+
+// IsEnabled reports whether the engine has not been disabled.
+func (r *SearchRegistry) IsEnabled(engine SearchEngine) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.disabled[engine]
+}
+
+// Revision returns the revision of the factory registered for the engine, or zero if there is none.
+// The revision changes whenever the factory is replaced, so adapters created by an older factory can be discarded.
+func (r *SearchRegistry) Revision(engine SearchEngine) uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.entries[engine].revision
+}
+
+// AdapterInfo describes a search engine known to the SDK.
+type AdapterInfo struct {
+	// Engine is the name of the engine.
+	Engine SearchEngine `json:"engine"`
+
+	// Registered reports whether the engine is registered, i.e. it can be used by the strategies.
+	Registered bool `json:"registered"`
+
+	// Enabled reports whether the engine has not been disabled at runtime.
+	Enabled bool `json:"enabled"`
+
+	// AdapterDescriptor is the description the adapter package registered, if any.
+	AdapterDescriptor
 }
 
 // globalRegistry is the global instance of the search adapter registry.
 var globalRegistry = NewSearchRegistry()
 
-// RegisterSearchAdapter registers a search adapter globally.
+// RegisterSearchAdapter registers a search adapter globally, replacing the adapter already registered for the engine.
 func RegisterSearchAdapter(engine SearchEngine, factory AdapterFactory) {
 	globalRegistry.Register(engine, factory)
 }
 
+// UnregisterSearchAdapter removes a search adapter from the global registry and reports whether it was registered.
+func UnregisterSearchAdapter(engine SearchEngine) bool {
+	return globalRegistry.Unregister(engine)
+}
+
 // CreateSearchAdapter creates a search adapter instance from the global registry.
 func CreateSearchAdapter(engine SearchEngine) (SearchAdapter, error) {
 	return globalRegistry.Create(engine)
 }
+
+// EnableSearchAdapter enables a search engine that was disabled at runtime.
+func EnableSearchAdapter(engine SearchEngine) {
+	globalRegistry.SetEnabled(engine, true)
+}
+
+// DisableSearchAdapter disables a search engine at runtime. The strategies skip the engine
+// from their next search on, without a restart, until it is enabled again.
+func DisableSearchAdapter(engine SearchEngine) {
+	globalRegistry.SetEnabled(engine, false)
+}
+
+// IsSearchAdapterEnabled reports whether a search engine has not been disabled at runtime.
+func IsSearchAdapterEnabled(engine SearchEngine) bool {
+	return globalRegistry.IsEnabled(engine)
+}
+
+// ListSearchAdapters describes every search engine that is registered or has an adapter creator, sorted by name.
+func ListSearchAdapters() []AdapterInfo {
+	names := make(map[SearchEngine]bool)
+	for _, engine := range globalRegistry.List() {
+		names[engine] = true
+	}
+	for _, name := range adapterCreatorNames() {
+		names[SearchEngine(name)] = true
+	}
+
+	infos := make([]AdapterInfo, 0, len(names))
+	for engine := range names {
+		info, _ := DescribeSearchAdapter(engine)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Engine < infos[j].Engine })
+	return infos
+}
+
+// DescribeSearchAdapter describes a search engine: whether it is registered and enabled,
+// and the capabilities and configuration schema its adapter declares.
+//
+// Parameters:
+//   - engine: The name of the engine.
+//
+// Returns:
+//   - AdapterInfo: The description of the engine.
+//   - bool: Whether the engine is known, i.e. registered or backed by an adapter creator.
+func DescribeSearchAdapter(engine SearchEngine) (AdapterInfo, bool) {
+	descriptor, described := getAdapterDescriptor(string(engine))
+	registered := globalRegistry.IsRegistered(engine)
+	return AdapterInfo{
+		Engine:            engine,
+		Registered:        registered,
+		Enabled:           globalRegistry.IsEnabled(engine),
+		AdapterDescriptor: descriptor,
+	}, registered || described || hasAdapterCreator(string(engine))
+}
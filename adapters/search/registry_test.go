@@ -0,0 +1,208 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// newFakeFactory returns a factory that creates the fake adapter.
+func newFakeFactory(adapter *fakeAdapter) AdapterFactory {
+	return func() (SearchAdapter, error) { return adapter, nil }
+}
+
+func TestSearchRegistryRevision(t *testing.T) {
+	registry := NewSearchRegistry()
+	if got := registry.Revision("a"); got != 0 {
+		t.Errorf("Revision() of an unregistered engine = %d, want 0", got)
+	}
+
+	registry.Register("a", newFakeFactory(answering(0)))
+	first := registry.Revision("a")
+	registry.Register("b", newFakeFactory(answering(0)))
+	if got := registry.Revision("a"); got != first {
+		t.Errorf("Revision() after registering another engine = %d, want %d", got, first)
+	}
+
+	registry.Register("a", newFakeFactory(answering(0)))
+	second := registry.Revision("a")
+	if second <= first {
+		t.Errorf("Revision() after re-registering = %d, want more than %d", second, first)
+	}
+	if _, revision, err := registry.create("a"); err != nil || revision != second {
+		t.Errorf("create() = %d, %v, want %d, nil", revision, err, second)
+	}
+
+	if !registry.Unregister("a") {
+		t.Error("Unregister() = false, want true")
+	}
+	if registry.Unregister("a") {
+		t.Error("Unregister() of an unregistered engine = true, want false")
+	}
+	if got := registry.Revision("a"); got != 0 {
+		t.Errorf("Revision() after unregistering = %d, want 0", got)
+	}
+	if _, err := registry.Create("a"); err == nil {
+		t.Error("Create() of an unregistered engine error = nil, want an error")
+	}
+
+	registry.Register("a", newFakeFactory(answering(0)))
+	if got := registry.Revision("a"); got <= second {
+		t.Errorf("Revision() after registering again = %d, want more than %d", got, second)
+	}
+}
+
+func TestSearchRegistrySetEnabled(t *testing.T) {
+	registry := NewSearchRegistry()
+	registry.Register("a", newFakeFactory(answering(0)))
+
+	registry.SetEnabled("a", false)
+	if registry.IsEnabled("a") {
+		t.Error("IsEnabled() after disabling = true, want false")
+	}
+	if _, err := registry.Create("a"); !errors.Is(err, ErrEngineDisabled) {
+		t.Errorf("Create() of a disabled engine error = %v, want %v", err, ErrEngineDisabled)
+	}
+
+	registry.Register("a", newFakeFactory(answering(0)))
+	if registry.IsEnabled("a") {
+		t.Error("IsEnabled() after re-registering a disabled engine = true, want false")
+	}
+
+	registry.SetEnabled("a", true)
+	if !registry.IsEnabled("a") {
+		t.Error("IsEnabled() after enabling = false, want true")
+	}
+	if _, err := registry.Create("a"); err != nil {
+		t.Errorf("Create() after enabling error = %v", err)
+	}
+}
+
+func TestSearchRegistryConcurrentAccess(t *testing.T) {
+	registry := NewSearchRegistry()
+	engines := []SearchEngine{"a", "b", "c"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				engine := engines[(i+j)%len(engines)]
+				switch (i + j) % 6 {
+				case 0:
+					registry.Register(engine, newFakeFactory(answering(0)))
+				case 1:
+					registry.Unregister(engine)
+				case 2:
+					registry.SetEnabled(engine, j%2 == 0)
+				case 3:
+					adapter, err := registry.Create(engine)
+					if err == nil && adapter == nil {
+						t.Errorf("Create(%s) = nil, nil", engine)
+					}
+				case 4:
+					registry.List()
+				case 5:
+					registry.Revision(engine)
+					registry.IsRegistered(engine)
+					registry.IsEnabled(engine)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, engine := range engines {
+		registry.Register(engine, newFakeFactory(answering(0)))
+		registry.SetEnabled(engine, true)
+		if _, err := registry.Create(engine); err != nil {
+			t.Errorf("Create(%s) after concurrent changes error = %v", engine, err)
+		}
+	}
+	if got := len(registry.List()); got != len(engines) {
+		t.Errorf("List() has %d engines, want %d", got, len(engines))
+	}
+}
+
+func TestStrategyPicksUpRegistryChanges(t *testing.T) {
+	first := answering(0, "https://first.example/1")
+	engines := registerFakeAdapters(t, first)
+	strategy := newTestStrategy(engines)
+	execute := func() (*SearchResponse, error) {
+		return strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if got := first.calls.Load(); got != 2 {
+		t.Errorf("first adapter calls = %d, want 2", got)
+	}
+
+	second := answering(0, "https://second.example/1")
+	RegisterSearchAdapter(engines[0], newFakeFactory(second))
+	response, err := execute()
+	if err != nil {
+		t.Fatalf("Execute() after re-registering error = %v", err)
+	}
+	if got := resultURLs(response.Results); len(got) != 1 || got[0] != "https://second.example/1" {
+		t.Errorf("Execute() after re-registering results = %v, want the new adapter's", got)
+	}
+	if got := first.calls.Load(); got != 2 {
+		t.Errorf("first adapter calls after re-registering = %d, want 2", got)
+	}
+
+	UnregisterSearchAdapter(engines[0])
+	if _, err := execute(); err == nil {
+		t.Error("Execute() after unregistering error = nil, want an error")
+	}
+}
+
+func TestStrategiesSkipDisabledEngines(t *testing.T) {
+	tests := []struct {
+		name        string
+		newStrategy func(engines []SearchEngine) *DefaultSearchStrategy
+	}{
+		{"fallback", newTestStrategy},
+		{"mixed", newMixedTestStrategy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disabled := answering(0, "https://disabled.example/1")
+			enabled := answering(0, "https://enabled.example/1")
+			engines := registerFakeAdapters(t, disabled, enabled)
+			strategy := tt.newStrategy(engines)
+
+			DisableSearchAdapter(engines[0])
+			response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := resultURLs(response.Results); len(got) != 1 || got[0] != "https://enabled.example/1" {
+				t.Errorf("Execute() results = %v, want only the enabled engine's", got)
+			}
+			if got := disabled.calls.Load(); got != 0 {
+				t.Errorf("disabled adapter calls = %d, want 0", got)
+			}
+
+			DisableSearchAdapter(engines[1])
+			if _, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"}); err == nil {
+				t.Error("Execute() with every engine disabled error = nil, want an error")
+			}
+
+			EnableSearchAdapter(engines[0])
+			response, err = strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+			if err != nil {
+				t.Fatalf("Execute() after enabling error = %v", err)
+			}
+			if got := resultURLs(response.Results); len(got) != 1 || got[0] != "https://disabled.example/1" {
+				t.Errorf("Execute() after enabling results = %v, want the re-enabled engine's", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/anboat/strato-sdk/config/types"
+	"github.com/anboat/strato-sdk/pkg/logging"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// Query classes used for intent-based routing.
+const (
+	QueryClassNews     = "news"
+	QueryClassSocial   = "social"
+	QueryClassAcademic = "academic"
+	QueryClassCode     = "code"
+	QueryClassGeneral  = "general"
+)
+
+// QueryClasses lists the query classes. When keyword scores tie, earlier classes win.
+var QueryClasses = []string{QueryClassNews, QueryClassSocial, QueryClassAcademic, QueryClassCode, QueryClassGeneral}
+
+// Query classifier names.
+const (
+	// ClassifierKeyword classifies queries with keyword rules.
+	ClassifierKeyword = "keyword"
+	// ClassifierLLM classifies queries with a language model. It must be registered with
+	// RegisterQueryClassifier, since this package does not depend on any model.
+	ClassifierLLM = "llm"
+)
+
+// QueryClassifier assigns a search query to one of the QueryClasses.
+type QueryClassifier interface {
+	// Classify returns the class of the query.
+	Classify(ctx context.Context, query string) (string, error)
+}
+
+// queryClassifiers holds the query classifiers registered by name.
+var queryClassifiers = struct {
+	mu          sync.RWMutex
+	classifiers map[string]QueryClassifier
+}{classifiers: make(map[string]QueryClassifier)}
+
+// RegisterQueryClassifier registers a query classifier under a name, so that the routing
+// configuration can select it. A classifier registered under an existing name replaces it.
+func RegisterQueryClassifier(name string, classifier QueryClassifier) {
+	queryClassifiers.mu.Lock()
+	defer queryClassifiers.mu.Unlock()
+	queryClassifiers.classifiers[name] = classifier
+}
+
+// getQueryClassifier returns the query classifier registered under the name.
+func getQueryClassifier(name string) (QueryClassifier, bool) {
+	queryClassifiers.mu.RLock()
+	defer queryClassifiers.mu.RUnlock()
+	classifier, ok := queryClassifiers.classifiers[name]
+	return classifier, ok
+}
+
+// defaultClassKeywords are the built-in keywords of each query class.
+var defaultClassKeywords = map[string][]string{
+	QueryClassNews: {
+		"news", "breaking", "latest", "headline", "headlines", "today", "this week", "announced", "announcement",
+		"election", "press release", "新闻", "最新", "今日", "今天", "快讯", "发布会",
+	},
+	QueryClassSocial: {
+		"twitter", "tweet", "tweets", "x.com", "reddit", "sentiment", "opinion", "opinions", "reaction", "reactions",
+		"trending", "viral", "public opinion", "what people think", "舆论", "舆情", "网友", "热搜", "微博", "口碑",
+	},
+	QueryClassAcademic: {
+		"paper", "papers", "arxiv", "journal", "peer-reviewed", "peer reviewed", "study", "studies", "citation",
+		"citations", "doi", "thesis", "meta-analysis", "survey of", "论文", "期刊", "学术", "文献", "综述",
+	},
+	QueryClassCode: {
+		"code", "github", "api", "sdk", "library", "function", "stack trace", "exception", "compile", "compiler",
+		"golang", "python", "javascript", "typescript", "rust", "java", "npm", "bug", "代码", "报错", "编程", "源码",
+	},
+}
+
+// KeywordClassifier classifies queries by counting the keywords of each class that occur in the query.
+// Queries without any keyword are classified as general.
+type KeywordClassifier struct {
+	keywords map[string][]string
+}
+
+// NewKeywordClassifier creates a keyword classifier with the built-in keywords, extended by
+// the given extra keywords per class.
+//
+// Parameters:
+//   - extra: Additional keywords per query class; may be nil.
+//
+// Returns:
+//   - *KeywordClassifier: The keyword classifier.
+func NewKeywordClassifier(extra map[string][]string) *KeywordClassifier {
+	keywords := make(map[string][]string, len(defaultClassKeywords)+len(extra))
+	for class, words := range defaultClassKeywords {
+		keywords[class] = append([]string(nil), words...)
+	}
+	for class, words := range extra {
+		for _, word := range words {
+			if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+				keywords[class] = append(keywords[class], word)
+			}
+		}
+	}
+	return &KeywordClassifier{keywords: keywords}
+}
+
+// NewKeywordClassifierFromConfig creates a keyword classifier with the built-in keywords, extended
+// by the keywords configured for each route. Other classifiers, such as the LLM classifier, use it
+// as their fallback, so that the configured keywords apply when they fail.
+//
+// Parameters:
+//   - routingConfig: The search routing configuration.
+//
+// Returns:
+//   - *KeywordClassifier: The keyword classifier.
+func NewKeywordClassifierFromConfig(routingConfig *types.SearchRoutingConfig) *KeywordClassifier {
+	extra := make(map[string][]string)
+	for _, routeConfig := range routingConfig.Routes {
+		class := strings.ToLower(strings.TrimSpace(routeConfig.Class))
+		extra[class] = append(extra[class], routeConfig.Keywords...)
+	}
+	return NewKeywordClassifier(extra)
+}
+
+// Classify implements QueryClassifier.
+func (c *KeywordClassifier) Classify(ctx context.Context, query string) (string, error) {
+	query = strings.ToLower(query)
+
+	bestClass, bestScore := QueryClassGeneral, 0
+	for _, class := range QueryClasses {
+		score := 0
+		for _, keyword := range c.keywords[class] {
+			if containsKeyword(query, keyword) {
+				score++
+			}
+		}
+		if score > bestScore {
+			bestClass, bestScore = class, score
+		}
+	}
+	return bestClass, nil
+}
+
+// containsKeyword reports whether the keyword occurs in the query. Keywords that start or end
+// with a letter or digit must do so at a word boundary, so "api" does not match "rapid";
+// CJK keywords, which are not separated by spaces, match anywhere.
+func containsKeyword(query, keyword string) bool {
+	for start := 0; start < len(query); {
+		i := strings.Index(query[start:], keyword)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(keyword)
+		if wordBoundary(query, i, keyword, true) && wordBoundary(query, end, keyword, false) {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
+
+// wordBoundary reports whether position pos of the query is a valid start (before is true)
+// or end of the keyword.
+func wordBoundary(query string, pos int, keyword string, before bool) bool {
+	var edge, neighbor rune
+	if before {
+		if pos == 0 {
+			return true
+		}
+		edge, _ = utf8.DecodeRuneInString(keyword)
+		neighbor, _ = utf8.DecodeLastRuneInString(query[:pos])
+	} else {
+		if pos == len(query) {
+			return true
+		}
+		edge, _ = utf8.DecodeLastRuneInString(keyword)
+		neighbor, _ = utf8.DecodeRuneInString(query[pos:])
+	}
+	if !isWordRune(edge) || unicode.Is(unicode.Han, edge) {
+		return true
+	}
+	return !isWordRune(neighbor) || unicode.Is(unicode.Han, neighbor)
+}
+
+// isWordRune reports whether r is part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Route sends the queries of a class to a set of engines.
+type Route struct {
+	// Class is the query class the route applies to.
+	Class string
+	// Engines are the engines used for the class, in fallback order; empty uses the default strategy.
+	Engines []SearchEngine
+	// Mixed, if true, searches all engines concurrently and fuses their results instead of falling back.
+	Mixed bool
+	// EngineParams are added to the request's engine parameters, e.g., SearXNG "categories".
+	// Parameters set on the request take precedence.
+	EngineParams map[string]interface{}
+}
+
+// RoutingSearchStrategy classifies each query and searches the engines of the route for its class.
+// Queries whose class has no route, or whose route fails, are searched with the default strategy.
+// The class of the query is recorded in SearchResponse.Route.
+type RoutingSearchStrategy struct {
+	classifier QueryClassifier
+	routes     map[string]Route
+	strategies map[string]SearchStrategy
+	fallback   SearchStrategy
+}
+
+// NewRoutingSearchStrategy creates a routing search strategy. The per-route strategies share one
+// circuit breaker per engine with each other and, if it is a *DefaultSearchStrategy, with the
+// fallback strategy, so that an engine that fails for one route is skipped by the others too.
+//
+// Parameters:
+//   - base: The strategy configuration the per-route strategies are derived from; its engine lists are replaced by the route's.
+//   - classifier: The query classifier.
+//   - routes: The routes per query class.
+//   - fallback: The strategy used for queries without a route and when a route fails.
+//
+// Returns:
+//   - *RoutingSearchStrategy: The routing search strategy.
+func NewRoutingSearchStrategy(base *SearchStrategyConfig, classifier QueryClassifier, routes []Route, fallback SearchStrategy) *RoutingSearchStrategy {
+	if base == nil {
+		base = &SearchStrategyConfig{}
+	}
+
+	s := &RoutingSearchStrategy{
+		classifier: classifier,
+		routes:     make(map[string]Route, len(routes)),
+		strategies: make(map[string]SearchStrategy, len(routes)),
+		fallback:   fallback,
+	}
+
+	breakers := newEngineBreakers(base.CircuitBreaker)
+	if defaultStrategy, ok := fallback.(*DefaultSearchStrategy); ok {
+		breakers = defaultStrategy.breakers
+	}
+
+	for _, route := range routes {
+		s.routes[route.Class] = route
+		if len(route.Engines) == 0 {
+			continue
+		}
+
+		routeConfig := *base
+		routeConfig.DefaultEngine = route.Engines[0]
+		routeConfig.DefaultFallbackOrder = route.Engines
+		routeConfig.MixedEngines = nil
+		if route.Mixed {
+			routeConfig.MixedEngines = route.Engines
+		}
+		strategy := NewDefaultSearchStrategy(&routeConfig)
+		strategy.breakers = breakers
+		s.strategies[route.Class] = strategy
+	}
+
+	return s
+}
+
+// Execute classifies the query and searches with the strategy of its route.
+func (s *RoutingSearchStrategy) Execute(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+	class, strategy, routedRequest := s.route(ctx, request)
+	if strategy != nil {
+		response, err := strategy.Execute(ctx, routedRequest)
+		if err == nil {
+			response.Route = class
+			return response, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		logging.Warnf("Search route %s failed, using the default strategy: %v", class, err)
+	}
+
+	response, err := s.fallback.Execute(ctx, routedRequest)
+	if err != nil {
+		return nil, fmt.Errorf("search route %s failed: %w", class, err)
+	}
+	response.Route = class
+	return response, nil
+}
+
+// ExecuteStream classifies the query and streams the search of its route, recording the class
+// on every batch. Unlike Execute, it does not fall back to the default strategy when the route fails.
+func (s *RoutingSearchStrategy) ExecuteStream(ctx context.Context, request *SearchRequest) (<-chan *SearchBatch, error) {
+	class, strategy, routedRequest := s.route(ctx, request)
+	if strategy == nil {
+		strategy = s.fallback
+	}
+
+	batches, err := StreamSearch(ctx, strategy, routedRequest)
+	if err != nil {
+		return nil, fmt.Errorf("search route %s failed: %w", class, err)
+	}
+
+	routed := make(chan *SearchBatch, cap(batches))
+	go func() {
+		defer close(routed)
+		for batch := range batches {
+			if batch.Response != nil {
+				batch.Response.Route = class
+			}
+			routed <- batch
+		}
+	}()
+	return routed, nil
+}
+
+// route classifies the query and returns its class, the strategy of its route (nil if the class
+// has no engines of its own) and the request with the engine parameters of the route.
+func (s *RoutingSearchStrategy) route(ctx context.Context, request *SearchRequest) (string, SearchStrategy, *SearchRequest) {
+	class, err := s.classifier.Classify(ctx, request.Query)
+	if err != nil {
+		logging.Warnf("Failed to classify search query %q, using the default route: %v", request.Query, err)
+		class = QueryClassGeneral
+	}
+
+	routedRequest := request
+	if route, ok := s.routes[class]; ok && len(route.EngineParams) > 0 {
+		routedRequest = withEngineParams(request, route.EngineParams)
+	}
+	return class, s.strategies[class], routedRequest
+}
+
+// Health returns the health of every engine used by the routes and the default strategy.
+// An engine used by several strategies reports the health tracked by the one that called it most,
+// which is the same for strategies that share their circuit breakers.
+func (s *RoutingSearchStrategy) Health() map[SearchEngine]resilience.Health {
+	health := make(map[SearchEngine]resilience.Health)
+	strategies := []SearchStrategy{s.fallback}
+	for _, class := range QueryClasses {
+		if strategy, ok := s.strategies[class]; ok {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	for _, strategy := range strategies {
+		reporter, ok := strategy.(interface {
+			Health() map[SearchEngine]resilience.Health
+		})
+		if !ok {
+			continue
+		}
+		for engine, engineHealth := range reporter.Health() {
+			if existing, ok := health[engine]; !ok || engineHealth.Requests > existing.Requests {
+				health[engine] = engineHealth
+			}
+		}
+	}
+	return health
+}
+
+// newRoutingStrategyFromConfig creates a routing search strategy from the routing configuration.
+// Engines that are not enabled are dropped from the routes.
+func newRoutingStrategyFromConfig(routingConfig *types.SearchRoutingConfig, engines map[string]types.EngineConfig, base *SearchStrategyConfig, fallback SearchStrategy) (*RoutingSearchStrategy, error) {
+	routes := make([]Route, 0, len(routingConfig.Routes))
+	for _, routeConfig := range routingConfig.Routes {
+		class := strings.ToLower(strings.TrimSpace(routeConfig.Class))
+		if !containsString(QueryClasses, class) {
+			return nil, fmt.Errorf("unknown search route class: %s", routeConfig.Class)
+		}
+
+		route := Route{Class: class, Mixed: routeConfig.Mixed, EngineParams: routeConfig.EngineParams}
+		for _, engineName := range routeConfig.Engines {
+			if engineConfig, exists := engines[engineName]; exists && engineConfig.Enabled {
+				route.Engines = append(route.Engines, SearchEngine(engineName))
+			}
+		}
+		routes = append(routes, route)
+	}
+
+	var classifier QueryClassifier
+	switch name := strings.ToLower(strings.TrimSpace(routingConfig.Classifier)); name {
+	case "", ClassifierKeyword:
+		classifier = NewKeywordClassifierFromConfig(routingConfig)
+	default:
+		registered, ok := getQueryClassifier(name)
+		if !ok {
+			return nil, fmt.Errorf("query classifier %s is not registered", routingConfig.Classifier)
+		}
+		classifier = registered
+	}
+
+	return NewRoutingSearchStrategy(base, classifier, routes, fallback), nil
+}
+
+// withEngineParams returns a copy of the request whose engine parameters include the given
+// parameters; parameters already set on the request are kept.
+func withEngineParams(request *SearchRequest, params map[string]interface{}) *SearchRequest {
+	routed := *request
+	routed.EngineParams = make(map[string]interface{}, len(params)+len(request.EngineParams))
+	for key, value := range params {
+		routed.EngineParams[key] = value
+	}
+	for key, value := range request.EngineParams {
+		routed.EngineParams[key] = value
+	}
+	return &routed
+}
@@ -0,0 +1,264 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+// classifierFunc is a query classifier implemented by a function.
+type classifierFunc func(ctx context.Context, query string) (string, error)
+
+// Classify implements QueryClassifier.
+func (f classifierFunc) Classify(ctx context.Context, query string) (string, error) {
+	return f(ctx, query)
+}
+
+// classifyAs returns a classifier that classifies every query as the class.
+func classifyAs(class string) QueryClassifier {
+	return classifierFunc(func(ctx context.Context, query string) (string, error) { return class, nil })
+}
+
+func TestContainsKeyword(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		keyword string
+		want    bool
+	}{
+		{"whole word", "rest api docs", "api", true},
+		{"at start", "api docs", "api", true},
+		{"at end", "rest api", "api", true},
+		{"inside word", "rapid prototyping", "api", false},
+		{"word prefix", "apis", "api", false},
+		{"later occurrence", "rapid api", "api", true},
+		{"punctuation", "which api?", "api", true},
+		{"hyphenated", "api-first design", "api", true},
+		{"digit neighbor", "api2 docs", "api", false},
+		{"multiword", "show the stack trace please", "stack trace", true},
+		{"multiword inside words", "haystack traces", "stack trace", false},
+		{"non-word edge", "peer-reviewed study", "peer-reviewed", true},
+		{"domain keyword", "posts on x.com today", "x.com", true},
+		{"cjk anywhere", "最新的ai新闻报道", "新闻", true},
+		{"cjk next to latin", "ai新闻", "新闻", true},
+		{"latin next to cjk", "用python写", "python", true},
+		{"missing", "weather tomorrow", "news", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsKeyword(tt.query, tt.keyword); got != tt.want {
+				t.Errorf("containsKeyword(%q, %q) = %v, want %v", tt.query, tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordClassifierClassify(t *testing.T) {
+	classifier := NewKeywordClassifier(map[string][]string{QueryClassCode: {" Kubernetes ", ""}})
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no keywords", "weather tomorrow", QueryClassGeneral},
+		{"single class", "Latest headlines", QueryClassNews},
+		{"highest score wins", "github python api example in the news", QueryClassCode},
+		{"tie news before code", "breaking github", QueryClassNews},
+		{"tie social before academic", "reddit paper", QueryClassSocial},
+		{"tie academic before code", "arxiv python", QueryClassAcademic},
+		{"word boundary", "rapid prototyping", QueryClassGeneral},
+		{"extra keyword", "kubernetes operators", QueryClassCode},
+		{"cjk", "最新新闻", QueryClassNews},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := classifier.Classify(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Classify() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Classify(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewKeywordClassifierFromConfig(t *testing.T) {
+	classifier := NewKeywordClassifierFromConfig(&types.SearchRoutingConfig{
+		Routes: []types.SearchRouteConfig{
+			{Class: " Academic ", Keywords: []string{"preprint"}},
+			{Class: "social", Keywords: []string{"mastodon"}},
+		},
+	})
+
+	tests := map[string]string{
+		"new preprint on llms":  QueryClassAcademic,
+		"mastodon instances":    QueryClassSocial,
+		"github rate limits":    QueryClassCode,
+		"best hiking in the uk": QueryClassGeneral,
+	}
+	for query, want := range tests {
+		if got, _ := classifier.Classify(context.Background(), query); got != want {
+			t.Errorf("Classify(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestRoutingSearchStrategyExecute(t *testing.T) {
+	errEngine := errors.New("engine failed")
+
+	t.Run("route engines", func(t *testing.T) {
+		engines := registerFakeAdapters(t, answering(0, "https://news.example/1"), answering(0, "https://default.example/1"))
+		strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassNews),
+			[]Route{{Class: QueryClassNews, Engines: engines[:1]}}, newTestStrategy(engines[1:]))
+
+		response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if got := resultURLs(response.Results); len(got) != 1 || got[0] != "https://news.example/1" {
+			t.Errorf("Execute() results = %v, want the news engine's", got)
+		}
+		if response.Route != QueryClassNews {
+			t.Errorf("Execute() route = %v, want %v", response.Route, QueryClassNews)
+		}
+	})
+
+	t.Run("failing route falls back", func(t *testing.T) {
+		routeEngine := failing(0, errEngine)
+		engines := registerFakeAdapters(t, routeEngine, answering(0, "https://default.example/1"))
+		strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassCode),
+			[]Route{{Class: QueryClassCode, Engines: engines[:1]}}, newTestStrategy(engines[1:]))
+
+		response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if got := resultURLs(response.Results); len(got) != 1 || got[0] != "https://default.example/1" {
+			t.Errorf("Execute() results = %v, want the default engine's", got)
+		}
+		if response.Route != QueryClassCode {
+			t.Errorf("Execute() route = %v, want %v", response.Route, QueryClassCode)
+		}
+		if routeEngine.calls.Load() == 0 {
+			t.Error("Execute() did not try the route engine")
+		}
+	})
+
+	t.Run("class without route", func(t *testing.T) {
+		routeEngine := answering(0, "https://news.example/1")
+		engines := registerFakeAdapters(t, routeEngine, answering(0, "https://default.example/1"))
+		strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassAcademic),
+			[]Route{{Class: QueryClassNews, Engines: engines[:1]}}, newTestStrategy(engines[1:]))
+
+		response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if response.Route != QueryClassAcademic {
+			t.Errorf("Execute() route = %v, want %v", response.Route, QueryClassAcademic)
+		}
+		if routeEngine.calls.Load() != 0 {
+			t.Error("Execute() searched the engine of another route")
+		}
+	})
+
+	t.Run("classifier error", func(t *testing.T) {
+		engines := registerFakeAdapters(t, answering(0, "https://default.example/1"))
+		classifier := classifierFunc(func(ctx context.Context, query string) (string, error) {
+			return QueryClassNews, errors.New("classifier failed")
+		})
+		strategy := NewRoutingSearchStrategy(nil, classifier, nil, newTestStrategy(engines))
+
+		response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if response.Route != QueryClassGeneral {
+			t.Errorf("Execute() route = %v, want %v", response.Route, QueryClassGeneral)
+		}
+	})
+
+	t.Run("route and fallback fail", func(t *testing.T) {
+		engines := registerFakeAdapters(t, failing(0, errEngine), failing(0, errEngine))
+		strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassNews),
+			[]Route{{Class: QueryClassNews, Engines: engines[:1]}}, newTestStrategy(engines[1:]))
+
+		if _, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"}); err == nil {
+			t.Error("Execute() error = nil, want an error")
+		}
+	})
+
+	t.Run("engine params", func(t *testing.T) {
+		var params map[string]interface{}
+		adapter := &fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+			params = request.EngineParams
+			return responseFor(request.Query, "https://example.com/1"), nil
+		}}
+		engines := registerFakeAdapters(t, adapter)
+		strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassNews),
+			[]Route{{Class: QueryClassNews, Engines: engines, EngineParams: map[string]interface{}{"categories": "news", "language": "en"}}},
+			newTestStrategy(engines))
+
+		request := &SearchRequest{Query: "q", EngineParams: map[string]interface{}{"language": "de"}}
+		if _, err := strategy.Execute(context.Background(), request); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if params["categories"] != "news" || params["language"] != "de" {
+			t.Errorf("Execute() engine params = %v, want the route's categories and the request's language", params)
+		}
+		if len(request.EngineParams) != 1 {
+			t.Errorf("Execute() modified the request's engine params: %v", request.EngineParams)
+		}
+	})
+}
+
+func TestRoutingSearchStrategySharesBreakers(t *testing.T) {
+	engines := []SearchEngine{SearchEngine(t.Name() + "/a"), SearchEngine(t.Name() + "/b")}
+	fallback := newTestStrategy(engines)
+	strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassNews), []Route{
+		{Class: QueryClassNews, Engines: engines},
+		{Class: QueryClassCode, Engines: engines[1:]},
+	}, fallback)
+
+	news := strategy.strategies[QueryClassNews].(*DefaultSearchStrategy)
+	code := strategy.strategies[QueryClassCode].(*DefaultSearchStrategy)
+	for _, engine := range engines {
+		if news.breaker(engine) != fallback.breaker(engine) {
+			t.Errorf("news route breaker for %s differs from the fallback's", engine)
+		}
+	}
+	if code.breaker(engines[1]) != news.breaker(engines[1]) {
+		t.Errorf("code route breaker for %s differs from the news route's", engines[1])
+	}
+	if news.breaker(engines[0]) == news.breaker(engines[1]) {
+		t.Error("engines share a breaker")
+	}
+}
+
+func TestRoutingSearchStrategyExecuteStream(t *testing.T) {
+	engines := registerFakeAdapters(t, answering(10*time.Millisecond, "https://social.example/1"), answering(0, "https://default.example/1"))
+	strategy := NewRoutingSearchStrategy(nil, classifyAs(QueryClassSocial),
+		[]Route{{Class: QueryClassSocial, Engines: engines[:1]}}, newTestStrategy(engines[1:]))
+
+	batches, err := strategy.ExecuteStream(context.Background(), &SearchRequest{Query: "q"})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	count := 0
+	for batch := range batches {
+		count++
+		if batch.Response != nil && batch.Response.Route != QueryClassSocial {
+			t.Errorf("ExecuteStream() batch route = %v, want %v", batch.Response.Route, QueryClassSocial)
+		}
+	}
+	if count == 0 {
+		t.Error("ExecuteStream() sent no batches")
+	}
+}
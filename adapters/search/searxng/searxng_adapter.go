@@ -13,6 +13,7 @@ import (
 
 	"github.com/anboat/strato-sdk/adapters/search"
 	"github.com/anboat/strato-sdk/pkg/logging"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 )
 
 // SearXNGAdapter is the search adapter for SearXNG.
@@ -34,14 +35,14 @@ type SearXNGConfig struct {
 
 // SearXNGResponse represents the response from the SearXNG API.
 type SearXNGResponse struct {
-	Query               string          `json:"query"`
-	NumberOfResults     int             `json:"number_of_results"`
-	Results             []SearXNGResult `json:"results"`
-	Answers             []interface{}   `json:"answers"`
-	Corrections         []interface{}   `json:"corrections"`
-	Infoboxes           []interface{}   `json:"infoboxes"`
-	Suggestions         []interface{}   `json:"suggestions"`
-	UnresponsiveEngines []interface{}   `json:"unresponsive_engines"`
+	Query               string                      `json:"query"`
+	NumberOfResults     int                         `json:"number_of_results"`
+	Results             []SearXNGResult             `json:"results"`
+	Answers             []SearXNGAnswer             `json:"answers"`
+	Corrections         []string                    `json:"corrections"`
+	Infoboxes           []SearXNGInfobox            `json:"infoboxes"`
+	Suggestions         []string                    `json:"suggestions"`
+	UnresponsiveEngines []SearXNGUnresponsiveEngine `json:"unresponsive_engines"`
 }
 
 // SearXNGResult represents a single search result from SearXNG.
@@ -60,6 +61,77 @@ type SearXNGResult struct {
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// SearXNGAnswer is an instant answer returned by SearXNG.
+// Older SearXNG versions return answers as plain strings, newer ones as objects.
+type SearXNGAnswer struct {
+	Answer string `json:"answer"`
+	URL    string `json:"url,omitempty"`
+	Engine string `json:"engine,omitempty"`
+}
+
+// UnmarshalJSON decodes an answer given either as a string or as an object.
+func (a *SearXNGAnswer) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*a = SearXNGAnswer{Answer: text}
+		return nil
+	}
+
+	type answer SearXNGAnswer // Avoids recursing into this method.
+	var decoded answer
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("failed to decode searxng answer: %w", err)
+	}
+	*a = SearXNGAnswer(decoded)
+	return nil
+}
+
+// SearXNGInfobox is a knowledge panel returned by SearXNG, e.g., from Wikipedia or Wikidata.
+type SearXNGInfobox struct {
+	Infobox    string             `json:"infobox"` // Title of the infobox.
+	ID         string             `json:"id"`      // URL of the entity.
+	Content    string             `json:"content"`
+	ImgSrc     string             `json:"img_src"`
+	URLs       []SearXNGInfoLink  `json:"urls"`
+	Attributes []SearXNGAttribute `json:"attributes"`
+	Engine     string             `json:"engine"`
+}
+
+// SearXNGInfoLink is a link listed in an infobox.
+type SearXNGInfoLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// SearXNGAttribute is a labeled fact in an infobox. Values are usually strings,
+// but some engines return numbers or objects, which are kept in their JSON form.
+type SearXNGAttribute struct {
+	Label string          `json:"label"`
+	Value json.RawMessage `json:"value"`
+}
+
+// SearXNGUnresponsiveEngine is an upstream engine that failed to answer,
+// encoded by SearXNG as a [name, reason] pair.
+type SearXNGUnresponsiveEngine struct {
+	Engine string
+	Reason string
+}
+
+// UnmarshalJSON decodes a [name, reason] pair.
+func (e *SearXNGUnresponsiveEngine) UnmarshalJSON(data []byte) error {
+	var pair []string
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return fmt.Errorf("failed to decode searxng unresponsive engine: %w", err)
+	}
+	if len(pair) > 0 {
+		e.Engine = pair[0]
+	}
+	if len(pair) > 1 {
+		e.Reason = pair[1]
+	}
+	return nil
+}
+
 // NewSearXNGAdapter creates a new SearXNG search adapter.
 func NewSearXNGAdapter(config *SearXNGConfig) *SearXNGAdapter {
 	// Set default values.
@@ -131,7 +203,7 @@ func (s *SearXNGAdapter) Search(ctx context.Context, request *search.SearchReque
 	// Check the response status code.
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("HTTP request failed with status code %d: %s", resp.StatusCode, string(body))
+		return nil, resilience.NewStatusError(resp, fmt.Sprintf("HTTP request failed with status code %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Read the response body.
@@ -147,7 +219,13 @@ func (s *SearXNGAdapter) Search(ctx context.Context, request *search.SearchReque
 	}
 
 	// Convert to the standard response format.
-	return s.convertResponse(&searxngResp, time.Since(startTime)), nil
+	response := s.convertResponse(&searxngResp, time.Since(startTime))
+
+	// SearXNG does not report the number of pages, so another page is assumed while pages return results.
+	if len(response.Results) > 0 {
+		response.NextPageToken = strconv.Itoa(s.getPage(request) + 1)
+	}
+	return response, nil
 }
 
 // buildRequestURL builds the request URL for the SearXNG API.
@@ -158,7 +236,7 @@ func (s *SearXNGAdapter) buildRequestURL(request *search.SearchRequest) (string,
 
 	// Build query parameters.
 	params := url.Values{}
-	params.Set("q", request.Query)
+	params.Set("q", search.QueryWithOperators(request))
 	params.Set("format", "json")
 
 	// Set categories.
@@ -177,8 +255,7 @@ func (s *SearXNGAdapter) buildRequestURL(request *search.SearchRequest) (string,
 	}
 
 	// Set pagination.
-	if request.Offset > 0 {
-		page := (request.Offset / max(request.Num, 10)) + 1
+	if page := s.getPage(request); page > 1 {
 		params.Set("pageno", strconv.Itoa(page))
 	}
 
@@ -197,9 +274,33 @@ func (s *SearXNGAdapter) buildRequestURL(request *search.SearchRequest) (string,
 		}
 	}
 
+	// Fall back to the common time range, which uses the same values as SearXNG.
+	if params.Get("time_range") == "" {
+		switch timeRange := search.NormalizeTimeRange(request.TimeRange); timeRange {
+		case search.TimeRangeDay, search.TimeRangeWeek, search.TimeRangeMonth, search.TimeRangeYear:
+			params.Set("time_range", timeRange)
+		}
+	}
+
 	return fmt.Sprintf("%s/search?%s", s.config.BaseURL, params.Encode()), nil
 }
 
+// getPage determines the page to fetch: the page token of a paginated request, or the page derived from the offset.
+func (s *SearXNGAdapter) getPage(request *search.SearchRequest) int {
+	if request.PageToken != "" {
+		if page, err := strconv.Atoi(request.PageToken); err == nil && page > 0 {
+			return page
+		}
+	}
+	return (request.Offset / max(request.Num, 10)) + 1
+}
+
+// SupportsPagination implements the search.Paginator interface.
+// SearXNG pages are numbered, so the page token is the number of the next page.
+func (s *SearXNGAdapter) SupportsPagination() bool {
+	return true
+}
+
 // getCategories determines the search categories to use.
 func (s *SearXNGAdapter) getCategories(request *search.SearchRequest) string {
 	if request.EngineParams != nil {
@@ -211,11 +312,18 @@ func (s *SearXNGAdapter) getCategories(request *search.SearchRequest) string {
 }
 
 // getLanguage determines the search language to use.
+// SearXNG expresses the region as the country part of a locale, e.g., "en-US".
 func (s *SearXNGAdapter) getLanguage(request *search.SearchRequest) string {
-	if request.Lang != "" {
-		return request.Lang
+	lang := request.Lang
+	if lang == "" {
+		lang = s.config.Language
+	}
+
+	region := strings.ToUpper(strings.TrimSpace(request.Region))
+	if region == "" || lang == "" || lang == "all" || lang == "auto" || strings.Contains(lang, "-") {
+		return lang
 	}
-	return s.config.Language
+	return lang + "-" + region
 }
 
 // getSafeSearch determines the safe search level to use.
@@ -254,12 +362,70 @@ func (s *SearXNGAdapter) convertResponse(resp *SearXNGResponse, timeTaken time.D
 		}
 	}
 
-	return &search.SearchResponse{
-		Query:      resp.Query,
-		Results:    results,
-		TotalCount: resp.NumberOfResults,
-		TimeTaken:  timeTaken.Milliseconds(),
+	response := &search.SearchResponse{
+		Query:       resp.Query,
+		Results:     results,
+		TotalCount:  resp.NumberOfResults,
+		TimeTaken:   timeTaken.Milliseconds(),
+		Suggestions: resp.Suggestions,
+		Corrections: resp.Corrections,
+	}
+
+	for _, answer := range resp.Answers {
+		if answer.Answer == "" {
+			continue
+		}
+		response.Answers = append(response.Answers, &search.DirectAnswer{
+			Answer: answer.Answer,
+			URL:    answer.URL,
+			Engine: answer.Engine,
+		})
+	}
+
+	for _, infobox := range resp.Infoboxes {
+		response.Infoboxes = append(response.Infoboxes, convertInfobox(infobox))
+	}
+
+	for _, engine := range resp.UnresponsiveEngines {
+		response.UnresponsiveEngines = append(response.UnresponsiveEngines, &search.EngineDiagnostic{
+			Engine: engine.Engine,
+			Reason: engine.Reason,
+		})
+	}
+
+	return response
+}
+
+// convertInfobox converts a SearXNG infobox to the standard search.Infobox format.
+func convertInfobox(infobox SearXNGInfobox) *search.Infobox {
+	converted := &search.Infobox{
+		Title:    infobox.Infobox,
+		Content:  infobox.Content,
+		URL:      infobox.ID,
+		ImageURL: infobox.ImgSrc,
+		Engine:   infobox.Engine,
+	}
+
+	for _, attribute := range infobox.Attributes {
+		value := strings.TrimSpace(string(attribute.Value))
+		var text string
+		if err := json.Unmarshal(attribute.Value, &text); err == nil {
+			value = text
+		}
+		if attribute.Label == "" || value == "" || value == "null" {
+			continue
+		}
+		converted.Attributes = append(converted.Attributes, &search.InfoboxAttribute{Label: attribute.Label, Value: value})
+	}
+
+	for _, link := range infobox.URLs {
+		if link.URL == "" {
+			continue
+		}
+		converted.Links = append(converted.Links, &search.InfoboxLink{Title: link.Title, URL: link.URL})
 	}
+
+	return converted
 }
 
 func max(a, b int) int {
@@ -0,0 +1,266 @@
+package searxng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// requestParams builds the request URL of the adapter and returns its query parameters.
+func requestParams(t *testing.T, adapter *SearXNGAdapter, request *search.SearchRequest) url.Values {
+	t.Helper()
+	requestURL, err := adapter.buildRequestURL(request)
+	if err != nil {
+		t.Fatalf("buildRequestURL() error = %v", err)
+	}
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		t.Fatalf("buildRequestURL() returned an invalid URL %q: %v", requestURL, err)
+	}
+	return parsed.Query()
+}
+
+func TestBuildRequestURLFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  SearXNGConfig
+		request search.SearchRequest
+		want    map[string]string
+	}{
+		{
+			name:    "defaults",
+			request: search.SearchRequest{Query: "go gc"},
+			want:    map[string]string{"q": "go gc", "language": "zh-CN", "time_range": "", "pageno": ""},
+		},
+		{
+			name:    "site and file type",
+			request: search.SearchRequest{Query: "go gc", Site: "https://go.dev/doc", FileType: "pdf"},
+			want:    map[string]string{"q": "go gc site:go.dev filetype:pdf"},
+		},
+		{
+			name:    "time range alias",
+			request: search.SearchRequest{Query: "go gc", TimeRange: "past_week"},
+			want:    map[string]string{"time_range": "week"},
+		},
+		{
+			name:    "unknown time range",
+			request: search.SearchRequest{Query: "go gc", TimeRange: "decade"},
+			want:    map[string]string{"time_range": ""},
+		},
+		{
+			name:    "engine time range wins",
+			request: search.SearchRequest{Query: "go gc", TimeRange: "year", EngineParams: map[string]interface{}{"time_range": "day"}},
+			want:    map[string]string{"time_range": "day"},
+		},
+		{
+			name:    "region completes the language",
+			request: search.SearchRequest{Query: "go gc", Lang: "en", Region: "us"},
+			want:    map[string]string{"language": "en-US"},
+		},
+		{
+			name:    "region does not override a locale",
+			request: search.SearchRequest{Query: "go gc", Region: "US"},
+			want:    map[string]string{"language": "zh-CN"},
+		},
+		{
+			name:    "region is ignored for all languages",
+			config:  SearXNGConfig{Language: "all"},
+			request: search.SearchRequest{Query: "go gc", Region: "US"},
+			want:    map[string]string{"language": "all"},
+		},
+		{
+			name:    "page token",
+			request: search.SearchRequest{Query: "go gc", PageToken: "3"},
+			want:    map[string]string{"pageno": "3"},
+		},
+		{
+			name:    "page from offset",
+			request: search.SearchRequest{Query: "go gc", Offset: 20, Num: 10},
+			want:    map[string]string{"pageno": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			params := requestParams(t, NewSearXNGAdapter(&config), &tt.request)
+			for key, want := range tt.want {
+				if got := params.Get(key); got != want {
+					t.Errorf("parameter %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildRequestURLEmptyQuery(t *testing.T) {
+	if _, err := NewSearXNGAdapter(&SearXNGConfig{}).buildRequestURL(&search.SearchRequest{}); err == nil {
+		t.Error("buildRequestURL() error = nil, want an error for an empty query")
+	}
+}
+
+// searchResponseJSON is a SearXNG response with every kind of answer and diagnostic.
+const searchResponseJSON = `{
+	"query": "albert einstein",
+	"number_of_results": 120,
+	"results": [
+		{"url": "https://en.wikipedia.org/wiki/Albert_Einstein", "title": "Albert Einstein", "content": "German-born physicist", "engine": "wikipedia", "score": 2.5, "category": "general"}
+	],
+	"answers": [
+		"Albert Einstein was born on 14 March 1879",
+		{"answer": "Theoretical physicist", "url": "https://www.wikidata.org/wiki/Q937", "engine": "wikidata"},
+		{"answer": ""}
+	],
+	"infoboxes": [
+		{
+			"infobox": "Albert Einstein",
+			"id": "https://www.wikidata.org/wiki/Q937",
+			"content": "German-born theoretical physicist",
+			"img_src": "https://upload.wikimedia.org/einstein.jpg",
+			"engine": "wikidata",
+			"attributes": [
+				{"label": "Born", "value": "14 March 1879"},
+				{"label": "Height", "value": 1.75},
+				{"label": "Spouse", "value": {"name": "Mileva Marić"}},
+				{"label": "Died", "value": null},
+				{"label": "", "value": "no label"},
+				{"label": "Empty", "value": ""}
+			],
+			"urls": [
+				{"title": "Wikipedia", "url": "https://en.wikipedia.org/wiki/Albert_Einstein"},
+				{"title": "No URL", "url": ""}
+			]
+		}
+	],
+	"suggestions": ["einstein theory of relativity"],
+	"corrections": ["albert einstein"],
+	"unresponsive_engines": [["google", "timeout"], ["bing"]]
+}`
+
+// serveSearch starts a SearXNG server that answers every search with the body and
+// records the query parameters of the last request.
+func serveSearch(t *testing.T, status int, body string, params *url.Values) *SearXNGAdapter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			http.NotFound(w, r)
+			return
+		}
+		if params != nil {
+			*params = r.URL.Query()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewSearXNGAdapter(&SearXNGConfig{BaseURL: server.URL + "/"})
+}
+
+func TestSearchDecodesAnswers(t *testing.T) {
+	var params url.Values
+	adapter := serveSearch(t, http.StatusOK, searchResponseJSON, &params)
+
+	response, err := adapter.Search(context.Background(), &search.SearchRequest{Query: "albert einstein"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if params.Get("format") != "json" || params.Get("q") != "albert einstein" {
+		t.Errorf("request parameters = %v, want the JSON format and the query", params)
+	}
+
+	if len(response.Results) != 1 || response.Results[0].URL != "https://en.wikipedia.org/wiki/Albert_Einstein" || response.TotalCount != 120 {
+		t.Errorf("Search() results = %d, total %d, want the single result of 120", len(response.Results), response.TotalCount)
+	}
+	if response.NextPageToken != "2" {
+		t.Errorf("Search() next page token = %q, want %q", response.NextPageToken, "2")
+	}
+
+	wantAnswers := []*search.DirectAnswer{
+		{Answer: "Albert Einstein was born on 14 March 1879"},
+		{Answer: "Theoretical physicist", URL: "https://www.wikidata.org/wiki/Q937", Engine: "wikidata"},
+	}
+	if !reflect.DeepEqual(response.Answers, wantAnswers) {
+		t.Errorf("Search() answers = %+v, want %+v", response.Answers, wantAnswers)
+	}
+
+	wantInfoboxes := []*search.Infobox{{
+		Title:    "Albert Einstein",
+		Content:  "German-born theoretical physicist",
+		URL:      "https://www.wikidata.org/wiki/Q937",
+		ImageURL: "https://upload.wikimedia.org/einstein.jpg",
+		Engine:   "wikidata",
+		Attributes: []*search.InfoboxAttribute{
+			{Label: "Born", Value: "14 March 1879"},
+			{Label: "Height", Value: "1.75"},
+			{Label: "Spouse", Value: `{"name": "Mileva Marić"}`},
+		},
+		Links: []*search.InfoboxLink{
+			{Title: "Wikipedia", URL: "https://en.wikipedia.org/wiki/Albert_Einstein"},
+		},
+	}}
+	if !reflect.DeepEqual(response.Infoboxes, wantInfoboxes) {
+		t.Errorf("Search() infoboxes = %s, want %s", toJSON(response.Infoboxes), toJSON(wantInfoboxes))
+	}
+
+	if want := []string{"einstein theory of relativity"}; !reflect.DeepEqual(response.Suggestions, want) {
+		t.Errorf("Search() suggestions = %v, want %v", response.Suggestions, want)
+	}
+	if want := []string{"albert einstein"}; !reflect.DeepEqual(response.Corrections, want) {
+		t.Errorf("Search() corrections = %v, want %v", response.Corrections, want)
+	}
+
+	wantEngines := []*search.EngineDiagnostic{{Engine: "google", Reason: "timeout"}, {Engine: "bing"}}
+	if !reflect.DeepEqual(response.UnresponsiveEngines, wantEngines) {
+		t.Errorf("Search() unresponsive engines = %s, want %s", toJSON(response.UnresponsiveEngines), toJSON(wantEngines))
+	}
+}
+
+func TestSearchWithoutResults(t *testing.T) {
+	adapter := serveSearch(t, http.StatusOK, `{"query": "q", "results": [], "answers": [], "infoboxes": []}`, nil)
+
+	response, err := adapter.Search(context.Background(), &search.SearchRequest{Query: "q"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(response.Results) != 0 || response.Answers != nil || response.Infoboxes != nil || response.UnresponsiveEngines != nil {
+		t.Errorf("Search() = %s, want an empty response", toJSON(response))
+	}
+	if response.NextPageToken != "" {
+		t.Errorf("Search() next page token = %q, want none after an empty page", response.NextPageToken)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	t.Run("status", func(t *testing.T) {
+		adapter := serveSearch(t, http.StatusTooManyRequests, `rate limited`, nil)
+		_, err := adapter.Search(context.Background(), &search.SearchRequest{Query: "q"})
+		var statusErr *resilience.StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Search() error = %v, want a status error with code %d", err, http.StatusTooManyRequests)
+		}
+	})
+
+	t.Run("malformed answers", func(t *testing.T) {
+		for _, body := range []string{`{"answers": [42]}`, `{"unresponsive_engines": ["google"]}`, `not json`} {
+			adapter := serveSearch(t, http.StatusOK, body, nil)
+			if _, err := adapter.Search(context.Background(), &search.SearchRequest{Query: "q"}); err == nil {
+				t.Errorf("Search() of %s error = nil, want a decoding error", body)
+			}
+		}
+	})
+}
+
+// toJSON renders a value as JSON, for readable test failures.
+func toJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -1,81 +1,73 @@
 package searxng
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
 )
 
-// SearXNGAdapterCreator creates SearXNG adapter instances.
-type SearXNGAdapterCreator struct{}
+// engineName is the name the SearXNG adapter is registered under.
+const engineName = "searxng"
 
-// CreateAdapter creates a new SearXNG adapter instance.
-func (c *SearXNGAdapterCreator) CreateAdapter(engineConfig types.EngineConfig) (search.SearchAdapter, error) {
-	// Create a SearXNG configuration object.
-	searxngConfig := &SearXNGConfig{}
+// SearXNGOptions holds the keys of the `config` section of the SearXNG engine.
+type SearXNGOptions struct {
+	UserAgent  string        `mapstructure:"user_agent" description:"User-Agent header sent to the instance"`
+	Language   string        `mapstructure:"language" description:"Default search language"`
+	SafeSearch int           `mapstructure:"safe_search" description:"Safe search level: 0 (off), 1 (moderate) or 2 (strict)"`
+	Categories string        `mapstructure:"categories" description:"Comma-separated SearXNG categories"`
+	Engines    string        `mapstructure:"engines" description:"Comma-separated SearXNG engines, empty for the instance defaults"`
+	Timeout    time.Duration `mapstructure:"timeout" description:"Request timeout, in seconds or as a duration string"`
+}
 
-	// Use the BaseURL field instead of the Config map.
-	if engineConfig.BaseURL != "" {
-		searxngConfig.BaseURL = engineConfig.BaseURL
-	} else {
-		searxngConfig.BaseURL = "https://searx.be" // Default public instance.
+// defaultSearXNGOptions returns the options used for the keys that are not configured.
+func defaultSearXNGOptions() SearXNGOptions {
+	return SearXNGOptions{
+		UserAgent:  "SearXNG-Go-Client/1.0",
+		Language:   "zh-CN",
+		SafeSearch: 1, // Moderate level.
+		Categories: "general",
+		Timeout:    30 * time.Second,
 	}
+}
 
-	// Read other configurations from the engine's 'config' section.
-	if engineConfig.Config != nil {
-		if userAgent, ok := engineConfig.Config["user_agent"].(string); ok {
-			searxngConfig.UserAgent = userAgent
-		} else {
-			searxngConfig.UserAgent = "SearXNG-Go-Client/1.0"
-		}
-
-		if language, ok := engineConfig.Config["language"].(string); ok {
-			searxngConfig.Language = language
-		} else {
-			searxngConfig.Language = "zh-CN"
-		}
-
-		if safeSearch, ok := engineConfig.Config["safe_search"].(string); ok {
-			searxngConfig.SafeSearch = safeSearch
-		} else {
-			searxngConfig.SafeSearch = "1" // Moderate level.
-		}
+// Validate implements config.ConfigValidator.
+func (o *SearXNGOptions) Validate() error {
+	if o.SafeSearch < 0 || o.SafeSearch > 2 {
+		return config.InvalidConfigValue("safe_search", "must be 0, 1 or 2, got %d", o.SafeSearch)
+	}
+	if o.Timeout <= 0 {
+		return config.InvalidConfigValue("timeout", "must be positive, got %s", o.Timeout)
+	}
+	return nil
+}
 
-		if categories, ok := engineConfig.Config["categories"].(string); ok {
-			searxngConfig.Categories = categories
-		} else {
-			searxngConfig.Categories = "general"
-		}
+// SearXNGAdapterCreator creates SearXNG adapter instances.
+type SearXNGAdapterCreator struct{}
 
-		if engines, ok := engineConfig.Config["engines"].(string); ok {
-			searxngConfig.Engines = engines
-		}
+// CreateAdapter creates a new SearXNG adapter instance.
+func (c *SearXNGAdapterCreator) CreateAdapter(engineConfig types.EngineConfig) (search.SearchAdapter, error) {
+	options := defaultSearXNGOptions()
+	if err := config.DecodeAdapterConfig(config.ComponentSearchEngine, engineName, engineConfig.Config, &options); err != nil {
+		return nil, err
+	}
 
-		// Handle various timeout formats.
-		if timeout, ok := engineConfig.Config["timeout"]; ok {
-			switch t := timeout.(type) {
-			case int:
-				searxngConfig.Timeout = time.Duration(t) * time.Second
-			case float64:
-				searxngConfig.Timeout = time.Duration(t) * time.Second
-			case string:
-				if duration, err := time.ParseDuration(t); err == nil {
-					searxngConfig.Timeout = duration
-				}
-			}
-		}
-	} else {
-		// Provide default configurations.
-		searxngConfig.UserAgent = "SearXNG-Go-Client/1.0"
-		searxngConfig.Language = "zh-CN"
-		searxngConfig.SafeSearch = "1"
-		searxngConfig.Categories = "general"
+	// Create a SearXNG configuration object.
+	searxngConfig := &SearXNGConfig{
+		BaseURL:    engineConfig.BaseURL,
+		UserAgent:  options.UserAgent,
+		Timeout:    options.Timeout,
+		Language:   options.Language,
+		SafeSearch: strconv.Itoa(options.SafeSearch),
+		Categories: options.Categories,
+		Engines:    options.Engines,
 	}
 
-	// Set a default timeout if not provided.
-	if searxngConfig.Timeout == 0 {
-		searxngConfig.Timeout = 30 * time.Second
+	// Fall back to a public instance if no BaseURL is configured.
+	if searxngConfig.BaseURL == "" {
+		searxngConfig.BaseURL = "https://searx.be"
 	}
 
 	return NewSearXNGAdapter(searxngConfig), nil
@@ -84,5 +76,14 @@ func (c *SearXNGAdapterCreator) CreateAdapter(engineConfig types.EngineConfig) (
 // init registers the SearXNG adapter creator.
 func init() {
 	creator := &SearXNGAdapterCreator{}
-	search.RegisterAdapterCreator("searxng", creator.CreateAdapter)
+	search.RegisterAdapterCreator(engineName, creator.CreateAdapter)
+	search.RegisterAdapterDescriptor(engineName, search.AdapterDescriptor{
+		Description: "Self-hosted SearXNG metasearch instance",
+		Capabilities: search.AdapterCapabilities{
+			Pagination: true,
+			Filters:    []string{search.FilterSite, search.FilterFileType, search.FilterRegion, search.FilterTimeRange, search.FilterLang},
+			Answers:    true,
+		},
+		ConfigSchema: config.ConfigSchema(defaultSearXNGOptions()),
+	})
 }
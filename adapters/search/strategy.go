@@ -4,16 +4,46 @@ import (
 	"context"
 	"fmt"
 	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/pkg/logging"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+	"github.com/anboat/strato-sdk/pkg/urlcanon"
 	"sync"
+	"time"
 )
 
+// Metadata keys recorded on results by the search strategies.
+const (
+	// MetadataURLKey is the canonical deduplication key of the result URL.
+	MetadataURLKey = "url_key"
+
+	// MetadataDomainWeight is the credibility weight of the result's domain, recorded by the domain policy.
+	MetadataDomainWeight = "domain_weight"
+)
+
+// DefaultMaxPages is the default number of pages requested from an engine when auto-pagination is on.
+const DefaultMaxPages = 5
+
 // SearchStrategy defines the interface for a search strategy.
-// It provides a method to execute a search based on a given request.
+// It provides methods to execute a search based on a given request.
 type SearchStrategy interface {
 	// Execute performs the search according to the implemented strategy.
 	Execute(ctx context.Context, request *SearchRequest) (*SearchResponse, error)
 }
 
+// StreamingSearchStrategy is an optional interface for search strategies that can send the
+// results of each engine as soon as it answers. Callers detect it with a type assertion, or use
+// StreamSearch, which falls back to Execute for strategies that do not implement it.
+//
+// Streamed batches are not cached by CachedStrategy: they hold the unfused results of single
+// engines, so only Execute fills the cache, although a cached response is streamed as well.
+type StreamingSearchStrategy interface {
+	SearchStrategy
+
+	// ExecuteStream performs the search and sends the results over the returned channel as the
+	// engines answer. The channel is closed when the search is complete.
+	ExecuteStream(ctx context.Context, request *SearchRequest) (<-chan *SearchBatch, error)
+}
+
 // SearchStrategyConfig holds the configuration for a search strategy.
 type SearchStrategyConfig struct {
 	// Breadth specifies the number of search results to retrieve from each search engine.
@@ -32,16 +62,100 @@ type SearchStrategyConfig struct {
 	// EnableFallback, if true, allows the strategy to try the next engine in the order upon failure.
 	EnableFallback bool `json:"enable_fallback"`
 
-	// FailFast, if true, causes the strategy to stop immediately after the first engine failure.
+	// FailFast, if true, causes the strategy to fall back immediately after an engine failure, without retries.
 	FailFast bool `json:"fail_fast"`
+
+	// MaxRetries is the number of times a search is retried on the same engine before falling back.
+	// Only transient errors are retried: network errors, timeouts, and 408, 429 and 5xx responses.
+	MaxRetries int `json:"max_retries"`
+
+	// Timeout bounds each search attempt. Zero leaves attempts bounded only by the caller's context.
+	Timeout time.Duration `json:"timeout"`
+
+	// CircuitBreaker configures the circuit breaker that tracks the health of each engine
+	// and skips engines that keep failing until they recover.
+	CircuitBreaker resilience.BreakerConfig `json:"circuit_breaker"`
+
+	// Hedge configures hedged searches in fallback mode: when an engine is slow to answer, the next
+	// engine in the fallback order is started in parallel and the first success wins.
+	Hedge resilience.HedgeConfig `json:"hedge"`
+
+	// DomainAllowlist, if not empty, restricts results to these domains and their subdomains.
+	DomainAllowlist []string `json:"domain_allowlist,omitempty"`
+
+	// DomainDenylist removes results from these domains and their subdomains.
+	DomainDenylist []string `json:"domain_denylist,omitempty"`
+
+	// DomainWeights maps a domain or domain suffix (e.g., "gov", "arxiv.org") to a credibility weight.
+	// Domains without an entry use DefaultDomainWeight.
+	DomainWeights map[string]float64 `json:"domain_weights,omitempty"`
+
+	// Merger fuses the results of the engines in a mixed search. Defaults to reciprocal rank fusion.
+	Merger ResultMerger `json:"-"`
+
+	// AutoPaginate, if true, keeps requesting pages from engines that support pagination until
+	// SearchRequest.Num unique results are collected, the engine runs out of results, or MaxPages is reached.
+	AutoPaginate bool `json:"auto_paginate"`
+
+	// MaxPages bounds the number of pages requested from an engine for a single search when auto-pagination is on.
+	MaxPages int `json:"max_pages"`
+
+	// MinResults, if positive, makes a mixed search return as soon as this many unique results
+	// have arrived, without waiting for the slower engines.
+	MinResults int `json:"min_results"`
+
+	// SoftDeadline, if positive, makes a mixed search return with the results that have arrived
+	// once it has passed; if none have arrived yet, it returns with the first results that do.
+	SoftDeadline time.Duration `json:"soft_deadline"`
+
+	// Canonicalizer normalizes result URLs for deduplication. Defaults to applying every normalization.
+	Canonicalizer *urlcanon.Canonicalizer `json:"-"`
 }
 
 // DefaultSearchStrategy provides a default implementation for the search strategy.
 // It supports both fallback and mixed search modes.
 type DefaultSearchStrategy struct {
+	mu           sync.RWMutex
+	config       *SearchStrategyConfig
+	adapters     map[SearchEngine]strategyAdapter
+	breakers     *engineBreakers
+	domainPolicy *domainPolicy
+}
+
+// engineBreakers holds the circuit breaker of each engine. Strategies that search the same
+// engines, such as the strategies of a routing strategy, share it so that each engine's health
+// is tracked once, whichever strategy calls the engine.
+type engineBreakers struct {
+	config   resilience.BreakerConfig
 	mu       sync.RWMutex
-	config   *SearchStrategyConfig
-	adapters map[SearchEngine]SearchAdapter
+	breakers map[SearchEngine]*resilience.CircuitBreaker
+}
+
+// newEngineBreakers creates an empty set of circuit breakers with the given configuration.
+func newEngineBreakers(config resilience.BreakerConfig) *engineBreakers {
+	return &engineBreakers{config: config, breakers: make(map[SearchEngine]*resilience.CircuitBreaker)}
+}
+
+// get returns the circuit breaker of the engine, creating it if needed.
+func (b *engineBreakers) get(engine SearchEngine) *resilience.CircuitBreaker {
+	b.mu.RLock()
+	breaker, exists := b.breakers[engine]
+	b.mu.RUnlock()
+	if exists {
+		return breaker
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Double-check in case another goroutine created the breaker.
+	if breaker, exists := b.breakers[engine]; exists {
+		return breaker
+	}
+
+	breaker = resilience.NewCircuitBreaker(b.config)
+	b.breakers[engine] = breaker
+	return breaker
 }
 
 // NewDefaultSearchStrategy creates a new instance of the default search strategy
@@ -72,9 +186,26 @@ func NewDefaultSearchStrategy(config *SearchStrategyConfig) *DefaultSearchStrate
 		config.Breadth = 10 // Default to 10 results per engine.
 	}
 
+	// Set default page limit for auto-pagination.
+	if config.MaxPages <= 0 {
+		config.MaxPages = DefaultMaxPages
+	}
+
+	// Set default result merger.
+	if config.Merger == nil {
+		config.Merger = &RRFMerger{K: DefaultRRFK}
+	}
+
+	// Set default URL canonicalizer.
+	if config.Canonicalizer == nil {
+		config.Canonicalizer = urlcanon.New(nil)
+	}
+
 	return &DefaultSearchStrategy{
-		config:   config,
-		adapters: make(map[SearchEngine]SearchAdapter),
+		config:       config,
+		adapters:     make(map[SearchEngine]strategyAdapter),
+		breakers:     newEngineBreakers(config.CircuitBreaker),
+		domainPolicy: newDomainPolicy(config),
 	}
 }
 
@@ -100,6 +231,42 @@ func NewDefaultSearchStrategyFromConfig() (SearchStrategy, error) {
 		DefaultFallbackOrder: convertStringSliceToSearchEngines(searchConfig.Strategy.DefaultFallbackOrder),
 		EnableFallback:       searchConfig.Strategy.EnableFallback,
 		FailFast:             searchConfig.Strategy.FailFast,
+		MaxRetries:           searchConfig.Strategy.MaxRetries,
+		Timeout:              time.Duration(searchConfig.Strategy.TimeoutSeconds) * time.Second,
+		CircuitBreaker: resilience.BreakerConfig{
+			Enabled:            searchConfig.Strategy.CircuitBreaker.Enabled,
+			FailureThreshold:   searchConfig.Strategy.CircuitBreaker.FailureThreshold,
+			ErrorRateThreshold: searchConfig.Strategy.CircuitBreaker.ErrorRateThreshold,
+			MinRequests:        searchConfig.Strategy.CircuitBreaker.MinRequests,
+			WindowSize:         searchConfig.Strategy.CircuitBreaker.WindowSize,
+			Cooldown:           time.Duration(searchConfig.Strategy.CircuitBreaker.CooldownSeconds) * time.Second,
+		},
+		Hedge: resilience.HedgeConfig{
+			Enabled:  searchConfig.Strategy.Hedge.Enabled,
+			Delay:    time.Duration(searchConfig.Strategy.Hedge.DelayMs) * time.Millisecond,
+			Adaptive: searchConfig.Strategy.Hedge.Adaptive,
+		},
+		DomainAllowlist: searchConfig.Strategy.DomainAllowlist,
+		DomainDenylist:  searchConfig.Strategy.DomainDenylist,
+		AutoPaginate:    searchConfig.Strategy.AutoPaginate,
+		MaxPages:        searchConfig.Strategy.MaxPages,
+		MinResults:      searchConfig.Strategy.MinResults,
+		SoftDeadline:    time.Duration(searchConfig.Strategy.SoftDeadlineSeconds) * time.Second,
+		Canonicalizer:   urlcanon.New(config.GetCanonicalizationConfig()),
+	}
+
+	merger, err := NewResultMerger(searchConfig.Strategy.Merger, searchConfig.Strategy.RRFK)
+	if err != nil {
+		return nil, err
+	}
+	strategyConfig.Merger = merger
+
+	// Convert the domain weight list into a lookup table.
+	if len(searchConfig.Strategy.DomainWeights) > 0 {
+		strategyConfig.DomainWeights = make(map[string]float64, len(searchConfig.Strategy.DomainWeights))
+		for _, domainWeight := range searchConfig.Strategy.DomainWeights {
+			strategyConfig.DomainWeights[domainWeight.Domain] = domainWeight.Weight
+		}
 	}
 
 	// Filter the fallback order list to include only enabled engines.
@@ -125,7 +292,27 @@ func NewDefaultSearchStrategyFromConfig() (SearchStrategy, error) {
 		strategyConfig.MixedEngines = enabledMixedEngines
 	}
 
-	return NewDefaultSearchStrategy(strategyConfig), nil
+	var strategy SearchStrategy = NewDefaultSearchStrategy(strategyConfig)
+
+	// Route queries to engines by intent, if enabled.
+	if searchConfig.Routing.Enabled {
+		routing, err := newRoutingStrategyFromConfig(&searchConfig.Routing, searchConfig.Engines, strategyConfig, strategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create search routing: %w", err)
+		}
+		strategy = routing
+	}
+
+	// Serve repeated requests from the cache, if enabled.
+	if searchConfig.Cache.Enabled {
+		backend, err := NewCacheBackendFromConfig(&searchConfig.Cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create search cache: %w", err)
+		}
+		return NewCachedStrategy(strategy, backend, newCacheConfig(&searchConfig.Cache)), nil
+	}
+
+	return strategy, nil
 }
 
 // convertStringSliceToSearchEngines converts a slice of strings to a slice of SearchEngine type.
@@ -149,181 +336,363 @@ func (s *DefaultSearchStrategy) Execute(ctx context.Context, request *SearchRequ
 	return s.executeFallbackSearch(ctx, request)
 }
 
-// executeMixedSearch performs a search across multiple engines concurrently and aggregates the results.
+// executeMixedSearch performs a search across multiple engines concurrently and fuses the results.
+// With MinResults or SoftDeadline set, it returns as soon as enough unique results have arrived
+// or the deadline has passed, cancelling the engines that have not answered yet.
 func (s *DefaultSearchStrategy) executeMixedSearch(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
-	engines := s.config.MixedEngines
-	if len(engines) == 0 {
-		return nil, fmt.Errorf("no mixed search engines are configured")
-	}
+	// Cancel the engines that are still running when the search returns early.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	// A channel to collect results from different engines.
-	type engineResult struct {
-		engine   SearchEngine
-		response *SearchResponse
-		err      error
+	engines, batches, err := s.streamMixedSearch(ctx, request)
+	if err != nil {
+		return nil, err
 	}
 
-	resultChan := make(chan engineResult, len(engines))
-	var wg sync.WaitGroup
-
-	// Perform search on each engine concurrently.
-	for _, engine := range engines {
-		wg.Add(1)
-		go func(eng SearchEngine) {
-			defer wg.Done()
+	var deadline <-chan time.Time
+	if s.config.SoftDeadline > 0 {
+		timer := time.NewTimer(s.config.SoftDeadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
 
-			adapter, err := s.getOrCreateAdapter(eng)
-			if err != nil {
-				resultChan <- engineResult{engine: eng, err: fmt.Errorf("failed to create adapter for engine %s: %w", eng, err)}
-				return
+	// Collect the batches by engine position, so the merge does not depend on which engine finishes first.
+	responses := make([]*SearchResponse, len(engines))
+	errs := make([]error, len(engines))
+	answered := 0
+	seenURLs := make(map[string]bool)
+	deadlinePassed := false
+collect:
+	for {
+		select {
+		case batch, ok := <-batches:
+			if !ok {
+				break collect
 			}
-
-			response, err := adapter.Search(ctx, request)
-			resultChan <- engineResult{engine: eng, response: response, err: err}
-		}(engine)
+			responses[batch.index] = batch.Response
+			errs[batch.index] = batch.Err
+			answered++
+			if batch.Response != nil {
+				for i, result := range batch.Response.Results {
+					seenURLs[fusionKey(result, batch.Engine, i)] = true
+				}
+			}
+			if s.config.MinResults > 0 && len(seenURLs) >= s.config.MinResults {
+				break collect
+			}
+			if deadlinePassed && len(seenURLs) > 0 {
+				break collect
+			}
+		case <-deadline:
+			// Keep waiting for the first results if no engine has returned any yet.
+			if len(seenURLs) > 0 {
+				break collect
+			}
+			deadline = nil
+			deadlinePassed = true
+		}
+	}
+	if answered < len(engines) {
+		logging.Infof("Mixed search returned early with results from %d of %d engines", answered, len(engines))
 	}
 
-	// Wait for all searches to complete.
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	// Collect and merge the results.
-	var allResults []*SearchResultItem
+	var lists []EngineResults
 	var errors []error
-	usedEngines := make(map[string]int)
-	seenURLs := make(map[string]bool) // A set of URLs for deduplication.
-
-	for result := range resultChan {
-		if result.err != nil {
-			errors = append(errors, fmt.Errorf("engine %s failed: %w", result.engine, result.err))
+	for i, engine := range engines {
+		if errs[i] != nil {
+			errors = append(errors, fmt.Errorf("engine %s failed: %w", engine, errs[i]))
 			continue
 		}
-
-		if result.response != nil && len(result.response.Results) > 0 {
-			// Get the top 'breadth' results.
-			maxResults := s.config.Breadth
-			if maxResults <= 0 {
-				maxResults = len(result.response.Results)
-			} else if maxResults > len(result.response.Results) {
-				maxResults = len(result.response.Results)
-			}
-
-			engineResults := result.response.Results[:maxResults]
-
-			// Deduplicate results and add engine identifier to each result.
-			var uniqueResults []*SearchResultItem
-			for _, searchResult := range engineResults {
-				// Check for URL duplicates.
-				if searchResult.URL != "" && seenURLs[searchResult.URL] {
-					continue // Skip duplicate URL.
-				}
-
-				if searchResult.Metadata == nil {
-					searchResult.Metadata = make(map[string]interface{})
-				}
-				searchResult.Metadata["search_engine"] = string(result.engine)
-				searchResult.Metadata["strategy"] = "mixed"
-
-				// Mark the URL as seen.
-				if searchResult.URL != "" {
-					seenURLs[searchResult.URL] = true
-				}
-
-				uniqueResults = append(uniqueResults, searchResult)
-			}
-
-			allResults = append(allResults, uniqueResults...)
-			usedEngines[string(result.engine)] = len(uniqueResults)
+		if responses[i] == nil || len(responses[i].Results) == 0 {
+			continue
 		}
+		lists = append(lists, EngineResults{Engine: engine, Results: responses[i].Results})
 	}
 
 	// Handle case where all engines fail.
-	if len(allResults) == 0 {
+	if len(lists) == 0 {
 		if len(errors) > 0 {
 			return nil, fmt.Errorf("all mixed search engines failed: %v", errors)
 		}
 		return nil, fmt.Errorf("no results from any mixed search engine")
 	}
 
+	// Fuse the rankings of the engines, then keep 'breadth' results for every engine that contributed.
+	allResults := s.config.Merger.Merge(lists)
+	if maxResults := s.resultLimit(request) * len(lists); maxResults > 0 && len(allResults) > maxResults {
+		allResults = allResults[:maxResults]
+	}
+
 	// Build the final mixed search response.
 	response := &SearchResponse{
 		Query:      request.Query,
 		Results:    allResults,
 		TotalCount: len(allResults),
 	}
+	for _, engineResponse := range responses {
+		if engineResponse != nil {
+			mergeAnswers(response, engineResponse)
+		}
+	}
 
 	return response, nil
 }
 
 // executeFallbackSearch performs a search using a fallback mechanism.
 // It tries engines one by one from a predefined order until a successful result is obtained.
+// With hedging enabled, the next engine is started in parallel when the current one is slow
+// to answer; the first success wins and the slower search is cancelled.
 func (s *DefaultSearchStrategy) executeFallbackSearch(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
 	engines := s.getEngineOrder()
-	seenURLs := make(map[string]bool) // A set for deduplicating URLs.
+	if len(engines) == 0 {
+		return nil, fmt.Errorf("all search engines are disabled or unavailable: %w", resilience.ErrCircuitOpen)
+	}
+	if !s.config.EnableFallback {
+		engines = engines[:1]
+	}
 
-	var lastErr error
-	for i, engine := range engines {
-		adapter, err := s.getOrCreateAdapter(engine)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create adapter for engine %s: %w", engine, err)
-			if s.config.FailFast || i == len(engines)-1 {
-				continue
-			}
+	response, _, err := resilience.Hedge(ctx, len(engines), func(i int) time.Duration {
+		return s.config.Hedge.DelayFor(s.breaker(engines[i]))
+	}, func(ctx context.Context, i int) (*SearchResponse, error) {
+		return s.searchFallbackEngine(ctx, i+1, engines[i], request)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("all search engines failed, last error: %w", err)
+	}
+	return response, nil
+}
+
+// searchFallbackEngine searches a single engine of a fallback search and prepares its results:
+// the domain policy is applied, duplicate URLs are removed and the results are tagged with the
+// engine and the attempt number. An engine whose results are all filtered out counts as failed.
+func (s *DefaultSearchStrategy) searchFallbackEngine(ctx context.Context, attempt int, engine SearchEngine, request *SearchRequest) (*SearchResponse, error) {
+	adapter, err := s.getOrCreateAdapter(engine)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create adapter for engine %s: %w", engine, err)
+	}
+
+	response, err := s.searchPages(ctx, engine, adapter, request)
+	if err != nil {
+		return nil, fmt.Errorf("engine %s search failed: %w", engine, err)
+	}
+	if response.Results == nil {
+		return response, nil
+	}
+
+	// Apply the domain policy; an engine whose results are all filtered out counts as a failure.
+	allowedResults := s.domainPolicy.apply(response.Results)
+	if len(allowedResults) == 0 && len(response.Results) > 0 {
+		return nil, fmt.Errorf("engine %s returned no results allowed by the domain policy", engine)
+	}
+
+	// Handle deduplication.
+	seenURLs := make(map[string]bool)
+	var uniqueResults []*SearchResultItem
+	for _, result := range allowedResults {
+		// Check for URL duplicates.
+		urlKey := s.recordURLKey(result)
+		if urlKey != "" && seenURLs[urlKey] {
+			continue // Skip duplicate URL.
+		}
+
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
 		}
+		result.Metadata["search_engine"] = string(engine)
+		result.Metadata["strategy"] = "fallback"
+		result.Metadata["attempt"] = attempt
+		result.PublishDate = NormalizePublishDate(result.PublishDate)
+
+		// Mark URL as seen.
+		if urlKey != "" {
+			seenURLs[urlKey] = true
+		}
+
+		uniqueResults = append(uniqueResults, result)
+	}
+
+	// Limit the number of results if breadth is set.
+	if limit := s.resultLimit(request); limit > 0 && len(uniqueResults) > limit {
+		uniqueResults = uniqueResults[:limit]
+	}
+
+	response.Results = uniqueResults
+	response.TotalCount = len(uniqueResults)
+	return response, nil
+}
 
+// search performs a search on a single engine, retrying transient failures according to
+// the retry settings of the strategy. Every attempt first waits for the engine's rate limit.
+func (s *DefaultSearchStrategy) search(ctx context.Context, engine SearchEngine, adapter SearchAdapter, request *SearchRequest) (*SearchResponse, error) {
+	policy := resilience.RetryPolicy{
+		MaxRetries:     s.config.MaxRetries,
+		AttemptTimeout: s.config.Timeout,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			logging.Warnf("Search engine %s failed (attempt %d), retrying in %s: %v", engine, attempt, delay, err)
+		},
+		BeforeAttempt: func(ctx context.Context, attempt int) error {
+			return waitForRateLimit(ctx, engine)
+		},
+	}
+	if s.config.FailFast {
+		policy.MaxRetries = 0
+	}
+
+	breaker := s.breaker(engine)
+	return resilience.Retry(ctx, policy, func(ctx context.Context) (*SearchResponse, error) {
+		if !breaker.Allow() {
+			return nil, fmt.Errorf("engine %s is unavailable: %w", engine, resilience.ErrCircuitOpen)
+		}
+
+		start := time.Now()
 		response, err := adapter.Search(ctx, request)
-		if err == nil {
-			// On success, mark the response with the engine used.
-			if response.Results != nil {
-				// Handle deduplication.
-				var uniqueResults []*SearchResultItem
-				for _, result := range response.Results {
-					// Check for URL duplicates.
-					if result.URL != "" && seenURLs[result.URL] {
-						continue // Skip duplicate URL.
-					}
-
-					if result.Metadata == nil {
-						result.Metadata = make(map[string]interface{})
-					}
-					result.Metadata["search_engine"] = string(engine)
-					result.Metadata["strategy"] = "fallback"
-					result.Metadata["attempt"] = i + 1
-
-					// Mark URL as seen.
-					if result.URL != "" {
-						seenURLs[result.URL] = true
-					}
-
-					uniqueResults = append(uniqueResults, result)
-				}
+		breaker.Record(err, time.Since(start))
+		return response, err
+	})
+}
 
-				// Limit the number of results if breadth is set.
-				if s.config.Breadth > 0 && len(uniqueResults) > s.config.Breadth {
-					uniqueResults = uniqueResults[:s.config.Breadth]
-				}
+// mergeAnswers adds the direct answers, query hints and engine diagnostics of an engine's
+// response to a merged response. Suggestions and corrections are kept only once.
+func mergeAnswers(merged, response *SearchResponse) {
+	merged.Answers = append(merged.Answers, response.Answers...)
+	merged.Infoboxes = append(merged.Infoboxes, response.Infoboxes...)
+	merged.UnresponsiveEngines = append(merged.UnresponsiveEngines, response.UnresponsiveEngines...)
+	for _, suggestion := range response.Suggestions {
+		if !containsString(merged.Suggestions, suggestion) {
+			merged.Suggestions = append(merged.Suggestions, suggestion)
+		}
+	}
+	for _, correction := range response.Corrections {
+		if !containsString(merged.Corrections, correction) {
+			merged.Corrections = append(merged.Corrections, correction)
+		}
+	}
+}
 
-				response.Results = uniqueResults
-				response.TotalCount = len(uniqueResults)
+// searchPages performs a search on a single engine. With auto-pagination on and an adapter that
+// supports pagination, further pages are requested until the requested number of unique results
+// is collected, a page adds no new results, the engine has no more pages, or MaxPages is reached.
+// A failing later page ends the pagination but keeps the results collected so far.
+func (s *DefaultSearchStrategy) searchPages(ctx context.Context, engine SearchEngine, adapter SearchAdapter, request *SearchRequest) (*SearchResponse, error) {
+	if ignored := UnsupportedFilters(adapter, request); len(ignored) > 0 {
+		logging.Debugf("Search engine %s ignores the filters %v", engine, ignored)
+	}
+
+	response, err := s.search(ctx, engine, adapter, request)
+	if err != nil || response == nil || !s.config.AutoPaginate || !SupportsPagination(adapter) {
+		return response, err
+	}
+
+	want := s.resultLimit(request)
+	seenURLs := make(map[string]bool)
+	var results []*SearchResultItem
+
+	// addPage appends the results of a page that were not seen on earlier pages and returns how many were added.
+	addPage := func(page []*SearchResultItem) int {
+		added := 0
+		for _, result := range page {
+			if urlKey := s.recordURLKey(result); urlKey != "" {
+				if seenURLs[urlKey] {
+					continue
+				}
+				seenURLs[urlKey] = true
 			}
-			return response, nil
+			result.Rank = len(results) + 1
+			results = append(results, result)
+			added++
 		}
+		return added
+	}
+	addPage(response.Results)
 
-		lastErr = fmt.Errorf("engine %s search failed: %w", engine, err)
+	for page := 2; page <= s.config.MaxPages && len(results) < want && response.NextPageToken != ""; page++ {
+		pageRequest := *request
+		pageRequest.PageToken = response.NextPageToken
 
-		// If fallback is disabled or this is the last engine, break the loop.
-		if !s.config.EnableFallback || i == len(engines)-1 {
+		next, err := s.search(ctx, engine, adapter, &pageRequest)
+		if err != nil || next == nil {
+			if err != nil {
+				logging.Warnf("Search engine %s failed on page %d, keeping %d results: %v", engine, page, len(results), err)
+			}
+			response.NextPageToken = ""
 			break
 		}
+
+		response.NextPageToken = next.NextPageToken
+		response.TimeTaken += next.TimeTaken
+		if addPage(next.Results) == 0 {
+			break
+		}
+	}
+
+	if len(results) > want {
+		results = results[:want]
+	}
+	response.Results = results
+	return response, nil
+}
+
+// resultLimit returns the number of results kept from each engine: the breadth, raised to the
+// requested number of results when auto-pagination is on.
+func (s *DefaultSearchStrategy) resultLimit(request *SearchRequest) int {
+	if s.config.AutoPaginate && request.Num > s.config.Breadth {
+		return request.Num
+	}
+	return s.config.Breadth
+}
+
+// Health returns the current health of every configured engine, as tracked by its circuit breaker.
+func (s *DefaultSearchStrategy) Health() map[SearchEngine]resilience.Health {
+	engines := append(s.getConfiguredEngines(), s.config.MixedEngines...)
+
+	health := make(map[SearchEngine]resilience.Health, len(engines))
+	for _, engine := range engines {
+		health[engine] = s.breaker(engine).Health()
+	}
+	return health
+}
+
+// breaker returns the circuit breaker of the engine, creating it if needed.
+func (s *DefaultSearchStrategy) breaker(engine SearchEngine) *resilience.CircuitBreaker {
+	return s.breakers.get(engine)
+}
+
+// availableEngines returns the engines that are enabled and whose circuit breaker lets calls through,
+// in the given order.
+func (s *DefaultSearchStrategy) availableEngines(engines []SearchEngine) []SearchEngine {
+	available := make([]SearchEngine, 0, len(engines))
+	for _, engine := range engines {
+		if IsSearchAdapterEnabled(engine) && s.breaker(engine).Ready() {
+			available = append(available, engine)
+		}
+	}
+	return available
+}
+
+// recordURLKey records and returns the deduplication key of the result URL. The URL itself is
+// left as the engine returned it, because sites do not necessarily serve its canonical form.
+// It returns an empty key for results without a URL.
+func (s *DefaultSearchStrategy) recordURLKey(result *SearchResultItem) string {
+	if result.URL == "" {
+		return ""
 	}
 
-	return nil, fmt.Errorf("all search engines failed, last error: %w", lastErr)
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	key := s.config.Canonicalizer.Key(result.URL)
+	result.Metadata[MetadataURLKey] = key
+	return key
 }
 
 // getEngineOrder determines the order of execution for search engines.
+// Disabled engines are skipped, as are engines whose circuit breaker is open until their cooldown has elapsed.
 func (s *DefaultSearchStrategy) getEngineOrder() []SearchEngine {
+	return s.availableEngines(s.getConfiguredEngines())
+}
+
+// getConfiguredEngines returns the configured fallback order of search engines.
+func (s *DefaultSearchStrategy) getConfiguredEngines() []SearchEngine {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -339,12 +708,25 @@ func (s *DefaultSearchStrategy) getEngineOrder() []SearchEngine {
 	return []SearchEngine{s.config.DefaultEngine}
 }
 
+// strategyAdapter is an adapter instance cached by a strategy, together with the registry
+// revision of the factory that created it.
+type strategyAdapter struct {
+	adapter  SearchAdapter
+	revision uint64
+}
+
 // getOrCreateAdapter retrieves a cached adapter instance or creates a new one if not available.
+// A cached adapter is replaced when the engine was registered anew since it was created.
 func (s *DefaultSearchStrategy) getOrCreateAdapter(engine SearchEngine) (SearchAdapter, error) {
+	if !IsSearchAdapterEnabled(engine) {
+		return nil, fmt.Errorf("%w: %s", ErrEngineDisabled, engine)
+	}
+	revision := globalRegistry.Revision(engine)
+
 	s.mu.RLock()
-	if adapter, exists := s.adapters[engine]; exists {
+	if cached, exists := s.adapters[engine]; exists && cached.revision == revision {
 		s.mu.RUnlock()
-		return adapter, nil
+		return cached.adapter, nil
 	}
 	s.mu.RUnlock()
 
@@ -352,16 +734,17 @@ func (s *DefaultSearchStrategy) getOrCreateAdapter(engine SearchEngine) (SearchA
 	defer s.mu.Unlock()
 
 	// Double-check in case another goroutine created the adapter.
-	if adapter, exists := s.adapters[engine]; exists {
-		return adapter, nil
+	if cached, exists := s.adapters[engine]; exists && cached.revision == revision {
+		return cached.adapter, nil
 	}
 
 	// Create a new adapter. Configuration is read when the adapter is registered.
-	adapter, err := CreateSearchAdapter(engine)
+	adapter, revision, err := globalRegistry.create(engine)
 	if err != nil {
+		delete(s.adapters, engine)
 		return nil, err
 	}
 
-	s.adapters[engine] = adapter
+	s.adapters[engine] = strategyAdapter{adapter: adapter, revision: revision}
 	return adapter, nil
 }
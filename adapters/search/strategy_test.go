@@ -0,0 +1,315 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAdapter is a search adapter that answers with a function and counts its calls.
+type fakeAdapter struct {
+	search func(ctx context.Context, request *SearchRequest) (*SearchResponse, error)
+	calls  atomic.Int32
+}
+
+// Search implements SearchAdapter.
+func (a *fakeAdapter) Search(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+	a.calls.Add(1)
+	return a.search(ctx, request)
+}
+
+// answering returns a fake adapter that answers with results for the URLs after the delay,
+// or with the context's error if the context ends first.
+func answering(delay time.Duration, urls ...string) *fakeAdapter {
+	return &fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+		select {
+		case <-time.After(delay):
+			return responseFor(request.Query, urls...), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}}
+}
+
+// failing returns a fake adapter that fails with the error after the delay.
+func failing(delay time.Duration, err error) *fakeAdapter {
+	return &fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+		time.Sleep(delay)
+		return nil, err
+	}}
+}
+
+// responseFor returns a response with one result per URL.
+func responseFor(query string, urls ...string) *SearchResponse {
+	return &SearchResponse{Query: query, Results: resultsFor(urls...), TotalCount: len(urls)}
+}
+
+// registerFakeAdapters registers the fake adapters in the global registry under names unique to
+// the test, and returns the names in the order of the adapters.
+func registerFakeAdapters(t *testing.T, adapters ...*fakeAdapter) []SearchEngine {
+	t.Helper()
+	engines := make([]SearchEngine, len(adapters))
+	for i, adapter := range adapters {
+		engine := SearchEngine(t.Name() + "/" + string(rune('a'+i)))
+		RegisterSearchAdapter(engine, func() (SearchAdapter, error) { return adapter, nil })
+		t.Cleanup(func() {
+			UnregisterSearchAdapter(engine)
+			EnableSearchAdapter(engine)
+		})
+		engines[i] = engine
+	}
+	return engines
+}
+
+// newTestStrategy creates a strategy that falls back across the engines without retries.
+func newTestStrategy(engines []SearchEngine) *DefaultSearchStrategy {
+	return NewDefaultSearchStrategy(&SearchStrategyConfig{
+		DefaultEngine:        engines[0],
+		DefaultFallbackOrder: engines,
+		EnableFallback:       true,
+		FailFast:             true,
+	})
+}
+
+// newMixedTestStrategy creates a strategy that searches the engines concurrently without retries.
+func newMixedTestStrategy(engines []SearchEngine) *DefaultSearchStrategy {
+	strategy := newTestStrategy(engines)
+	strategy.config.MixedEngines = engines
+	return strategy
+}
+
+// fakePage is a page of results served by paging, with the token of the next page.
+type fakePage struct {
+	urls []string
+	next string
+}
+
+// pagingAdapter is a fake adapter that supports pagination.
+type pagingAdapter struct {
+	*fakeAdapter
+}
+
+// SupportsPagination implements Paginator.
+func (pagingAdapter) SupportsPagination() bool { return true }
+
+// paging returns a fake adapter that serves the first page without a page token and every
+// later page for the token given by the page before it.
+func paging(pages ...fakePage) pagingAdapter {
+	byToken := map[string]fakePage{"": pages[0]}
+	for i := 1; i < len(pages); i++ {
+		byToken[pages[i-1].next] = pages[i]
+	}
+	return pagingAdapter{&fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+		page, ok := byToken[request.PageToken]
+		if !ok {
+			return nil, errors.New("unknown page token " + request.PageToken)
+		}
+		response := responseFor(request.Query, page.urls...)
+		response.NextPageToken = page.next
+		return response, nil
+	}}}
+}
+
+// newPagingTestStrategy creates a strategy that paginates up to three pages of two results each.
+func newPagingTestStrategy() *DefaultSearchStrategy {
+	return NewDefaultSearchStrategy(&SearchStrategyConfig{
+		Breadth:      2,
+		AutoPaginate: true,
+		MaxPages:     3,
+		FailFast:     true,
+	})
+}
+
+func TestSearchPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		adapter   SearchAdapter
+		num       int
+		want      []string
+		wantCalls int32
+		wantNext  string
+	}{
+		{
+			name: "collects the requested number",
+			adapter: paging(
+				fakePage{[]string{"https://a.example/", "https://b.example/"}, "p2"},
+				fakePage{[]string{"https://c.example/", "https://d.example/"}, "p3"},
+				fakePage{[]string{"https://e.example/"}, ""},
+			),
+			num:       3,
+			want:      []string{"https://a.example/", "https://b.example/", "https://c.example/"},
+			wantCalls: 2,
+			wantNext:  "p3",
+		},
+		{
+			name: "removes duplicates across pages",
+			adapter: paging(
+				fakePage{[]string{"https://a.example/", "https://b.example/"}, "p2"},
+				fakePage{[]string{"https://b.example", "https://c.example/"}, "p3"},
+				fakePage{[]string{"https://a.example/#top", "https://d.example/"}, ""},
+			),
+			num:       4,
+			want:      []string{"https://a.example/", "https://b.example/", "https://c.example/", "https://d.example/"},
+			wantCalls: 3,
+		},
+		{
+			name: "stops at the last page",
+			adapter: paging(
+				fakePage{[]string{"https://a.example/", "https://b.example/"}, "p2"},
+				fakePage{[]string{"https://c.example/"}, ""},
+			),
+			num:       6,
+			want:      []string{"https://a.example/", "https://b.example/", "https://c.example/"},
+			wantCalls: 2,
+		},
+		{
+			name: "stops on a page without new results",
+			adapter: paging(
+				fakePage{[]string{"https://a.example/", "https://b.example/"}, "p2"},
+				fakePage{[]string{"https://b.example/", "https://a.example/"}, "p3"},
+				fakePage{[]string{"https://c.example/"}, ""},
+			),
+			num:       6,
+			want:      []string{"https://a.example/", "https://b.example/"},
+			wantCalls: 2,
+			wantNext:  "p3",
+		},
+		{
+			name: "stops at the page limit",
+			adapter: paging(
+				fakePage{[]string{"https://a.example/"}, "p2"},
+				fakePage{[]string{"https://b.example/"}, "p3"},
+				fakePage{[]string{"https://c.example/"}, "p4"},
+				fakePage{[]string{"https://d.example/"}, ""},
+			),
+			num:       6,
+			want:      []string{"https://a.example/", "https://b.example/", "https://c.example/"},
+			wantCalls: 3,
+			wantNext:  "p4",
+		},
+		{
+			name: "keeps the results of earlier pages when a page fails",
+			adapter: paging(
+				fakePage{[]string{"https://a.example/", "https://b.example/"}, "missing"},
+			),
+			num:       6,
+			want:      []string{"https://a.example/", "https://b.example/"},
+			wantCalls: 2,
+		},
+		{
+			name: "adapter without pagination",
+			adapter: answering(0,
+				"https://a.example/", "https://b.example/", "https://c.example/",
+			),
+			num:       6,
+			want:      []string{"https://a.example/", "https://b.example/", "https://c.example/"},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := newPagingTestStrategy()
+			response, err := strategy.searchPages(context.Background(), "pager", tt.adapter, &SearchRequest{Query: "q", Num: tt.num})
+			if err != nil {
+				t.Fatalf("searchPages() error = %v", err)
+			}
+			if got := resultURLs(response.Results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchPages() results = %v, want %v", got, tt.want)
+			}
+			if response.NextPageToken != tt.wantNext {
+				t.Errorf("searchPages() next page token = %q, want %q", response.NextPageToken, tt.wantNext)
+			}
+
+			var calls int32
+			switch adapter := tt.adapter.(type) {
+			case pagingAdapter:
+				calls = adapter.calls.Load()
+				// Paginated results are ranked across pages.
+				for i, result := range response.Results {
+					if result.Rank != i+1 {
+						t.Errorf("searchPages() rank of %s = %d, want %d", result.URL, result.Rank, i+1)
+					}
+				}
+			case *fakeAdapter:
+				calls = adapter.calls.Load()
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("adapter calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSearchPagesTokenNeverAdvances(t *testing.T) {
+	// The engine keeps returning the same token, with new results every time.
+	var page atomic.Int32
+	adapter := pagingAdapter{&fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+		n := page.Add(1)
+		response := responseFor(request.Query, fmt.Sprintf("https://example.com/%d", n))
+		response.NextPageToken = "same"
+		return response, nil
+	}}}
+
+	strategy := newPagingTestStrategy()
+	response, err := strategy.searchPages(context.Background(), "pager", adapter, &SearchRequest{Query: "q", Num: 10})
+	if err != nil {
+		t.Fatalf("searchPages() error = %v", err)
+	}
+	if got := adapter.calls.Load(); got != int32(strategy.config.MaxPages) {
+		t.Errorf("adapter calls = %d, want the page limit %d", got, strategy.config.MaxPages)
+	}
+	if got := len(response.Results); got != strategy.config.MaxPages {
+		t.Errorf("searchPages() returned %d results, want %d", got, strategy.config.MaxPages)
+	}
+}
+
+func TestSearchPagesDisabled(t *testing.T) {
+	adapter := paging(
+		fakePage{[]string{"https://a.example/", "https://b.example/"}, "p2"},
+		fakePage{[]string{"https://c.example/"}, ""},
+	)
+	strategy := newPagingTestStrategy()
+	strategy.config.AutoPaginate = false
+
+	response, err := strategy.searchPages(context.Background(), "pager", adapter, &SearchRequest{Query: "q", Num: 6})
+	if err != nil {
+		t.Fatalf("searchPages() error = %v", err)
+	}
+	if got := adapter.calls.Load(); got != 1 {
+		t.Errorf("adapter calls = %d, want 1", got)
+	}
+	if response.NextPageToken != "p2" {
+		t.Errorf("searchPages() next page token = %q, want %q", response.NextPageToken, "p2")
+	}
+	if got := strategy.resultLimit(&SearchRequest{Num: 6}); got != strategy.config.Breadth {
+		t.Errorf("resultLimit() = %d, want the breadth %d", got, strategy.config.Breadth)
+	}
+}
+
+func TestExecutePaginates(t *testing.T) {
+	adapter := paging(
+		fakePage{[]string{"https://a.example/", "https://b.example/"}, "p2"},
+		fakePage{[]string{"https://c.example/", "https://d.example/"}, ""},
+	)
+	engine := SearchEngine(t.Name())
+	RegisterSearchAdapter(engine, func() (SearchAdapter, error) { return adapter, nil })
+	t.Cleanup(func() { UnregisterSearchAdapter(engine) })
+
+	strategy := newPagingTestStrategy()
+	strategy.config.DefaultEngine = engine
+	strategy.config.DefaultFallbackOrder = []SearchEngine{engine}
+
+	response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q", Num: 3})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := []string{"https://a.example/", "https://b.example/", "https://c.example/"}
+	if got := resultURLs(response.Results); !reflect.DeepEqual(got, want) {
+		t.Errorf("Execute() results = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// SearchBatch is the outcome of a single engine in a streaming search.
+type SearchBatch struct {
+	// Engine is the engine that produced the batch; empty if the batch was not produced by a single engine.
+	Engine SearchEngine
+	// Response holds the results of the engine, nil if the engine failed.
+	Response *SearchResponse
+	// Err is the error of the engine, nil if it succeeded.
+	Err error
+
+	index int // Position of the engine in the engine list, used to fuse results deterministically.
+}
+
+// ExecuteStream performs the search and sends the results of every engine over the returned
+// channel as soon as the engine answers, so that callers can start working with partial results.
+// In mixed mode, every engine sends one batch with its own (unfused) results; in fallback mode,
+// a single batch with the result of the fallback search is sent. The channel is closed once all
+// engines have answered. Callers that stop reading early should cancel the context, which stops
+// the engines that are still running.
+func (s *DefaultSearchStrategy) ExecuteStream(ctx context.Context, request *SearchRequest) (<-chan *SearchBatch, error) {
+	if len(s.config.MixedEngines) > 0 {
+		_, batches, err := s.streamMixedSearch(ctx, request)
+		return batches, err
+	}
+
+	batches := make(chan *SearchBatch, 1)
+	go func() {
+		defer close(batches)
+		response, err := s.executeFallbackSearch(ctx, request)
+		batches <- &SearchBatch{Engine: responseEngine(response), Response: response, Err: err}
+	}()
+	return batches, nil
+}
+
+// streamMixedSearch starts the search on every available mixed engine concurrently and returns
+// the engines together with a channel that receives the batch of each engine as it answers.
+// The channel is buffered for all engines, so engines never block on a caller that stopped reading.
+func (s *DefaultSearchStrategy) streamMixedSearch(ctx context.Context, request *SearchRequest) ([]SearchEngine, <-chan *SearchBatch, error) {
+	if len(s.config.MixedEngines) == 0 {
+		return nil, nil, fmt.Errorf("no mixed search engines are configured")
+	}
+
+	// Skip the engines whose circuit breaker is open.
+	engines := s.availableEngines(s.config.MixedEngines)
+	if len(engines) == 0 {
+		return nil, nil, fmt.Errorf("all mixed search engines are disabled or unavailable: %w", resilience.ErrCircuitOpen)
+	}
+
+	batches := make(chan *SearchBatch, len(engines))
+	var wg sync.WaitGroup
+	for i, engine := range engines {
+		wg.Add(1)
+		go func(index int, eng SearchEngine) {
+			defer wg.Done()
+			batches <- s.searchMixedEngine(ctx, index, eng, request)
+		}(i, engine)
+	}
+
+	// Close the channel once every engine has answered.
+	go func() {
+		wg.Wait()
+		close(batches)
+	}()
+
+	return engines, batches, nil
+}
+
+// searchMixedEngine searches a single engine of a mixed search and prepares its results for fusion:
+// the domain policy filters them, the URLs are canonicalized and the results are tagged with the engine.
+func (s *DefaultSearchStrategy) searchMixedEngine(ctx context.Context, index int, engine SearchEngine, request *SearchRequest) *SearchBatch {
+	batch := &SearchBatch{Engine: engine, index: index}
+
+	adapter, err := s.getOrCreateAdapter(engine)
+	if err != nil {
+		batch.Err = fmt.Errorf("failed to create adapter for engine %s: %w", engine, err)
+		return batch
+	}
+
+	response, err := s.searchPages(ctx, engine, adapter, request)
+	if err != nil {
+		batch.Err = err
+		return batch
+	}
+	if response == nil {
+		return batch
+	}
+
+	// Filter by the domain policy before merging so that filtered results do not use up the breadth.
+	// The domain weights are applied by the result merger, so the engine's ranking is kept here.
+	results := s.domainPolicy.filter(response.Results)
+	for _, result := range results {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
+		result.Metadata["search_engine"] = string(engine)
+		result.Metadata["strategy"] = "mixed"
+		result.PublishDate = NormalizePublishDate(result.PublishDate)
+		s.recordURLKey(result)
+	}
+	response.Results = results
+
+	batch.Response = response
+	return batch
+}
+
+// responseEngine returns the engine recorded on the results of a response, or an empty engine.
+func responseEngine(response *SearchResponse) SearchEngine {
+	if response == nil || len(response.Results) == 0 {
+		return ""
+	}
+	engine, _ := response.Results[0].Metadata["search_engine"].(string)
+	return SearchEngine(engine)
+}
+
+// StreamSearch streams the search of the strategy if it implements StreamingSearchStrategy,
+// and otherwise executes the search and sends its outcome as a single batch.
+//
+// Parameters:
+//   - ctx: A context.Context for cancellation; cancelling it stops the engines that are still running.
+//   - strategy: The search strategy.
+//   - request: The search request.
+//
+// Returns:
+//   - <-chan *SearchBatch: The batches of the engines, closed once the search is complete.
+//   - error: An error if the search could not be started.
+func StreamSearch(ctx context.Context, strategy SearchStrategy, request *SearchRequest) (<-chan *SearchBatch, error) {
+	if streaming, ok := strategy.(StreamingSearchStrategy); ok {
+		return streaming.ExecuteStream(ctx, request)
+	}
+
+	batches := make(chan *SearchBatch, 1)
+	go func() {
+		defer close(batches)
+		response, err := strategy.Execute(ctx, request)
+		batches <- &SearchBatch{Engine: responseEngine(response), Response: response, Err: err}
+	}()
+	return batches, nil
+}
+
+// singleBatch returns a closed channel holding a single batch with the response or error.
+func singleBatch(response *SearchResponse, err error) <-chan *SearchBatch {
+	batches := make(chan *SearchBatch, 1)
+	batches <- &SearchBatch{Engine: responseEngine(response), Response: response, Err: err}
+	close(batches)
+	return batches
+}
@@ -0,0 +1,234 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// collectBatches reads the batches until the channel is closed, failing the test if that takes
+// longer than the timeout.
+func collectBatches(t *testing.T, batches <-chan *SearchBatch, timeout time.Duration) []*SearchBatch {
+	t.Helper()
+	var collected []*SearchBatch
+	deadline := time.After(timeout)
+	for {
+		select {
+		case batch, ok := <-batches:
+			if !ok {
+				return collected
+			}
+			collected = append(collected, batch)
+		case <-deadline:
+			t.Fatalf("channel not closed after %v, received %d batches", timeout, len(collected))
+		}
+	}
+}
+
+func TestExecuteStreamSendsBatchesAsEnginesAnswer(t *testing.T) {
+	errDown := errors.New("engine down")
+	engines := registerFakeAdapters(t,
+		answering(80*time.Millisecond, "https://slow.com"),
+		answering(0, "https://fast.com", "https://fast.com/2"),
+		failing(40*time.Millisecond, errDown),
+	)
+	strategy := newMixedTestStrategy(engines)
+
+	batches, err := strategy.ExecuteStream(context.Background(), &SearchRequest{Query: "q"})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	collected := collectBatches(t, batches, time.Second)
+
+	var order []SearchEngine
+	for _, batch := range collected {
+		order = append(order, batch.Engine)
+	}
+	if want := []SearchEngine{engines[1], engines[2], engines[0]}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("batches from %v, want them in the order the engines answered: %v", order, want)
+	}
+
+	fast := collected[0]
+	if fast.Err != nil || len(fast.Response.Results) != 2 {
+		t.Errorf("batch of the fast engine = %+v, want its 2 results", fast)
+	}
+	for _, result := range fast.Response.Results {
+		if result.Metadata["search_engine"] != string(engines[1]) || result.Metadata["strategy"] != "mixed" {
+			t.Errorf("result metadata = %v, want the engine and the mixed strategy", result.Metadata)
+		}
+		if _, fused := result.Metadata["fusion_score"]; fused {
+			t.Error("streamed results were fused")
+		}
+	}
+	if failed := collected[1]; !errors.Is(failed.Err, errDown) || failed.Response != nil {
+		t.Errorf("batch of the failing engine = %+v, want its error", failed)
+	}
+}
+
+func TestExecuteStreamFallback(t *testing.T) {
+	engines := registerFakeAdapters(t, failing(0, errors.New("engine down")), answering(0, "https://b.com"))
+	strategy := newTestStrategy(engines)
+
+	batches, err := strategy.ExecuteStream(context.Background(), &SearchRequest{Query: "q"})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	collected := collectBatches(t, batches, time.Second)
+	if len(collected) != 1 {
+		t.Fatalf("received %d batches, want a single batch in fallback mode", len(collected))
+	}
+	if batch := collected[0]; batch.Err != nil || batch.Engine != engines[1] || len(batch.Response.Results) != 1 {
+		t.Errorf("batch = %+v, want the results of the engine that answered", batch)
+	}
+}
+
+func TestExecuteStreamCancellation(t *testing.T) {
+	engines := registerFakeAdapters(t, answering(time.Hour, "https://a.com"), answering(time.Hour, "https://b.com"))
+	strategy := newMixedTestStrategy(engines)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	batches, err := strategy.ExecuteStream(ctx, &SearchRequest{Query: "q"})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	cancel()
+
+	collected := collectBatches(t, batches, time.Second)
+	if len(collected) != 2 {
+		t.Fatalf("received %d batches, want one per engine", len(collected))
+	}
+	for _, batch := range collected {
+		if !errors.Is(batch.Err, context.Canceled) {
+			t.Errorf("batch of %s error = %v, want %v", batch.Engine, batch.Err, context.Canceled)
+		}
+	}
+}
+
+func TestExecuteStreamWithoutEngines(t *testing.T) {
+	engines := registerFakeAdapters(t, answering(0, "https://a.com"))
+	DisableSearchAdapter(engines[0])
+	strategy := newMixedTestStrategy(engines)
+
+	if batches, err := strategy.ExecuteStream(context.Background(), &SearchRequest{Query: "q"}); err == nil || batches != nil {
+		t.Errorf("ExecuteStream() = %v, %v, want an error without available engines", batches, err)
+	}
+}
+
+func TestExecuteMixedSearchReturnsEarly(t *testing.T) {
+	tests := []struct {
+		name         string
+		minResults   int
+		softDeadline time.Duration
+		fastDelay    time.Duration
+		maxElapsed   time.Duration
+		minElapsed   time.Duration
+	}{
+		{name: "min results", minResults: 2, maxElapsed: 500 * time.Millisecond},
+		{name: "soft deadline", softDeadline: 50 * time.Millisecond, minElapsed: 50 * time.Millisecond, maxElapsed: 500 * time.Millisecond},
+		{
+			name:         "soft deadline waits for the first results",
+			softDeadline: 20 * time.Millisecond,
+			fastDelay:    100 * time.Millisecond,
+			minElapsed:   100 * time.Millisecond,
+			maxElapsed:   500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := make(chan struct{})
+			slow := &fakeAdapter{search: func(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+				<-ctx.Done()
+				close(cancelled)
+				return nil, ctx.Err()
+			}}
+			engines := registerFakeAdapters(t, answering(tt.fastDelay, "https://a.com", "https://b.com"), slow)
+			strategy := newMixedTestStrategy(engines)
+			strategy.config.MinResults = tt.minResults
+			strategy.config.SoftDeadline = tt.softDeadline
+
+			start := time.Now()
+			response, err := strategy.Execute(context.Background(), &SearchRequest{Query: "q"})
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := resultURLs(response.Results); !reflect.DeepEqual(got, []string{"https://a.com", "https://b.com"}) {
+				t.Errorf("results = %v, want the results of the fast engine", got)
+			}
+			if elapsed < tt.minElapsed || elapsed > tt.maxElapsed {
+				t.Errorf("Execute() took %v, want between %v and %v", elapsed, tt.minElapsed, tt.maxElapsed)
+			}
+
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Error("the engine that had not answered was not cancelled")
+			}
+		})
+	}
+}
+
+// executeOnlyStrategy is a search strategy that does not implement StreamingSearchStrategy.
+type executeOnlyStrategy struct {
+	response *SearchResponse
+	err      error
+}
+
+// Execute implements SearchStrategy.
+func (s *executeOnlyStrategy) Execute(ctx context.Context, request *SearchRequest) (*SearchResponse, error) {
+	return s.response, s.err
+}
+
+func TestStreamSearch(t *testing.T) {
+	response := responseFor("q", "https://a.com")
+	response.Results[0].Metadata = map[string]interface{}{"search_engine": "a"}
+
+	batches, err := StreamSearch(context.Background(), &executeOnlyStrategy{response: response}, &SearchRequest{Query: "q"})
+	if err != nil {
+		t.Fatalf("StreamSearch() error = %v", err)
+	}
+	collected := collectBatches(t, batches, time.Second)
+	if len(collected) != 1 || collected[0].Response != response || collected[0].Engine != "a" {
+		t.Errorf("StreamSearch() of a non-streaming strategy = %+v, want its response as a single batch", collected)
+	}
+
+	errDown := errors.New("down")
+	batches, _ = StreamSearch(context.Background(), &executeOnlyStrategy{err: errDown}, &SearchRequest{Query: "q"})
+	if collected := collectBatches(t, batches, time.Second); len(collected) != 1 || !errors.Is(collected[0].Err, errDown) {
+		t.Errorf("StreamSearch() of a failing strategy = %+v, want its error as a single batch", collected)
+	}
+}
+
+func TestCachedStrategyExecuteStream(t *testing.T) {
+	adapter := answering(0, "https://a.com")
+	engines := registerFakeAdapters(t, adapter)
+	cached := NewCachedStrategy(newTestStrategy(engines), NewMemoryCache(10), CacheConfig{})
+	request := &SearchRequest{Query: "q"}
+
+	// Streaming does not fill the cache.
+	batches, err := cached.ExecuteStream(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	if collected := collectBatches(t, batches, time.Second); len(collected) != 1 || collected[0].Response.Results[0].Metadata[MetadataCacheHit] != nil {
+		t.Fatalf("ExecuteStream() = %+v, want the uncached batch of the strategy", collected)
+	}
+	if _, err := cached.Execute(context.Background(), request); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if n := adapter.calls.Load(); n != 2 {
+		t.Errorf("adapter called %d times, want Execute not served by a streamed search", n)
+	}
+
+	// A response cached by Execute is streamed as a single batch.
+	batches, _ = cached.ExecuteStream(context.Background(), request)
+	collected := collectBatches(t, batches, time.Second)
+	if len(collected) != 1 || collected[0].Response.Results[0].Metadata[MetadataCacheHit] != true {
+		t.Errorf("ExecuteStream() = %+v, want the cached response", collected)
+	}
+	if n := adapter.calls.Load(); n != 2 {
+		t.Errorf("adapter called %d times, want the cached response streamed", n)
+	}
+}
@@ -7,22 +7,52 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 )
 
 // Constants for the Twitter adapter.
 const (
 	TwitterAPIBaseURL = "https://api.twitter.com/2"
 	DefaultTimeout    = 30 * time.Second
+	DefaultMaxPages   = 3
+
+	// recentSearchWindow is how far back the recent search endpoint reaches; older start times are rejected.
+	recentSearchWindow = 7 * 24 * time.Hour
+
+	// minPageSize and maxPageSize bound the max_results parameter of the recent search endpoint.
+	minPageSize = 10
+	maxPageSize = 100
+)
+
+// Sort orders of the recent search endpoint.
+const (
+	SortOrderRecency   = "recency"
+	SortOrderRelevancy = "relevancy"
+)
+
+// RankByEngagement is the value of the "rank_by" engine parameter that orders tweets by engagement.
+const RankByEngagement = "engagement"
+
+// Fields requested for every tweet and author.
+const (
+	tweetFields = "created_at,author_id,public_metrics,lang,conversation_id,entities"
+	userFields  = "name,username,verified"
 )
 
 // Client is a client for the Twitter API.
 type Client struct {
-	bearerToken string
-	httpClient  *http.Client
-	baseURL     string
+	bearerToken      string
+	httpClient       *http.Client
+	baseURL          string
+	sortOrder        string
+	maxPages         int
+	rankByEngagement bool
 }
 
 // ClientConfig holds the configuration for the Twitter client.
@@ -31,6 +61,13 @@ type ClientConfig struct {
 	BaseURL     string        `json:"base_url,omitempty"`
 	Timeout     time.Duration `json:"timeout,omitempty"`
 	HTTPClient  *http.Client  `json:"-"`
+
+	// SortOrder is the default sort order of the results: "recency" or "relevancy"; empty uses the API default.
+	SortOrder string `json:"sort_order,omitempty"`
+	// MaxPages bounds the number of pages fetched to collect SearchRequest.Num tweets; zero uses DefaultMaxPages.
+	MaxPages int `json:"max_pages,omitempty"`
+	// RankByEngagement orders the results by likes, retweets, replies and quotes by default.
+	RankByEngagement bool `json:"rank_by_engagement,omitempty"`
 }
 
 // TwitterSearchResponse represents the main structure of the Twitter API search response.
@@ -42,10 +79,56 @@ type TwitterSearchResponse struct {
 
 // TweetObject represents a single tweet.
 type TweetObject struct {
-	ID        string    `json:"id"`
-	Text      string    `json:"text"`
-	AuthorID  string    `json:"author_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             string         `json:"id"`
+	Text           string         `json:"text"`
+	AuthorID       string         `json:"author_id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Lang           string         `json:"lang,omitempty"`
+	ConversationID string         `json:"conversation_id,omitempty"`
+	PublicMetrics  *PublicMetrics `json:"public_metrics,omitempty"`
+	Entities       *Entities      `json:"entities,omitempty"`
+}
+
+// PublicMetrics holds the public engagement counts of a tweet.
+type PublicMetrics struct {
+	RetweetCount    int `json:"retweet_count"`
+	ReplyCount      int `json:"reply_count"`
+	LikeCount       int `json:"like_count"`
+	QuoteCount      int `json:"quote_count"`
+	BookmarkCount   int `json:"bookmark_count"`
+	ImpressionCount int `json:"impression_count"`
+}
+
+// Engagement returns the engagement score of a tweet. Retweets and quotes, which spread the
+// tweet, weigh more than likes and replies.
+func (m *PublicMetrics) Engagement() float64 {
+	if m == nil {
+		return 0
+	}
+	return float64(m.LikeCount) + float64(m.ReplyCount) + 2*float64(m.RetweetCount) + 2*float64(m.QuoteCount)
+}
+
+// Entities holds the entities parsed from the text of a tweet.
+type Entities struct {
+	URLs     []URLEntity     `json:"urls,omitempty"`
+	Hashtags []TagEntity     `json:"hashtags,omitempty"`
+	Mentions []MentionEntity `json:"mentions,omitempty"`
+}
+
+// URLEntity is a URL in the text of a tweet.
+type URLEntity struct {
+	URL         string `json:"url"`
+	ExpandedURL string `json:"expanded_url"`
+}
+
+// TagEntity is a hashtag in the text of a tweet.
+type TagEntity struct {
+	Tag string `json:"tag"`
+}
+
+// MentionEntity is a mention of a user in the text of a tweet.
+type MentionEntity struct {
+	Username string `json:"username"`
 }
 
 // UserObject represents a Twitter user.
@@ -53,6 +136,7 @@ type UserObject struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Username string `json:"username"`
+	Verified bool   `json:"verified,omitempty"`
 }
 
 // MetaObject contains metadata about the search results.
@@ -63,6 +147,21 @@ type MetaObject struct {
 	NextToken   string `json:"next_token"`
 }
 
+// RateLimitError is returned when the Twitter API rejects a request because the rate limit
+// of the endpoint is exhausted. It wraps a resilience.StatusError whose RetryAfter is the
+// time until the limit resets, so that retries wait for the reset or fall back if it is far away.
+type RateLimitError struct {
+	*resilience.StatusError
+	Limit     int       // Requests allowed in the current window.
+	Remaining int       // Requests remaining in the current window.
+	Reset     time.Time // Time the window resets; zero if unknown.
+}
+
+// Unwrap returns the underlying status error.
+func (e *RateLimitError) Unwrap() error {
+	return e.StatusError
+}
+
 // NewClient creates a new Twitter client.
 func NewClient(config *ClientConfig) *Client {
 	if config == nil {
@@ -80,17 +179,84 @@ func NewClient(config *ClientConfig) *Client {
 			Timeout: config.Timeout,
 		}
 	}
+	if config.MaxPages <= 0 {
+		config.MaxPages = DefaultMaxPages
+	}
 
 	return &Client{
-		bearerToken: config.BearerToken,
-		httpClient:  config.HTTPClient,
-		baseURL:     config.BaseURL,
+		bearerToken:      config.BearerToken,
+		httpClient:       config.HTTPClient,
+		baseURL:          strings.TrimRight(config.BaseURL, "/"),
+		sortOrder:        config.SortOrder,
+		maxPages:         config.MaxPages,
+		rankByEngagement: config.RankByEngagement,
 	}
 }
 
 // Search implements the search.SearchAdapter interface for Twitter.
+// It fetches pages until SearchRequest.Num tweets are collected, the results run out, or the
+// page limit of the client is reached. The token of the next page is returned in NextPageToken.
+// The API returns at least minPageSize tweets per page, so when more tweets are fetched than
+// requested, the surplus is cut and NextPageToken is left empty: the next page would skip the
+// cut tweets.
 func (c *Client) Search(ctx context.Context, request *search.SearchRequest) (*search.SearchResponse, error) {
-	req, err := c.buildRequest(ctx, request)
+	startTime := time.Now()
+	want := request.Num
+	if want <= 0 {
+		want = minPageSize
+	}
+
+	response := &search.SearchResponse{Query: request.Query}
+	pageToken := request.PageToken
+	for page := 0; page < c.maxPages; page++ {
+		twitterResp, err := c.fetchPage(ctx, request, pageToken, want-len(response.Results))
+		if err != nil {
+			// Keep the tweets of earlier pages if a later page fails.
+			if page > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		response.Results = append(response.Results, convertTweets(twitterResp)...)
+		response.TotalCount += twitterResp.Meta.ResultCount
+		pageToken = twitterResp.Meta.NextToken
+		if len(response.Results) >= want || pageToken == "" {
+			break
+		}
+	}
+	response.NextPageToken = pageToken
+
+	if c.shouldRankByEngagement(request) {
+		rankByEngagement(response.Results)
+	}
+	if len(response.Results) > want {
+		response.Results = response.Results[:want]
+		response.NextPageToken = ""
+	}
+	for i, result := range response.Results {
+		result.Rank = i + 1
+	}
+
+	response.TimeTaken = time.Since(startTime).Milliseconds()
+	return response, nil
+}
+
+// SupportsPagination implements the search.Paginator interface.
+// The page token is the next_token returned by the Twitter API.
+func (c *Client) SupportsPagination() bool {
+	return true
+}
+
+// UnsupportedFilters implements the search.FilterCapabilities interface.
+// Tweets have no site or file type, and filtering by country requires elevated API access.
+func (c *Client) UnsupportedFilters() []string {
+	return []string{search.FilterSite, search.FilterFileType, search.FilterRegion}
+}
+
+// fetchPage fetches a single page of search results.
+func (c *Client) fetchPage(ctx context.Context, request *search.SearchRequest, pageToken string, remaining int) (*TwitterSearchResponse, error) {
+	req, err := c.buildRequest(ctx, request, pageToken, remaining)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build twitter search request: %w", err)
 	}
@@ -106,21 +272,47 @@ func (c *Client) Search(ctx context.Context, request *search.SearchRequest) (*se
 		return nil, fmt.Errorf("failed to read twitter response body: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(resp, body, time.Now())
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("twitter api returned an error. status: %d, body: %s", resp.StatusCode, string(body))
+		return nil, resilience.NewStatusError(resp, fmt.Sprintf("twitter api returned an error. status: %d, body: %s", resp.StatusCode, string(body)))
 	}
 
-	return c.parseResponse(body, request.Query)
+	var twitterResp TwitterSearchResponse
+	if err := json.Unmarshal(body, &twitterResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal twitter json response: %w", err)
+	}
+	return &twitterResp, nil
 }
 
-// buildRequest creates an HTTP request for the Twitter search API.
-func (c *Client) buildRequest(ctx context.Context, request *search.SearchRequest) (*http.Request, error) {
+// buildRequest creates an HTTP request for a page of the Twitter recent search API.
+func (c *Client) buildRequest(ctx context.Context, request *search.SearchRequest, pageToken string, remaining int) (*http.Request, error) {
 	endpoint := fmt.Sprintf("%s/tweets/search/recent", c.baseURL)
 
 	params := url.Values{}
-	params.Set("query", request.Query)
-	// You can add more parameters here like 'tweet.fields', 'expansions', etc.
+	params.Set("query", buildQuery(request))
+	params.Set("max_results", strconv.Itoa(min(max(remaining, minPageSize), maxPageSize)))
 	params.Set("expansions", "author_id")
+	params.Set("tweet.fields", tweetFields)
+	params.Set("user.fields", userFields)
+
+	if startTime, ok := startTimeFor(request.TimeRange, time.Now()); ok {
+		params.Set("start_time", startTime.Format(time.RFC3339))
+	}
+	// Explicit time windows in the engine parameters take precedence over the time range.
+	for _, name := range []string{"start_time", "end_time"} {
+		if value, ok := request.EngineParams[name].(string); ok && value != "" {
+			params.Set(name, value)
+		}
+	}
+
+	if sortOrder := c.getSortOrder(request); sortOrder != "" {
+		params.Set("sort_order", sortOrder)
+	}
+	if pageToken != "" {
+		params.Set("next_token", pageToken)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+params.Encode(), nil)
 	if err != nil {
@@ -133,13 +325,87 @@ func (c *Client) buildRequest(ctx context.Context, request *search.SearchRequest
 	return req, nil
 }
 
-// parseResponse parses the JSON response from the Twitter API.
-func (c *Client) parseResponse(body []byte, query string) (*search.SearchResponse, error) {
-	var twitterResp TwitterSearchResponse
-	if err := json.Unmarshal(body, &twitterResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal twitter json response: %w", err)
+// getSortOrder determines the sort order: the "sort_order" engine parameter or the configured default.
+func (c *Client) getSortOrder(request *search.SearchRequest) string {
+	if sortOrder, ok := request.EngineParams["sort_order"].(string); ok {
+		switch sortOrder = strings.ToLower(strings.TrimSpace(sortOrder)); sortOrder {
+		case SortOrderRecency, SortOrderRelevancy:
+			return sortOrder
+		}
+	}
+	return c.sortOrder
+}
+
+// shouldRankByEngagement reports whether the results are ordered by engagement: the "rank_by"
+// engine parameter or the configured default.
+func (c *Client) shouldRankByEngagement(request *search.SearchRequest) bool {
+	if rankBy, ok := request.EngineParams["rank_by"].(string); ok && rankBy != "" {
+		return strings.EqualFold(rankBy, RankByEngagement)
+	}
+	return c.rankByEngagement
+}
+
+// buildQuery translates the common filters of the request into Twitter search operators.
+// Retweets are excluded unless the query already mentions them, since they repeat the original tweet.
+func buildQuery(request *search.SearchRequest) string {
+	query := strings.TrimSpace(request.Query)
+	lowerQuery := strings.ToLower(query)
+
+	if lang := strings.ToLower(strings.TrimSpace(request.Lang)); lang != "" && !strings.Contains(lowerQuery, "lang:") {
+		// Twitter uses two-letter language codes, so "en-US" becomes "en".
+		if i := strings.IndexAny(lang, "-_"); i > 0 {
+			lang = lang[:i]
+		}
+		query += " lang:" + lang
+	}
+	if !strings.Contains(lowerQuery, "is:retweet") {
+		query += " -is:retweet"
+	}
+	return query
+}
+
+// startTimeFor returns the start_time for a time range, clamped to the window of the recent search endpoint.
+// The second return value is false if the time range is empty or unknown.
+func startTimeFor(timeRange string, now time.Time) (time.Time, bool) {
+	window, ok := search.TimeRangeDuration(timeRange)
+	if !ok {
+		return time.Time{}, false
+	}
+	// Keep a minute of margin so that the start time is still inside the window when the request arrives.
+	window = min(window, recentSearchWindow-time.Minute)
+	return now.Add(-window).UTC(), true
+}
+
+// newRateLimitError creates a RateLimitError from the x-rate-limit-* headers of a 429 response.
+func newRateLimitError(resp *http.Response, body []byte, now time.Time) *RateLimitError {
+	rateErr := &RateLimitError{
+		StatusError: resilience.NewStatusError(resp, fmt.Sprintf("twitter api rate limit exceeded, body: %s", string(body))),
+		Limit:       headerInt(resp.Header, "x-rate-limit-limit"),
+		Remaining:   headerInt(resp.Header, "x-rate-limit-remaining"),
+	}
+
+	if reset := headerInt(resp.Header, "x-rate-limit-reset"); reset > 0 {
+		rateErr.Reset = time.Unix(int64(reset), 0)
+		if wait := rateErr.Reset.Sub(now); wait > rateErr.RetryAfter {
+			rateErr.RetryAfter = wait
+		}
+		rateErr.Message = fmt.Sprintf("twitter api rate limit exceeded, resets at %s, body: %s", rateErr.Reset.Format(time.RFC3339), string(body))
 	}
+	return rateErr
+}
+
+// headerInt returns the integer value of a header, or zero if it is absent or invalid.
+func headerInt(header http.Header, name string) int {
+	value, err := strconv.Atoi(strings.TrimSpace(header.Get(name)))
+	if err != nil {
+		return 0
+	}
+	return value
+}
 
+// convertTweets converts the tweets of a response to search results.
+// Tweets whose author is missing from the includes are kept with a generic title and link.
+func convertTweets(twitterResp *TwitterSearchResponse) []*search.SearchResultItem {
 	userMap := make(map[string]UserObject)
 	if users, ok := twitterResp.Includes["users"]; ok {
 		for _, user := range users {
@@ -147,34 +413,89 @@ func (c *Client) parseResponse(body []byte, query string) (*search.SearchRespons
 		}
 	}
 
-	var results []*search.SearchResultItem
+	results := make([]*search.SearchResultItem, 0, len(twitterResp.Data))
 	for _, tweet := range twitterResp.Data {
-		user, ok := userMap[tweet.AuthorID]
-		if !ok {
-			// Skip tweet if author info is not found.
-			continue
+		metadata := map[string]interface{}{
+			"tweet_id": tweet.ID,
+		}
+
+		title := fmt.Sprintf("Tweet %s", tweet.ID)
+		tweetURL := fmt.Sprintf("https://twitter.com/i/web/status/%s", tweet.ID)
+		if user, ok := userMap[tweet.AuthorID]; ok {
+			title = fmt.Sprintf("Tweet from %s (@%s)", user.Name, user.Username)
+			tweetURL = fmt.Sprintf("https://twitter.com/%s/status/%s", user.Username, tweet.ID)
+			metadata["author_name"] = user.Name
+			metadata["author_username"] = user.Username
+			metadata["author_verified"] = user.Verified
+		}
+
+		if tweet.Lang != "" {
+			metadata["lang"] = tweet.Lang
+		}
+		if tweet.ConversationID != "" {
+			metadata["conversation_id"] = tweet.ConversationID
+		}
+		if tweet.PublicMetrics != nil {
+			metadata["like_count"] = tweet.PublicMetrics.LikeCount
+			metadata["retweet_count"] = tweet.PublicMetrics.RetweetCount
+			metadata["reply_count"] = tweet.PublicMetrics.ReplyCount
+			metadata["quote_count"] = tweet.PublicMetrics.QuoteCount
+			metadata["impression_count"] = tweet.PublicMetrics.ImpressionCount
 		}
+		addEntities(metadata, tweet.Entities)
 
-		item := &search.SearchResultItem{
-			Title:       fmt.Sprintf("Tweet from %s (@%s)", user.Name, user.Username),
-			URL:         fmt.Sprintf("https://twitter.com/%s/status/%s", user.Username, tweet.ID),
+		var publishDate string
+		if !tweet.CreatedAt.IsZero() {
+			publishDate = tweet.CreatedAt.Format(time.RFC3339)
+		}
+
+		results = append(results, &search.SearchResultItem{
+			Title:       title,
+			URL:         tweetURL,
 			Description: tweet.Text,
-			PublishDate: tweet.CreatedAt.Format(time.RFC3339),
-			Rank:        len(results) + 1,
-			Metadata: map[string]interface{}{
-				"author_name":     user.Name,
-				"author_username": user.Username,
-				"tweet_id":        tweet.ID,
-			},
+			PublishDate: publishDate,
+			Score:       tweet.PublicMetrics.Engagement(),
+			Metadata:    metadata,
+		})
+	}
+	return results
+}
+
+// addEntities records the links, hashtags and mentions of a tweet in the result metadata.
+func addEntities(metadata map[string]interface{}, entities *Entities) {
+	if entities == nil {
+		return
+	}
+
+	var links, hashtags, mentions []string
+	for _, entity := range entities.URLs {
+		if entity.ExpandedURL != "" {
+			links = append(links, entity.ExpandedURL)
+		} else if entity.URL != "" {
+			links = append(links, entity.URL)
 		}
-		results = append(results, item)
+	}
+	for _, entity := range entities.Hashtags {
+		hashtags = append(hashtags, entity.Tag)
+	}
+	for _, entity := range entities.Mentions {
+		mentions = append(mentions, entity.Username)
 	}
 
-	response := &search.SearchResponse{
-		Query:      query,
-		Results:    results,
-		TotalCount: twitterResp.Meta.ResultCount,
+	if len(links) > 0 {
+		metadata["links"] = links
+	}
+	if len(hashtags) > 0 {
+		metadata["hashtags"] = hashtags
 	}
+	if len(mentions) > 0 {
+		metadata["mentions"] = mentions
+	}
+}
 
-	return response, nil
+// rankByEngagement orders the results by engagement score, keeping the API order for ties.
+func rankByEngagement(results []*search.SearchResultItem) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
 }
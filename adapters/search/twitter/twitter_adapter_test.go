@@ -0,0 +1,503 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// fakeAPI is a Twitter recent search endpoint that serves pages by their next_token and
+// records the query parameters of every request.
+type fakeAPI struct {
+	mu       sync.Mutex
+	pages    map[string]TwitterSearchResponse
+	requests []url.Values
+	status   map[string]int // Status code answered for a next_token instead of its page.
+	header   http.Header    // Headers added to every response.
+}
+
+// ServeHTTP implements http.Handler.
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tweets/search/recent" || r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	params := r.URL.Query()
+	a.requests = append(a.requests, params)
+
+	for name, values := range a.header {
+		w.Header()[name] = values
+	}
+	token := params.Get("next_token")
+	if status, ok := a.status[token]; ok {
+		http.Error(w, `{"title": "error"}`, status)
+		return
+	}
+	page, ok := a.pages[token]
+	if !ok {
+		http.Error(w, "unknown next_token "+token, http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// serveAPI starts the fake API and returns a client of it.
+func serveAPI(t *testing.T, api *fakeAPI, config ClientConfig) *Client {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	config.BaseURL = server.URL
+	config.BearerToken = "token"
+	return NewClient(&config)
+}
+
+// tweetPage returns a page of tweets with the IDs and like counts, written by user 1.
+func tweetPage(next string, likes map[string]int, ids ...string) TwitterSearchResponse {
+	page := TwitterSearchResponse{
+		Includes: map[string][]UserObject{"users": {{ID: "1", Name: "Gopher", Username: "gopher"}}},
+		Meta:     MetaObject{ResultCount: len(ids), NextToken: next},
+	}
+	for _, id := range ids {
+		page.Data = append(page.Data, TweetObject{
+			ID:            id,
+			Text:          "tweet " + id,
+			AuthorID:      "1",
+			PublicMetrics: &PublicMetrics{LikeCount: likes[id]},
+		})
+	}
+	return page
+}
+
+// tweetIDs returns the IDs of n tweets starting at the first ID.
+func tweetIDs(first, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(first + i)
+	}
+	return ids
+}
+
+// resultTweetIDs returns the tweet IDs of the results.
+func resultTweetIDs(results []*search.SearchResultItem) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i], _ = result.Metadata["tweet_id"].(string)
+	}
+	return ids
+}
+
+func TestBuildQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		request search.SearchRequest
+		want    string
+	}{
+		{"retweets excluded", search.SearchRequest{Query: " golang "}, "golang -is:retweet"},
+		{"language", search.SearchRequest{Query: "golang", Lang: "EN"}, "golang lang:en -is:retweet"},
+		{"locale", search.SearchRequest{Query: "golang", Lang: "zh-CN"}, "golang lang:zh -is:retweet"},
+		{"underscore locale", search.SearchRequest{Query: "golang", Lang: "pt_BR"}, "golang lang:pt -is:retweet"},
+		{"language already in query", search.SearchRequest{Query: "golang lang:ja", Lang: "en"}, "golang lang:ja -is:retweet"},
+		{"retweets requested", search.SearchRequest{Query: "golang is:retweet"}, "golang is:retweet"},
+		{"retweets already excluded", search.SearchRequest{Query: "golang -is:retweet"}, "golang -is:retweet"},
+		{"other filters ignored", search.SearchRequest{Query: "golang", Site: "go.dev", FileType: "pdf"}, "golang -is:retweet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildQuery(&tt.request); got != tt.want {
+				t.Errorf("buildQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartTimeFor(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.FixedZone("CST", 8*3600))
+
+	tests := []struct {
+		timeRange string
+		want      time.Time
+		wantOK    bool
+	}{
+		{"", time.Time{}, false},
+		{"decade", time.Time{}, false},
+		{"day", now.Add(-24 * time.Hour).UTC(), true},
+		{"past_week", now.Add(-recentSearchWindow + time.Minute).UTC(), true},
+		{"month", now.Add(-recentSearchWindow + time.Minute).UTC(), true},
+		{"year", now.Add(-recentSearchWindow + time.Minute).UTC(), true},
+	}
+
+	for _, tt := range tests {
+		got, ok := startTimeFor(tt.timeRange, now)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("startTimeFor(%q) = %v, %v, want %v, %v", tt.timeRange, got, ok, tt.want, tt.wantOK)
+		}
+		if ok && got.Location() != time.UTC {
+			t.Errorf("startTimeFor(%q) location = %v, want UTC", tt.timeRange, got.Location())
+		}
+	}
+}
+
+func TestNewRateLimitError(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name           string
+		header         http.Header
+		wantLimit      int
+		wantRemaining  int
+		wantReset      time.Time
+		wantRetryAfter time.Duration
+	}{
+		{
+			name: "reset header",
+			header: http.Header{
+				"X-Rate-Limit-Limit":     {"450"},
+				"X-Rate-Limit-Remaining": {"0"},
+				"X-Rate-Limit-Reset":     {"1700000090"},
+			},
+			wantLimit:      450,
+			wantReset:      time.Unix(1700000090, 0),
+			wantRetryAfter: 90 * time.Second,
+		},
+		{
+			name: "longer retry after",
+			header: http.Header{
+				"Retry-After":        {"120"},
+				"X-Rate-Limit-Reset": {"1700000030"},
+			},
+			wantReset:      time.Unix(1700000030, 0),
+			wantRetryAfter: 120 * time.Second,
+		},
+		{
+			name:   "invalid headers",
+			header: http.Header{"X-Rate-Limit-Limit": {"many"}, "X-Rate-Limit-Reset": {"soon"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: tt.header}
+			rateErr := newRateLimitError(resp, []byte("slow down"), now)
+
+			if rateErr.Limit != tt.wantLimit || rateErr.Remaining != tt.wantRemaining || !rateErr.Reset.Equal(tt.wantReset) {
+				t.Errorf("newRateLimitError() = limit %d, remaining %d, reset %v, want %d, %d, %v",
+					rateErr.Limit, rateErr.Remaining, rateErr.Reset, tt.wantLimit, tt.wantRemaining, tt.wantReset)
+			}
+			if rateErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("newRateLimitError() RetryAfter = %v, want %v", rateErr.RetryAfter, tt.wantRetryAfter)
+			}
+
+			var statusErr *resilience.StatusError
+			if !errors.As(error(rateErr), &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+				t.Errorf("newRateLimitError() does not wrap a status error with code %d", http.StatusTooManyRequests)
+			}
+		})
+	}
+}
+
+func TestEngagement(t *testing.T) {
+	var missing *PublicMetrics
+	if got := missing.Engagement(); got != 0 {
+		t.Errorf("Engagement() of missing metrics = %v, want 0", got)
+	}
+
+	metrics := &PublicMetrics{LikeCount: 10, ReplyCount: 3, RetweetCount: 2, QuoteCount: 1, ImpressionCount: 1000}
+	if got := metrics.Engagement(); got != 19 {
+		t.Errorf("Engagement() = %v, want 19", got)
+	}
+}
+
+func TestSearchRequestParameters(t *testing.T) {
+	api := &fakeAPI{pages: map[string]TwitterSearchResponse{
+		"":      tweetPage("", nil, "1"),
+		"start": tweetPage("", nil, "2"),
+	}}
+	client := serveAPI(t, api, ClientConfig{SortOrder: SortOrderRecency})
+
+	requests := []*search.SearchRequest{
+		{Query: "golang", Lang: "en", TimeRange: "day", Num: 5},
+		{Query: "golang", PageToken: "start", Num: 250, EngineParams: map[string]interface{}{
+			"sort_order": "Relevancy",
+			"start_time": "2024-05-01T00:00:00Z",
+			"end_time":   "2024-05-02T00:00:00Z",
+		}},
+	}
+	for _, request := range requests {
+		if _, err := client.Search(context.Background(), request); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+
+	first, second := api.requests[0], api.requests[1]
+	for name, want := range map[string]string{
+		"query":        "golang lang:en -is:retweet",
+		"max_results":  "10",
+		"expansions":   "author_id",
+		"tweet.fields": tweetFields,
+		"user.fields":  userFields,
+		"sort_order":   SortOrderRecency,
+		"next_token":   "",
+	} {
+		if got := first.Get(name); got != want {
+			t.Errorf("first request %s = %q, want %q", name, got, want)
+		}
+	}
+	if startTime, err := time.Parse(time.RFC3339, first.Get("start_time")); err != nil || time.Since(startTime) < 23*time.Hour {
+		t.Errorf("first request start_time = %q, want a day ago", first.Get("start_time"))
+	}
+
+	for name, want := range map[string]string{
+		"max_results": "100",
+		"sort_order":  SortOrderRelevancy,
+		"start_time":  "2024-05-01T00:00:00Z",
+		"end_time":    "2024-05-02T00:00:00Z",
+		"next_token":  "start",
+	} {
+		if got := second.Get(name); got != want {
+			t.Errorf("second request %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     map[string]TwitterSearchResponse
+		status    map[string]int
+		num       int
+		want      []string
+		wantNext  string
+		wantCalls int
+	}{
+		{
+			name: "one page",
+			pages: map[string]TwitterSearchResponse{
+				"": tweetPage("t2", nil, tweetIDs(1, 10)...),
+			},
+			num:       10,
+			want:      tweetIDs(1, 10),
+			wantNext:  "t2",
+			wantCalls: 1,
+		},
+		{
+			name: "pages until the requested number",
+			pages: map[string]TwitterSearchResponse{
+				"":   tweetPage("t2", nil, tweetIDs(1, 10)...),
+				"t2": tweetPage("t3", nil, tweetIDs(11, 10)...),
+			},
+			num:       20,
+			want:      tweetIDs(1, 20),
+			wantNext:  "t3",
+			wantCalls: 2,
+		},
+		{
+			name: "cut page has no next token",
+			pages: map[string]TwitterSearchResponse{
+				"":   tweetPage("t2", nil, tweetIDs(1, 10)...),
+				"t2": tweetPage("t3", nil, tweetIDs(11, 10)...),
+			},
+			num:       15,
+			want:      tweetIDs(1, 15),
+			wantNext:  "",
+			wantCalls: 2,
+		},
+		{
+			name: "last page",
+			pages: map[string]TwitterSearchResponse{
+				"":   tweetPage("t2", nil, tweetIDs(1, 10)...),
+				"t2": tweetPage("", nil, tweetIDs(11, 3)...),
+			},
+			num:       50,
+			want:      tweetIDs(1, 13),
+			wantCalls: 2,
+		},
+		{
+			name: "page limit",
+			pages: map[string]TwitterSearchResponse{
+				"":   tweetPage("t2", nil, tweetIDs(1, 10)...),
+				"t2": tweetPage("t3", nil, tweetIDs(11, 10)...),
+				"t3": tweetPage("t4", nil, tweetIDs(21, 10)...),
+			},
+			num:       100,
+			want:      tweetIDs(1, 20),
+			wantNext:  "t3",
+			wantCalls: 2,
+		},
+		{
+			name: "failing later page keeps earlier tweets",
+			pages: map[string]TwitterSearchResponse{
+				"": tweetPage("t2", nil, tweetIDs(1, 10)...),
+			},
+			status:    map[string]int{"t2": http.StatusServiceUnavailable},
+			num:       20,
+			want:      tweetIDs(1, 10),
+			wantNext:  "t2",
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{pages: tt.pages, status: tt.status}
+			client := serveAPI(t, api, ClientConfig{MaxPages: 2})
+
+			response, err := client.Search(context.Background(), &search.SearchRequest{Query: "golang", Num: tt.num})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got := resultTweetIDs(response.Results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() tweets = %v, want %v", got, tt.want)
+			}
+			if response.NextPageToken != tt.wantNext {
+				t.Errorf("Search() next page token = %q, want %q", response.NextPageToken, tt.wantNext)
+			}
+			if len(api.requests) != tt.wantCalls {
+				t.Errorf("API requests = %d, want %d", len(api.requests), tt.wantCalls)
+			}
+			for i, result := range response.Results {
+				if result.Rank != i+1 {
+					t.Errorf("Search() rank of tweet %s = %d, want %d", resultTweetIDs(response.Results)[i], result.Rank, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchRanksByEngagement(t *testing.T) {
+	likes := map[string]int{"1": 5, "2": 50, "3": 5, "4": 500}
+	api := &fakeAPI{pages: map[string]TwitterSearchResponse{
+		"": tweetPage("t2", likes, "1", "2", "3", "4"),
+	}}
+
+	tests := []struct {
+		name     string
+		config   ClientConfig
+		params   map[string]interface{}
+		num      int
+		want     []string
+		wantNext string
+	}{
+		{"API order", ClientConfig{}, nil, 4, []string{"1", "2", "3", "4"}, "t2"},
+		{"configured", ClientConfig{RankByEngagement: true}, nil, 4, []string{"4", "2", "1", "3"}, "t2"},
+		{"engine parameter", ClientConfig{}, map[string]interface{}{"rank_by": "Engagement"}, 4, []string{"4", "2", "1", "3"}, "t2"},
+		{"engine parameter overrides the configuration", ClientConfig{RankByEngagement: true}, map[string]interface{}{"rank_by": "api"}, 4, []string{"1", "2", "3", "4"}, "t2"},
+		{"most engaging of a cut page", ClientConfig{RankByEngagement: true}, nil, 2, []string{"4", "2"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := serveAPI(t, api, tt.config)
+			response, err := client.Search(context.Background(), &search.SearchRequest{Query: "golang", Num: tt.num, EngineParams: tt.params})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got := resultTweetIDs(response.Results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() tweets = %v, want %v", got, tt.want)
+			}
+			if response.NextPageToken != tt.wantNext {
+				t.Errorf("Search() next page token = %q, want %q", response.NextPageToken, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSearchConvertsTweets(t *testing.T) {
+	createdAt := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	page := TwitterSearchResponse{
+		Data: []TweetObject{
+			{
+				ID:             "100",
+				Text:           "Go 1.22 is out",
+				AuthorID:       "1",
+				CreatedAt:      createdAt,
+				Lang:           "en",
+				ConversationID: "99",
+				PublicMetrics:  &PublicMetrics{LikeCount: 10, RetweetCount: 2},
+				Entities: &Entities{
+					URLs:     []URLEntity{{URL: "https://t.co/a", ExpandedURL: "https://go.dev/blog/go1.22"}, {URL: "https://t.co/b"}},
+					Hashtags: []TagEntity{{Tag: "golang"}},
+					Mentions: []MentionEntity{{Username: "golang"}},
+				},
+			},
+			{ID: "101", Text: "no author", AuthorID: "2"},
+		},
+		Includes: map[string][]UserObject{"users": {{ID: "1", Name: "Gopher", Username: "gopher", Verified: true}}},
+	}
+	client := serveAPI(t, &fakeAPI{pages: map[string]TwitterSearchResponse{"": page}}, ClientConfig{})
+
+	response, err := client.Search(context.Background(), &search.SearchRequest{Query: "golang", Num: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(response.Results) != 2 {
+		t.Fatalf("Search() returned %d results, want 2", len(response.Results))
+	}
+
+	tweet := response.Results[0]
+	if tweet.Title != "Tweet from Gopher (@gopher)" || tweet.URL != "https://twitter.com/gopher/status/100" ||
+		tweet.Description != "Go 1.22 is out" || tweet.PublishDate != "2024-05-10T12:00:00Z" || tweet.Score != 14 {
+		t.Errorf("Search() tweet = %+v", *tweet)
+	}
+	wantMetadata := map[string]interface{}{
+		"tweet_id":         "100",
+		"author_name":      "Gopher",
+		"author_username":  "gopher",
+		"author_verified":  true,
+		"lang":             "en",
+		"conversation_id":  "99",
+		"like_count":       10,
+		"retweet_count":    2,
+		"reply_count":      0,
+		"quote_count":      0,
+		"impression_count": 0,
+		"links":            []string{"https://go.dev/blog/go1.22", "https://t.co/b"},
+		"hashtags":         []string{"golang"},
+		"mentions":         []string{"golang"},
+	}
+	if !reflect.DeepEqual(tweet.Metadata, wantMetadata) {
+		t.Errorf("Search() tweet metadata = %v, want %v", tweet.Metadata, wantMetadata)
+	}
+
+	orphan := response.Results[1]
+	if orphan.Title != "Tweet 101" || orphan.URL != "https://twitter.com/i/web/status/101" || orphan.PublishDate != "" {
+		t.Errorf("Search() tweet without author = %+v", *orphan)
+	}
+}
+
+func TestSearchRateLimited(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	api := &fakeAPI{
+		status: map[string]int{"": http.StatusTooManyRequests},
+		header: http.Header{
+			"X-Rate-Limit-Limit":     {"450"},
+			"X-Rate-Limit-Remaining": {"0"},
+			"X-Rate-Limit-Reset":     {fmt.Sprint(reset)},
+		},
+	}
+	client := serveAPI(t, api, ClientConfig{})
+
+	_, err := client.Search(context.Background(), &search.SearchRequest{Query: "golang"})
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("Search() error = %v, want a rate limit error", err)
+	}
+	if rateErr.Limit != 450 || rateErr.Reset.Unix() != reset || rateErr.RetryAfter < 59*time.Minute {
+		t.Errorf("Search() rate limit error = limit %d, reset %v, retry after %v", rateErr.Limit, rateErr.Reset, rateErr.RetryAfter)
+	}
+}
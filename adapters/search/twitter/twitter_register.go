@@ -1,46 +1,83 @@
 package twitter
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
 )
 
+// engineName is the name the Twitter adapter is registered under.
+const engineName = "twitter"
+
+// ClientOptions holds the keys of the `config` section of the Twitter engine.
+// The API base URL is taken from the `base_url` field of the engine, like for every other engine.
+type ClientOptions struct {
+	Timeout          time.Duration `mapstructure:"timeout" description:"Request timeout, in seconds or as a duration string"`
+	SortOrder        string        `mapstructure:"sort_order" description:"Result order: recency or relevancy, empty for the API default"`
+	MaxPages         int           `mapstructure:"max_pages" description:"Maximum number of pages fetched per search"`
+	RankByEngagement bool          `mapstructure:"rank_by_engagement" description:"Sort tweets by likes, retweets, replies and quotes"`
+}
+
+// defaultClientOptions returns the options used for the keys that are not configured.
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout:  DefaultTimeout,
+		MaxPages: DefaultMaxPages,
+	}
+}
+
+// Validate implements config.ConfigValidator.
+func (o *ClientOptions) Validate() error {
+	switch o.SortOrder {
+	case "", SortOrderRecency, SortOrderRelevancy:
+	default:
+		return config.InvalidConfigValue("sort_order", "must be %q or %q, got %q", SortOrderRecency, SortOrderRelevancy, o.SortOrder)
+	}
+	if o.MaxPages <= 0 {
+		return config.InvalidConfigValue("max_pages", "must be positive, got %d", o.MaxPages)
+	}
+	if o.Timeout <= 0 {
+		return config.InvalidConfigValue("timeout", "must be positive, got %s", o.Timeout)
+	}
+	return nil
+}
+
 // TwitterAdapterCreator creates Twitter adapter instances.
 type TwitterAdapterCreator struct{}
 
 // CreateAdapter creates a new Twitter adapter from the engine configuration.
 func (c *TwitterAdapterCreator) CreateAdapter(engineConfig types.EngineConfig) (search.SearchAdapter, error) {
-	bearerToken := engineConfig.SecretKey
-	if bearerToken == "" {
-		// The BearerToken is often stored in the 'secret_key' field for consistency.
-		return nil, fmt.Errorf("twitter bearer_token (secret_key) is missing from credentials")
-	}
-
-	config := &ClientConfig{
-		BearerToken: bearerToken,
+	// The bearer token is stored in the 'secret_key' field for consistency.
+	if engineConfig.SecretKey == "" {
+		return nil, &config.ConfigError{Component: config.ComponentSearchEngine, Name: engineName, Key: "secret_key", Err: config.ErrMissingConfigValue}
 	}
 
-	if engineConfig.Config != nil {
-		if baseURL, ok := engineConfig.Config["base_url"].(string); ok {
-			config.BaseURL = baseURL
-		}
-		if timeout, ok := engineConfig.Config["timeout"]; ok {
-			switch t := timeout.(type) {
-			case int:
-				config.Timeout = time.Duration(t) * time.Second
-			case float64:
-				config.Timeout = time.Duration(t) * time.Second
-			}
-		}
+	options := defaultClientOptions()
+	if err := config.DecodeAdapterConfig(config.ComponentSearchEngine, engineName, engineConfig.Config, &options); err != nil {
+		return nil, err
 	}
 
-	return NewClient(config), nil
+	return NewClient(&ClientConfig{
+		BearerToken:      engineConfig.SecretKey,
+		BaseURL:          engineConfig.BaseURL,
+		Timeout:          options.Timeout,
+		SortOrder:        options.SortOrder,
+		MaxPages:         options.MaxPages,
+		RankByEngagement: options.RankByEngagement,
+	}), nil
 }
 
 // init registers the Twitter adapter creator with the global registry.
 func init() {
-	search.RegisterAdapterCreator("twitter", (&TwitterAdapterCreator{}).CreateAdapter)
+	search.RegisterAdapterCreator(engineName, (&TwitterAdapterCreator{}).CreateAdapter)
+	search.RegisterAdapterDescriptor(engineName, search.AdapterDescriptor{
+		Description: "Twitter (X) API v2 recent search",
+		Capabilities: search.AdapterCapabilities{
+			Pagination: true,
+			Filters:    []string{search.FilterTimeRange, search.FilterLang},
+		},
+		ConfigSchema: config.ConfigSchema(defaultClientOptions()),
+	})
 }
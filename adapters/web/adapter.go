@@ -65,6 +65,9 @@ type WebContent struct {
 	// Extracted structured data
 	Links  []Link  `json:"links,omitempty"`
 	Images []Image `json:"images,omitempty"`
+
+	// Scraper is the scraper that produced the page, set by the web strategy.
+	Scraper WebScraper `json:"scraper,omitempty"`
 }
 
 // Link represents a hyperlink.
@@ -1,7 +1,10 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
@@ -10,34 +13,146 @@ import (
 // AdapterCreatorFunc defines the function signature for an adapter creator.
 type AdapterCreatorFunc func(scraperConfig types.WebScraperConfig) (WebAdapter, error)
 
-// adapterCreators is a global registry for adapter creator functions.
-var adapterCreators = make(map[string]AdapterCreatorFunc)
+// AdapterCapabilities describes the optional features a web adapter supports.
+type AdapterCapabilities struct {
+	// Formats lists the content formats the adapter can return.
+	Formats []Format `json:"formats,omitempty"`
+
+	// Links reports whether the adapter extracts the links of a page.
+	Links bool `json:"links"`
+
+	// Images reports whether the adapter extracts the images of a page.
+	Images bool `json:"images"`
+
+	// JavaScript reports whether the adapter renders JavaScript before extracting the content.
+	JavaScript bool `json:"javascript"`
+}
+
+// AdapterDescriptor describes a web adapter implementation.
+type AdapterDescriptor struct {
+	// Description is a short human-readable description of the adapter.
+	Description string `json:"description,omitempty"`
+
+	// Capabilities lists the optional features the adapter supports.
+	Capabilities AdapterCapabilities `json:"capabilities"`
+
+	// ConfigSchema describes the keys of the scraper-specific `config` section.
+	ConfigSchema []types.ConfigField `json:"config_schema,omitempty"`
+}
+
+var (
+	// creatorsMu guards adapterCreators and adapterDescriptors.
+	creatorsMu sync.RWMutex
+
+	// adapterCreators is a global registry for adapter creator functions.
+	adapterCreators = make(map[string]AdapterCreatorFunc)
+
+	// adapterDescriptors holds the descriptions registered by the adapter packages.
+	adapterDescriptors = make(map[string]AdapterDescriptor)
+)
+
+var (
+	// configMu serializes RegisterAllWebAdapters.
+	configMu sync.Mutex
+
+	// configScrapers holds the scrapers registered from the configuration by the last RegisterAllWebAdapters.
+	configScrapers = make(map[string]bool)
+)
 
 // RegisterAdapterCreator registers an adapter creator function for a given scraper name.
 func RegisterAdapterCreator(scraperName string, creator AdapterCreatorFunc) {
+	creatorsMu.Lock()
+	defer creatorsMu.Unlock()
 	adapterCreators[scraperName] = creator
 }
 
+// RegisterAdapterDescriptor registers the description of the adapter of a given scraper name,
+// which is reported by DescribeWebAdapter and ListWebAdapters.
+func RegisterAdapterDescriptor(scraperName string, descriptor AdapterDescriptor) {
+	creatorsMu.Lock()
+	defer creatorsMu.Unlock()
+	adapterDescriptors[scraperName] = descriptor
+}
+
+// getAdapterCreator returns the adapter creator registered for the scraper name.
+func getAdapterCreator(scraperName string) (AdapterCreatorFunc, bool) {
+	creatorsMu.RLock()
+	defer creatorsMu.RUnlock()
+	creator, exists := adapterCreators[scraperName]
+	return creator, exists
+}
+
+// hasAdapterCreator reports whether an adapter creator is registered for the scraper name.
+func hasAdapterCreator(scraperName string) bool {
+	_, exists := getAdapterCreator(scraperName)
+	return exists
+}
+
+// getAdapterDescriptor returns the description registered for the scraper name.
+func getAdapterDescriptor(scraperName string) (AdapterDescriptor, bool) {
+	creatorsMu.RLock()
+	defer creatorsMu.RUnlock()
+	descriptor, exists := adapterDescriptors[scraperName]
+	return descriptor, exists
+}
+
+// adapterCreatorNames returns the scraper names that have an adapter creator, sorted by name.
+func adapterCreatorNames() []string {
+	creatorsMu.RLock()
+	defer creatorsMu.RUnlock()
+
+	names := make([]string, 0, len(adapterCreators))
+	for name := range adapterCreators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // RegisterAllWebAdapters registers all web adapters based on the application configuration.
+// It can be called again after the configuration was updated (see config.UpdateConfig): scrapers
+// enabled in the configuration are registered anew, so that the strategies recreate their adapters
+// with the new settings, and scrapers that are no longer enabled are unregistered. The configuration of
+// every enabled scraper is validated first; scrapers with an invalid configuration are not registered and
+// their errors are returned together.
 func RegisterAllWebAdapters() error {
 	webConfig := config.GetWebConfig()
 	if webConfig == nil {
 		return fmt.Errorf("web adapter configuration not initialized")
 	}
 
+	configMu.Lock()
+	defer configMu.Unlock()
+
 	// Iterate through the scrapers in the config and dynamically register enabled adapters.
+	enabled := make(map[string]bool)
+	var errs []error
 	for scraperName, scraperConfig := range webConfig.Scrapers {
 		if scraperConfig.Enabled {
 			// Use a local variable to avoid closure capture issues.
 			name := scraperName
+			// Create the adapter once so that configuration errors surface at registration.
+			if _, err := createAdapterFromConfig(name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
 			// Register the adapter using a generic factory function.
 			RegisterWebAdapter(WebScraper(name), func() (WebAdapter, error) {
 				return createAdapterFromConfig(name)
 			})
+			enabled[name] = true
+		}
+	}
+
+	// Unregister the scrapers that were registered from an earlier configuration but are no longer enabled.
+	for name := range configScrapers {
+		if !enabled[name] {
+			UnregisterWebAdapter(WebScraper(name))
 		}
 	}
+	configScrapers = enabled
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // createAdapterFromConfig is a generic adapter creation function.
@@ -58,11 +173,17 @@ func createAdapterFromConfig(scraperName string) (WebAdapter, error) {
 	}
 
 	// Find the corresponding adapter creator.
-	creator, exists := adapterCreators[scraperName]
+	creator, exists := getAdapterCreator(scraperName)
 	if !exists {
 		return nil, fmt.Errorf("adapter creator not registered for %s", scraperName)
 	}
 
 	// Use the creator to create the adapter instance.
-	return creator(scraperConfig)
+	adapter, err := creator(scraperConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scrape batches concurrently within the configured limits.
+	return withBatch(adapter, scraperConfig.Batch), nil
 }
@@ -0,0 +1,321 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/anboat/strato-sdk/config/types"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// Default limits of a batch scrape.
+const (
+	DefaultBatchConcurrency          = 4
+	DefaultPerDomainBatchConcurrency = 2
+)
+
+// ErrEmptyContent is reported for a page that was fetched but has no content.
+var ErrEmptyContent = errors.New("page has no content")
+
+// ScrapeErrorKind classifies why a page could not be scraped.
+type ScrapeErrorKind string
+
+const (
+	// ScrapeErrorTimeout means the page did not answer in time.
+	ScrapeErrorTimeout ScrapeErrorKind = "timeout"
+	// ScrapeErrorStatus means the page or scraping API answered with an unsuccessful HTTP status.
+	ScrapeErrorStatus ScrapeErrorKind = "http_status"
+	// ScrapeErrorBlocked means the site refused access, e.g. with 401, 403 or 451.
+	ScrapeErrorBlocked ScrapeErrorKind = "blocked"
+	// ScrapeErrorEmpty means the page was fetched but has no content.
+	ScrapeErrorEmpty ScrapeErrorKind = "empty"
+	// ScrapeErrorDisallowed means robots.txt disallows the page; it is not tried on other scrapers.
+	ScrapeErrorDisallowed ScrapeErrorKind = "disallowed"
+	// ScrapeErrorOther covers every other failure, e.g. DNS errors or unsupported content.
+	ScrapeErrorOther ScrapeErrorKind = "other"
+)
+
+// ScrapeError is the failure to scrape a single URL.
+type ScrapeError struct {
+	// URL is the page that could not be scraped.
+	URL string
+	// Kind classifies the failure.
+	Kind ScrapeErrorKind
+	// StatusCode is the HTTP status for ScrapeErrorStatus and ScrapeErrorBlocked, zero otherwise.
+	StatusCode int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ScrapeError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("scraping %s failed (%s, status %d): %v", e.URL, e.Kind, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("scraping %s failed (%s): %v", e.URL, e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
+
+// NewScrapeError classifies the error of scraping a URL. An error that already is a
+// *ScrapeError is returned unchanged.
+//
+// Parameters:
+//   - url: The page that could not be scraped.
+//   - err: The error returned by the scraper.
+//
+// Returns:
+//   - *ScrapeError: The classified error, or nil if err is nil.
+func NewScrapeError(url string, err error) *ScrapeError {
+	if err == nil {
+		return nil
+	}
+	var scrapeErr *ScrapeError
+	if errors.As(err, &scrapeErr) {
+		return scrapeErr
+	}
+
+	result := &ScrapeError{URL: url, Kind: ScrapeErrorOther, Err: err}
+	var statusErr *resilience.StatusError
+	var disallowedErr *DisallowedError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &disallowedErr):
+		result.Kind = ScrapeErrorDisallowed
+	case errors.As(err, &statusErr):
+		result.StatusCode = statusErr.StatusCode
+		switch statusErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusUnavailableForLegalReasons:
+			result.Kind = ScrapeErrorBlocked
+		case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+			result.Kind = ScrapeErrorTimeout
+		default:
+			result.Kind = ScrapeErrorStatus
+		}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		result.Kind = ScrapeErrorTimeout
+	case errors.Is(err, ErrEmptyContent):
+		result.Kind = ScrapeErrorEmpty
+	}
+	return result
+}
+
+// ScrapeResult is the outcome of scraping a single URL of a batch.
+type ScrapeResult struct {
+	// URL is the requested page.
+	URL string `json:"url"`
+	// Content is the scraped page, nil if scraping failed.
+	Content *WebContent `json:"content,omitempty"`
+	// Err is the failure, nil if scraping succeeded.
+	Err *ScrapeError `json:"-"`
+	// Scraper is the scraper that produced the page, or that failed last. It is set by the web strategy.
+	Scraper WebScraper `json:"scraper,omitempty"`
+}
+
+// OK reports whether the page was scraped.
+func (r *ScrapeResult) OK() bool {
+	return r.Err == nil && r.Content != nil
+}
+
+// BatchOptions bounds the concurrency of a batch scrape.
+type BatchOptions struct {
+	// Concurrency is the number of pages scraped at the same time; zero uses DefaultBatchConcurrency.
+	Concurrency int
+	// PerDomainConcurrency is the number of pages of the same host scraped at the same time;
+	// zero uses DefaultPerDomainBatchConcurrency.
+	PerDomainConcurrency int
+}
+
+// BatchScraper is implemented by web adapters that scrape a batch of URLs with per-URL results.
+type BatchScraper interface {
+	// ScrapeBatch scrapes the URLs and returns one result per URL, in the order of the URLs.
+	ScrapeBatch(ctx context.Context, urls []string, options *ScrapeOptions) []*ScrapeResult
+}
+
+// ScrapeBatch scrapes the URLs with the adapter and returns one result per URL, in the order of the URLs.
+// Adapters that do not implement BatchScraper are scraped page by page with the default batch limits.
+func ScrapeBatch(ctx context.Context, adapter WebAdapter, urls []string, options *ScrapeOptions) []*ScrapeResult {
+	if batcher, ok := adapter.(BatchScraper); ok {
+		return batcher.ScrapeBatch(ctx, urls, options)
+	}
+	return ScrapeConcurrently(ctx, adapter.Scrape, urls, options, BatchOptions{})
+}
+
+// ScrapeConcurrently scrapes the URLs with a bounded pool of workers, limiting the number of pages
+// of the same host that are scraped at the same time. Pages without content are reported as
+// ScrapeErrorEmpty. It is the building block for the batch scraping of adapters.
+//
+// Parameters:
+//   - ctx: A context.Context that bounds the whole batch.
+//   - scrape: Scrapes a single page, usually the Scrape method of an adapter.
+//   - urls: The pages to scrape.
+//   - options: The scraping options passed to every call.
+//   - batch: The concurrency limits.
+//
+// Returns:
+//   - []*ScrapeResult: One result per URL, in the order of the URLs.
+func ScrapeConcurrently(ctx context.Context, scrape func(ctx context.Context, url string, options *ScrapeOptions) (*WebContent, error), urls []string, options *ScrapeOptions, batch BatchOptions) []*ScrapeResult {
+	concurrency := batch.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	perDomain := batch.PerDomainConcurrency
+	if perDomain <= 0 {
+		perDomain = DefaultPerDomainBatchConcurrency
+	}
+
+	results := make([]*ScrapeResult, len(urls))
+	domains := newDomainLimiter(perDomain)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(urls)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index] = scrapeOne(ctx, scrape, domains, urls[index], options)
+			}
+		}()
+	}
+
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// scrapeOne scrapes a single page of a batch, holding a slot of its host while it runs.
+func scrapeOne(ctx context.Context, scrape func(ctx context.Context, url string, options *ScrapeOptions) (*WebContent, error), domains *domainLimiter, pageURL string, options *ScrapeOptions) *ScrapeResult {
+	result := &ScrapeResult{URL: pageURL}
+
+	release, err := domains.acquire(ctx, hostOf(pageURL))
+	if err != nil {
+		result.Err = NewScrapeError(pageURL, err)
+		return result
+	}
+	defer release()
+
+	content, err := scrape(ctx, pageURL, options)
+	switch {
+	case err != nil:
+		result.Err = NewScrapeError(pageURL, err)
+	case content == nil || strings.TrimSpace(content.Content) == "":
+		result.Err = NewScrapeError(pageURL, ErrEmptyContent)
+	default:
+		result.Content = content
+	}
+	return result
+}
+
+// BatchContents returns the pages that were scraped, in the order of the URLs. If no page was
+// scraped, it returns the errors of all pages joined, so that callers can fall back.
+func BatchContents(results []*ScrapeResult) ([]*WebContent, error) {
+	contents := make([]*WebContent, 0, len(results))
+	var errs []error
+	for _, result := range results {
+		if result.OK() {
+			contents = append(contents, result.Content)
+		} else if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	if len(contents) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return contents, nil
+}
+
+// domainLimiter limits the number of concurrent requests per host.
+type domainLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+// newDomainLimiter creates a limiter that allows the given number of concurrent requests per host.
+func newDomainLimiter(limit int) *domainLimiter {
+	return &domainLimiter{limit: limit, slots: make(map[string]chan struct{})}
+}
+
+// acquire waits for a slot of the host and returns the function that releases it.
+func (l *domainLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	l.mu.Lock()
+	slots, exists := l.slots[host]
+	if !exists {
+		slots = make(chan struct{}, l.limit)
+		l.slots[host] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// hostOf returns the lower-case host of a URL, or the URL itself if it cannot be parsed.
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return rawURL
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// batchAdapter wraps a web adapter so that its batches are scraped concurrently within the
+// scraper's configured limits.
+type batchAdapter struct {
+	adapter WebAdapter
+	batch   BatchOptions
+}
+
+// withBatch wraps the adapter in the batch limits configured for the scraper.
+// Unset limits fall back to DefaultBatchConcurrency and DefaultPerDomainBatchConcurrency.
+func withBatch(adapter WebAdapter, batch types.BatchConfig) WebAdapter {
+	return &batchAdapter{
+		adapter: adapter,
+		batch: BatchOptions{
+			Concurrency:          batch.Concurrency,
+			PerDomainConcurrency: batch.PerDomainConcurrency,
+		},
+	}
+}
+
+// Scrape scrapes a single page.
+func (a *batchAdapter) Scrape(ctx context.Context, url string, options *ScrapeOptions) (*WebContent, error) {
+	return a.adapter.Scrape(ctx, url, options)
+}
+
+// ScrapeMultiple scrapes the pages concurrently and returns the pages that were scraped.
+func (a *batchAdapter) ScrapeMultiple(ctx context.Context, urls []string, options *ScrapeOptions) ([]*WebContent, error) {
+	return BatchContents(a.ScrapeBatch(ctx, urls, options))
+}
+
+// ScrapeBatch implements BatchScraper.
+func (a *batchAdapter) ScrapeBatch(ctx context.Context, urls []string, options *ScrapeOptions) []*ScrapeResult {
+	return ScrapeConcurrently(ctx, a.adapter.Scrape, urls, options, a.batch)
+}
+
+// batchOptionsOf returns the batch limits configured for the adapter, or the default limits
+// if the adapter was not created from the configuration.
+func batchOptionsOf(adapter WebAdapter) BatchOptions {
+	if batched, ok := adapter.(*batchAdapter); ok {
+		return batched.batch
+	}
+	return BatchOptions{}
+}
@@ -0,0 +1,293 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anboat/strato-sdk/config/types"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// fakeAdapter is a web adapter whose pages are answered by a function, recording the calls
+// and the number of concurrent calls in total and per host.
+type fakeAdapter struct {
+	scrape func(ctx context.Context, url string) (*WebContent, error)
+	delay  time.Duration
+
+	mu           sync.Mutex
+	calls        []string
+	inFlight     int
+	maxInFlight  int
+	hostInFlight map[string]int
+	maxPerHost   map[string]int
+}
+
+// newFakeAdapter creates a fake adapter that answers every page with the function.
+func newFakeAdapter(scrape func(ctx context.Context, url string) (*WebContent, error)) *fakeAdapter {
+	return &fakeAdapter{
+		scrape:       scrape,
+		hostInFlight: make(map[string]int),
+		maxPerHost:   make(map[string]int),
+	}
+}
+
+// Scrape implements WebAdapter.
+func (a *fakeAdapter) Scrape(ctx context.Context, url string, options *ScrapeOptions) (*WebContent, error) {
+	host := hostOf(url)
+	a.mu.Lock()
+	a.calls = append(a.calls, url)
+	a.inFlight++
+	a.maxInFlight = max(a.maxInFlight, a.inFlight)
+	a.hostInFlight[host]++
+	a.maxPerHost[host] = max(a.maxPerHost[host], a.hostInFlight[host])
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		a.inFlight--
+		a.hostInFlight[host]--
+		a.mu.Unlock()
+	}()
+
+	if a.delay > 0 {
+		select {
+		case <-time.After(a.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return a.scrape(ctx, url)
+}
+
+// ScrapeMultiple implements WebAdapter.
+func (a *fakeAdapter) ScrapeMultiple(ctx context.Context, urls []string, options *ScrapeOptions) ([]*WebContent, error) {
+	return BatchContents(ScrapeConcurrently(ctx, a.Scrape, urls, options, BatchOptions{}))
+}
+
+// called returns the pages scraped so far, in the order of the calls.
+func (a *fakeAdapter) called() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.calls...)
+}
+
+// pageContent answers every page with content naming the page.
+func pageContent(ctx context.Context, url string) (*WebContent, error) {
+	return &WebContent{URL: url, Content: "content of " + url}, nil
+}
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestNewScrapeError(t *testing.T) {
+	const url = "https://example.com/page"
+	existing := &ScrapeError{URL: url, Kind: ScrapeErrorBlocked, StatusCode: http.StatusForbidden, Err: errors.New("forbidden")}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   ScrapeErrorKind
+		wantStatus int
+	}{
+		{name: "forbidden", err: &resilience.StatusError{StatusCode: http.StatusForbidden}, wantKind: ScrapeErrorBlocked, wantStatus: http.StatusForbidden},
+		{name: "unauthorized", err: &resilience.StatusError{StatusCode: http.StatusUnauthorized}, wantKind: ScrapeErrorBlocked, wantStatus: http.StatusUnauthorized},
+		{name: "unavailable for legal reasons", err: &resilience.StatusError{StatusCode: http.StatusUnavailableForLegalReasons}, wantKind: ScrapeErrorBlocked, wantStatus: http.StatusUnavailableForLegalReasons},
+		{name: "request timeout", err: &resilience.StatusError{StatusCode: http.StatusRequestTimeout}, wantKind: ScrapeErrorTimeout, wantStatus: http.StatusRequestTimeout},
+		{name: "gateway timeout", err: &resilience.StatusError{StatusCode: http.StatusGatewayTimeout}, wantKind: ScrapeErrorTimeout, wantStatus: http.StatusGatewayTimeout},
+		{name: "not found", err: &resilience.StatusError{StatusCode: http.StatusNotFound}, wantKind: ScrapeErrorStatus, wantStatus: http.StatusNotFound},
+		{name: "server error wrapped", err: fmt.Errorf("jina: %w", &resilience.StatusError{StatusCode: http.StatusBadGateway}), wantKind: ScrapeErrorStatus, wantStatus: http.StatusBadGateway},
+		{name: "deadline exceeded", err: fmt.Errorf("request failed: %w", context.DeadlineExceeded), wantKind: ScrapeErrorTimeout},
+		{name: "network timeout", err: &net.OpError{Op: "read", Err: timeoutError{}}, wantKind: ScrapeErrorTimeout},
+		{name: "empty content", err: ErrEmptyContent, wantKind: ScrapeErrorEmpty},
+		{name: "disallowed", err: &DisallowedError{URL: url, UserAgent: DefaultRobotsUserAgent}, wantKind: ScrapeErrorDisallowed},
+		{name: "other", err: errors.New("no such host"), wantKind: ScrapeErrorOther},
+		{name: "already classified", err: fmt.Errorf("wrapped: %w", existing), wantKind: ScrapeErrorBlocked, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewScrapeError(url, tt.err)
+			if got.Kind != tt.wantKind || got.StatusCode != tt.wantStatus {
+				t.Errorf("NewScrapeError() = %s/%d, want %s/%d", got.Kind, got.StatusCode, tt.wantKind, tt.wantStatus)
+			}
+			if got.URL != url {
+				t.Errorf("URL = %q, want %q", got.URL, url)
+			}
+			if !errors.Is(got, tt.err) && got != existing {
+				t.Errorf("NewScrapeError() does not wrap %v", tt.err)
+			}
+		})
+	}
+
+	if got := NewScrapeError(url, nil); got != nil {
+		t.Errorf("NewScrapeError(nil) = %v, want nil", got)
+	}
+}
+
+func TestScrapeConcurrentlyOrder(t *testing.T) {
+	failure := &resilience.StatusError{StatusCode: http.StatusNotFound, Message: "not found"}
+	urls := []string{
+		"https://a.example/1",
+		"https://b.example/missing",
+		"https://c.example/empty",
+		"https://d.example/2",
+		"https://e.example/3",
+	}
+	adapter := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		// Answer the later pages first, so that the results arrive out of order.
+		switch url {
+		case urls[0]:
+			time.Sleep(30 * time.Millisecond)
+		case urls[3]:
+			time.Sleep(15 * time.Millisecond)
+		case urls[1]:
+			return nil, failure
+		case urls[2]:
+			return &WebContent{URL: url, Content: "  \n"}, nil
+		}
+		return pageContent(ctx, url)
+	})
+
+	results := ScrapeConcurrently(context.Background(), adapter.Scrape, urls, nil, BatchOptions{Concurrency: len(urls)})
+	if len(results) != len(urls) {
+		t.Fatalf("got %d results, want %d", len(results), len(urls))
+	}
+	for i, result := range results {
+		if result.URL != urls[i] {
+			t.Errorf("results[%d].URL = %q, want %q", i, result.URL, urls[i])
+		}
+	}
+
+	for _, i := range []int{0, 3, 4} {
+		if !results[i].OK() || results[i].Content.URL != urls[i] {
+			t.Errorf("results[%d] = %+v, want the content of %s", i, results[i], urls[i])
+		}
+	}
+	if err := results[1].Err; err == nil || err.Kind != ScrapeErrorStatus || !errors.Is(err, failure) {
+		t.Errorf("results[1].Err = %v, want the status error", err)
+	}
+	if err := results[2].Err; err == nil || err.Kind != ScrapeErrorEmpty || results[2].Content != nil {
+		t.Errorf("results[2] = %+v, want an empty content error", results[2])
+	}
+}
+
+func TestScrapeConcurrentlyLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		batch         BatchOptions
+		wantMax       int
+		wantPerDomain int
+	}{
+		{name: "defaults", batch: BatchOptions{}, wantMax: DefaultBatchConcurrency, wantPerDomain: DefaultPerDomainBatchConcurrency},
+		{name: "per domain cap", batch: BatchOptions{Concurrency: 6, PerDomainConcurrency: 1}, wantMax: 6, wantPerDomain: 1},
+		{name: "total cap", batch: BatchOptions{Concurrency: 2, PerDomainConcurrency: 3}, wantMax: 2, wantPerDomain: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Eight pages on each of two hosts.
+			var urls []string
+			for i := 0; i < 8; i++ {
+				urls = append(urls, fmt.Sprintf("https://one.example/%d", i), fmt.Sprintf("https://two.example/%d", i))
+			}
+			adapter := newFakeAdapter(pageContent)
+			adapter.delay = 10 * time.Millisecond
+
+			results := ScrapeConcurrently(context.Background(), adapter.Scrape, urls, nil, tt.batch)
+			if _, err := BatchContents(results); err != nil {
+				t.Fatalf("BatchContents() error = %v", err)
+			}
+			if len(adapter.called()) != len(urls) {
+				t.Errorf("scraped %d pages, want %d", len(adapter.called()), len(urls))
+			}
+			if adapter.maxInFlight > tt.wantMax {
+				t.Errorf("%d pages scraped at the same time, want at most %d", adapter.maxInFlight, tt.wantMax)
+			}
+			for host, n := range adapter.maxPerHost {
+				if n > tt.wantPerDomain {
+					t.Errorf("%d pages of %s scraped at the same time, want at most %d", n, host, tt.wantPerDomain)
+				}
+			}
+		})
+	}
+}
+
+func TestScrapeConcurrentlyContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	adapter := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return pageContent(ctx, url)
+	})
+	results := ScrapeConcurrently(ctx, adapter.Scrape, []string{"https://example.com/1", "https://example.com/2"}, nil, BatchOptions{})
+	for _, result := range results {
+		if result.OK() || !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("result %+v, want the context error", result)
+		}
+	}
+}
+
+func TestBatchContents(t *testing.T) {
+	ok := &ScrapeResult{URL: "https://example.com/ok", Content: &WebContent{Content: "page"}}
+	failed := &ScrapeResult{URL: "https://example.com/failed", Err: NewScrapeError("https://example.com/failed", errors.New("failed"))}
+	empty := &ScrapeResult{URL: "https://example.com/empty", Err: NewScrapeError("https://example.com/empty", ErrEmptyContent)}
+
+	contents, err := BatchContents([]*ScrapeResult{failed, ok, empty})
+	if err != nil || len(contents) != 1 || contents[0] != ok.Content {
+		t.Errorf("BatchContents() = %v, %v, want the successful page", contents, err)
+	}
+
+	contents, err = BatchContents([]*ScrapeResult{failed, empty})
+	if err == nil || contents != nil {
+		t.Fatalf("BatchContents() = %v, %v, want an error", contents, err)
+	}
+	if !errors.Is(err, ErrEmptyContent) {
+		t.Errorf("error %v does not include the error of every page", err)
+	}
+
+	if contents, err := BatchContents(nil); err != nil || len(contents) != 0 {
+		t.Errorf("BatchContents(nil) = %v, %v, want no pages and no error", contents, err)
+	}
+}
+
+func TestBatchAdapterLimits(t *testing.T) {
+	fake := newFakeAdapter(pageContent)
+	fake.delay = 10 * time.Millisecond
+	adapter := withBatch(fake, types.BatchConfig{Concurrency: 3, PerDomainConcurrency: 1})
+
+	var urls []string
+	for i := 0; i < 6; i++ {
+		urls = append(urls, fmt.Sprintf("https://host%d.example/page", i%3))
+	}
+	results := ScrapeBatch(context.Background(), adapter, urls, nil)
+	if _, err := BatchContents(results); err != nil {
+		t.Fatalf("ScrapeBatch() error = %v", err)
+	}
+	if fake.maxInFlight > 3 {
+		t.Errorf("%d pages scraped at the same time, want at most 3", fake.maxInFlight)
+	}
+	for host, n := range fake.maxPerHost {
+		if n > 1 {
+			t.Errorf("%d pages of %s scraped at the same time, want at most 1", n, host)
+		}
+	}
+	if got := batchOptionsOf(adapter); got != (BatchOptions{Concurrency: 3, PerDomainConcurrency: 1}) {
+		t.Errorf("batchOptionsOf() = %+v", got)
+	}
+	if got := batchOptionsOf(fake); got != (BatchOptions{}) {
+		t.Errorf("batchOptionsOf() of an unwrapped adapter = %+v, want the defaults", got)
+	}
+}
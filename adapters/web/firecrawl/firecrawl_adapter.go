@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/anboat/strato-sdk/adapters/web"
 	"github.com/anboat/strato-sdk/pkg/logging"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 	"io"
 	"net/http"
 	"time"
@@ -140,7 +141,7 @@ func (c *Client) Scrape(ctx context.Context, targetURL string, options *web.Scra
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(body))
+		return nil, resilience.NewStatusError(resp, fmt.Sprintf("request failed with status code %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Parse response
@@ -149,19 +150,14 @@ func (c *Client) Scrape(ctx context.Context, targetURL string, options *web.Scra
 
 // ScrapeMultiple implements the web.WebAdapter interface for multiple URLs.
 func (c *Client) ScrapeMultiple(ctx context.Context, urls []string, options *web.ScrapeOptions) ([]*web.WebContent, error) {
-	// The Firecrawl scrape API does not support multiple URLs in a single request.
-	// We will iterate and scrape them one by one.
-	var results []*web.WebContent
-	for _, url := range urls {
-		result, err := c.Scrape(ctx, url, options)
-		if err != nil {
-			// In a real-world scenario, you might want to collect errors.
-			// For now, we'll just log or handle the error for the individual URL and skip it.
-			continue
-		}
-		results = append(results, result)
-	}
-	return results, nil
+	// The Firecrawl scrape API does not support multiple URLs in a single request,
+	// so the pages are scraped concurrently one by one.
+	return web.BatchContents(c.ScrapeBatch(ctx, urls, options))
+}
+
+// ScrapeBatch scrapes the pages concurrently and returns one result per URL, in the order of the URLs.
+func (c *Client) ScrapeBatch(ctx context.Context, urls []string, options *web.ScrapeOptions) []*web.ScrapeResult {
+	return web.ScrapeConcurrently(ctx, c.Scrape, urls, options, web.BatchOptions{})
 }
 
 func (c *Client) setRequestHeaders(req *http.Request) {
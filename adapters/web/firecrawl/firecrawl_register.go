@@ -1,48 +1,54 @@
 package firecrawl
 
 import (
+	"time"
+
 	"github.com/anboat/strato-sdk/adapters/web"
+	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
-	"time"
 )
 
+// scraperName is the name the Firecrawl adapter is registered under.
+const scraperName = "firecrawl"
+
+// ClientOptions holds the keys of the `config` section of the Firecrawl scraper.
+type ClientOptions struct {
+	Timeout time.Duration `mapstructure:"timeout" description:"Request timeout, in seconds or as a duration string"`
+}
+
+// defaultClientOptions returns the options used for the keys that are not configured.
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout: DefaultTimeout,
+	}
+}
+
+// Validate implements config.ConfigValidator.
+func (o *ClientOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return config.InvalidConfigValue("timeout", "must be positive, got %s", o.Timeout)
+	}
+	return nil
+}
+
 // FirecrawlAdapterCreator creates Firecrawl adapter instances.
 type FirecrawlAdapterCreator struct{}
 
 // CreateAdapter creates a new Firecrawl adapter.
 func (c *FirecrawlAdapterCreator) CreateAdapter(scraperConfig types.WebScraperConfig) (web.WebAdapter, error) {
-	// Create Firecrawl config object.
-	firecrawlConfig := &ClientConfig{}
-
-	// Read API key from credentials.
-	firecrawlConfig.APIKey = scraperConfig.APIKey
-
-	// Use the BaseURL field.
-	if scraperConfig.BaseURL != "" {
-		firecrawlConfig.BaseURL = scraperConfig.BaseURL
-	} else {
-		firecrawlConfig.BaseURL = FirecrawlAPIBaseURL
+	options := defaultClientOptions()
+	if err := config.DecodeAdapterConfig(config.ComponentWebScraper, scraperName, scraperConfig.Config, &options); err != nil {
+		return nil, err
 	}
 
-	// Read other configurations if they exist.
-	if scraperConfig.Config != nil {
-		if timeout, ok := scraperConfig.Config["timeout"]; ok {
-			switch t := timeout.(type) {
-			case int:
-				firecrawlConfig.Timeout = time.Duration(t) * time.Second
-			case float64:
-				firecrawlConfig.Timeout = time.Duration(t) * time.Second
-			case string:
-				if duration, err := time.ParseDuration(t); err == nil {
-					firecrawlConfig.Timeout = duration
-				}
-			}
-		}
+	// Create Firecrawl config object.
+	firecrawlConfig := &ClientConfig{
+		APIKey:  scraperConfig.APIKey,
+		BaseURL: scraperConfig.BaseURL,
+		Timeout: options.Timeout,
 	}
-
-	// Set a default timeout if not provided.
-	if firecrawlConfig.Timeout == 0 {
-		firecrawlConfig.Timeout = DefaultTimeout
+	if firecrawlConfig.BaseURL == "" {
+		firecrawlConfig.BaseURL = FirecrawlAPIBaseURL
 	}
 
 	return NewClient(firecrawlConfig), nil
@@ -51,5 +57,15 @@ func (c *FirecrawlAdapterCreator) CreateAdapter(scraperConfig types.WebScraperCo
 // init registers the Firecrawl adapter creator.
 func init() {
 	// The web adapter registry is in the parent `web` package.
-	web.RegisterAdapterCreator("firecrawl", (&FirecrawlAdapterCreator{}).CreateAdapter)
+	web.RegisterAdapterCreator(scraperName, (&FirecrawlAdapterCreator{}).CreateAdapter)
+	web.RegisterAdapterDescriptor(scraperName, web.AdapterDescriptor{
+		Description: "Firecrawl scrape API",
+		Capabilities: web.AdapterCapabilities{
+			Formats:    []web.Format{web.FormatText, web.FormatHTML, web.FormatMarkdown},
+			Links:      true,
+			Images:     true,
+			JavaScript: true,
+		},
+		ConfigSchema: config.ConfigSchema(defaultClientOptions()),
+	})
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/anboat/strato-sdk/adapters/web"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 )
 
 // Constants for the Jina adapter.
@@ -126,28 +127,22 @@ func (c *Client) Scrape(ctx context.Context, targetURL string, options *web.Scra
 	// Check the response status.
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(body))
+		return nil, resilience.NewStatusError(resp, fmt.Sprintf("request failed with status code %d: %s", resp.StatusCode, string(body)))
 	}
 
 	// Parse the response.
 	return c.parseResponse(resp, options)
 }
 
-// ScrapeMultiple implements batch scraping.
+// ScrapeMultiple scrapes the pages concurrently, returning the pages that were scraped.
+// It fails only if no page could be scraped.
 func (c *Client) ScrapeMultiple(ctx context.Context, urls []string, options *web.ScrapeOptions) ([]*web.WebContent, error) {
-	results := make([]*web.WebContent, 0, len(urls))
-
-	// Concurrent scraping (simplified version, a goroutine pool could be used in practice).
-	for _, targetURL := range urls {
-		result, err := c.Scrape(ctx, targetURL, options)
-		if err != nil {
-			// Log the error but continue processing other URLs.
-			continue
-		}
-		results = append(results, result)
-	}
+	return web.BatchContents(c.ScrapeBatch(ctx, urls, options))
+}
 
-	return results, nil
+// ScrapeBatch scrapes the pages concurrently and returns one result per URL, in the order of the URLs.
+func (c *Client) ScrapeBatch(ctx context.Context, urls []string, options *web.ScrapeOptions) []*web.ScrapeResult {
+	return web.ScrapeConcurrently(ctx, c.Scrape, urls, options, web.BatchOptions{})
 }
 
 // setRequestHeaders sets the request headers.
@@ -4,47 +4,51 @@ import (
 	"time"
 
 	"github.com/anboat/strato-sdk/adapters/web"
+	"github.com/anboat/strato-sdk/config"
 	"github.com/anboat/strato-sdk/config/types"
 )
 
+// scraperName is the name the Jina adapter is registered under.
+const scraperName = "jina"
+
+// ClientOptions holds the keys of the `config` section of the Jina scraper.
+type ClientOptions struct {
+	Timeout time.Duration `mapstructure:"timeout" description:"Request timeout, in seconds or as a duration string"`
+}
+
+// defaultClientOptions returns the options used for the keys that are not configured.
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout: DefaultTimeout,
+	}
+}
+
+// Validate implements config.ConfigValidator.
+func (o *ClientOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return config.InvalidConfigValue("timeout", "must be positive, got %s", o.Timeout)
+	}
+	return nil
+}
+
 // JinaAdapterCreator creates Jina adapter instances.
 type JinaAdapterCreator struct{}
 
 // CreateAdapter creates a new Jina adapter instance.
 func (c *JinaAdapterCreator) CreateAdapter(scraperConfig types.WebScraperConfig) (web.WebAdapter, error) {
-	// Create a Jina client configuration object.
-	clientConfig := &ClientConfig{}
-
-	// Read the API key from the scraper configuration.
-	clientConfig.APIKey = scraperConfig.APIKey
-
-	// Use the BaseURL field.
-	if scraperConfig.BaseURL != "" {
-		clientConfig.BaseURL = scraperConfig.BaseURL
-	} else {
-		clientConfig.BaseURL = JinaReaderBaseURL
+	options := defaultClientOptions()
+	if err := config.DecodeAdapterConfig(config.ComponentWebScraper, scraperName, scraperConfig.Config, &options); err != nil {
+		return nil, err
 	}
 
-	// Read other configurations from the 'config' section.
-	if scraperConfig.Config != nil {
-		// Handle various timeout formats.
-		if timeout, ok := scraperConfig.Config["timeout"]; ok {
-			switch t := timeout.(type) {
-			case int:
-				clientConfig.Timeout = time.Duration(t) * time.Second
-			case float64:
-				clientConfig.Timeout = time.Duration(t) * time.Second
-			case string:
-				if duration, err := time.ParseDuration(t); err == nil {
-					clientConfig.Timeout = duration
-				}
-			}
-		}
+	// Create a Jina client configuration object.
+	clientConfig := &ClientConfig{
+		APIKey:  scraperConfig.APIKey,
+		BaseURL: scraperConfig.BaseURL,
+		Timeout: options.Timeout,
 	}
-
-	// Set a default timeout if not provided.
-	if clientConfig.Timeout == 0 {
-		clientConfig.Timeout = DefaultTimeout
+	if clientConfig.BaseURL == "" {
+		clientConfig.BaseURL = JinaReaderBaseURL
 	}
 
 	// Create and return the Client, which already implements the WebAdapter interface.
@@ -54,5 +58,15 @@ func (c *JinaAdapterCreator) CreateAdapter(scraperConfig types.WebScraperConfig)
 // init registers the Jina adapter creator.
 func init() {
 	creator := &JinaAdapterCreator{}
-	web.RegisterAdapterCreator("jina", creator.CreateAdapter)
+	web.RegisterAdapterCreator(scraperName, creator.CreateAdapter)
+	web.RegisterAdapterDescriptor(scraperName, web.AdapterDescriptor{
+		Description: "Jina Reader API",
+		Capabilities: web.AdapterCapabilities{
+			Formats:    []web.Format{web.FormatText, web.FormatHTML, web.FormatMarkdown},
+			Links:      true,
+			Images:     true,
+			JavaScript: true,
+		},
+		ConfigSchema: config.ConfigSchema(defaultClientOptions()),
+	})
 }
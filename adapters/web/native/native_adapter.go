@@ -0,0 +1,193 @@
+package native
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"github.com/anboat/strato-sdk/adapters/web"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// Constants for the native adapter.
+const (
+	// DefaultTimeout is the default timeout for requests.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultUserAgent is the User-Agent header sent with every request.
+	DefaultUserAgent = "Mozilla/5.0 (compatible; Strato-SDK-Bot/1.0)"
+
+	// DefaultMaxBodySize is the default maximum number of bytes read from a page.
+	DefaultMaxBodySize = 5 << 20
+)
+
+// Client scrapes web pages directly over HTTP, without a third-party API.
+// It extracts the main content of HTML pages with a readability-style algorithm.
+type Client struct {
+	httpClient  *http.Client
+	userAgent   string
+	maxBodySize int64
+}
+
+// ClientConfig holds the configuration for the native client.
+type ClientConfig struct {
+	UserAgent   string        `json:"user_agent,omitempty"`
+	Timeout     time.Duration `json:"timeout,omitempty"`
+	MaxBodySize int64         `json:"max_body_size,omitempty"`
+	HTTPClient  *http.Client  `json:"-"`
+}
+
+// NewClient creates a new native client.
+func NewClient(config *ClientConfig) *Client {
+	if config == nil {
+		config = &ClientConfig{}
+	}
+
+	// Set default values.
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultMaxBodySize
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{
+			Timeout: config.Timeout,
+		}
+	}
+
+	return &Client{
+		httpClient:  config.HTTPClient,
+		userAgent:   config.UserAgent,
+		maxBodySize: config.MaxBodySize,
+	}
+}
+
+// Scrape fetches a single page and extracts its main content in the requested format.
+func (c *Client) Scrape(ctx context.Context, targetURL string, options *web.ScrapeOptions) (*web.WebContent, error) {
+	if options != nil && options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(options.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setRequestHeaders(req, options)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resilience.NewStatusError(resp, fmt.Sprintf("request failed with status code %d", resp.StatusCode))
+	}
+
+	// Resolve relative URLs against the final URL, after redirects.
+	pageURL := resp.Request.URL
+
+	// Decode the body to UTF-8, detecting the charset from the header, a BOM or a <meta> tag.
+	contentType := resp.Header.Get("Content-Type")
+	body, err := charset.NewReader(io.LimitReader(resp.Body, c.maxBodySize), contentType)
+	if errors.Is(err, io.EOF) {
+		// The page has no body.
+		return &web.WebContent{URL: pageURL.String()}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect the charset of %s: %w", targetURL, err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "", "text/html", "application/xhtml+xml":
+		return c.parseHTML(body, pageURL, options)
+	case "text/plain", "text/markdown":
+		text, err := io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return &web.WebContent{URL: pageURL.String(), Content: strings.TrimSpace(string(text))}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %q of %s", mediaType, targetURL)
+	}
+}
+
+// ScrapeMultiple scrapes the pages concurrently, returning the pages that were scraped.
+// It fails only if no page could be scraped.
+func (c *Client) ScrapeMultiple(ctx context.Context, urls []string, options *web.ScrapeOptions) ([]*web.WebContent, error) {
+	return web.BatchContents(c.ScrapeBatch(ctx, urls, options))
+}
+
+// ScrapeBatch scrapes the pages concurrently and returns one result per URL, in the order of the URLs.
+func (c *Client) ScrapeBatch(ctx context.Context, urls []string, options *web.ScrapeOptions) []*web.ScrapeResult {
+	return web.ScrapeConcurrently(ctx, c.Scrape, urls, options, web.BatchOptions{})
+}
+
+// setRequestHeaders sets the request headers.
+func (c *Client) setRequestHeaders(req *http.Request, options *web.ScrapeOptions) {
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.5")
+
+	if options != nil && len(options.Cookies) > 0 {
+		for name, value := range options.Cookies {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		}
+	}
+}
+
+// parseHTML extracts the title, main content, links and images of an HTML page.
+func (c *Client) parseHTML(body io.Reader, pageURL *url.URL, options *web.ScrapeOptions) (*web.WebContent, error) {
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML of %s: %w", pageURL, err)
+	}
+
+	base := baseURL(doc, pageURL)
+	content := &web.WebContent{
+		URL:    pageURL.String(),
+		Title:  extractTitle(doc),
+		Links:  extractLinks(doc, base),
+		Images: extractImages(doc, base),
+	}
+
+	// Extract the main content; the links and images above come from the whole page.
+	article := extractArticle(doc)
+
+	format := web.FormatText
+	if options != nil && options.Format.IsValid() {
+		format = options.Format
+	}
+	switch format {
+	case web.FormatHTML:
+		var buf bytes.Buffer
+		for _, node := range article {
+			if err := html.Render(&buf, node); err != nil {
+				return nil, fmt.Errorf("failed to render HTML of %s: %w", pageURL, err)
+			}
+		}
+		content.Content = buf.String()
+	case web.FormatMarkdown:
+		content.Content = renderMarkdown(article, base)
+	default:
+		content.Content = renderText(article)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,255 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anboat/strato-sdk/adapters/web"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// readFixture returns the content of a file in testdata.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// servePage starts a server that answers every request with the body and content type.
+func servePage(t *testing.T, contentType string, body []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// scrapeFixture serves the fixture as HTML and scrapes it in the format.
+func scrapeFixture(t *testing.T, name string, format web.Format) *web.WebContent {
+	t.Helper()
+	server := servePage(t, "text/html", readFixture(t, name))
+	content, err := NewClient(nil).Scrape(context.Background(), server.URL+"/posts/gc.html", &web.ScrapeOptions{Format: format})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	return content
+}
+
+func TestScrapeArticle(t *testing.T) {
+	content := scrapeFixture(t, "article.html", web.FormatText)
+
+	if want := "Tuning Go Garbage Collection"; content.Title != want {
+		t.Errorf("Scrape() title = %q, want %q", content.Title, want)
+	}
+	for _, want := range []string{
+		"Why the collector matters",
+		"The Go garbage collector trades CPU time for memory",
+		"The memory limit, introduced in Go 1.19",
+		"Read more about the author or the official guide for the details of the pacer.",
+		"GOGC=200 GOMEMLIMIT=1GiB ./server",
+	} {
+		if !strings.Contains(content.Content, want) {
+			t.Errorf("Scrape() content is missing %q:\n%s", want, content.Content)
+		}
+	}
+	for _, marker := range []string{"SCRIPT", "COOKIE", "HIDDEN", "SIDEBAR", "COMMENT", "FOOTER", "Back to top", "Archive"} {
+		if strings.Contains(content.Content, marker) {
+			t.Errorf("Scrape() content contains boilerplate %q:\n%s", marker, content.Content)
+		}
+	}
+}
+
+func TestScrapeResolvesAgainstBase(t *testing.T) {
+	content := scrapeFixture(t, "article.html", web.FormatText)
+
+	wantLinks := []web.Link{
+		{URL: "https://cdn.example.com/", Text: "Home"},
+		{URL: "https://cdn.example.com/archive", Text: "Archive"},
+		{URL: "https://cdn.example.com/about", Text: "more about the author"},
+		{URL: "https://go.dev/doc/gc-guide", Text: "official guide"},
+	}
+	if !reflect.DeepEqual(content.Links, wantLinks) {
+		t.Errorf("Scrape() links = %v, want %v", content.Links, wantLinks)
+	}
+
+	wantImages := []web.Image{
+		{URL: "https://cdn.example.com/blog/img/cover.png", Title: "Heap growth over time"},
+		{URL: "https://cdn.example.com/blog/img/lazy.png", Title: "Lazy chart"},
+	}
+	if !reflect.DeepEqual(content.Images, wantImages) {
+		t.Errorf("Scrape() images = %v, want %v", content.Images, wantImages)
+	}
+}
+
+func TestScrapeFormats(t *testing.T) {
+	tests := []struct {
+		format  web.Format
+		want    []string
+		notWant []string
+	}{
+		{
+			format:  web.FormatText,
+			want:    []string{"Why the collector matters\n\nThe Go garbage collector", "more about the author"},
+			notWant: []string{"##", "](", "![", "```", "<p>"},
+		},
+		{
+			format: web.FormatMarkdown,
+			want: []string{
+				"## Why the collector matters",
+				"[more about the author](https://cdn.example.com/about)",
+				"[official guide](https://go.dev/doc/gc-guide)",
+				"![Heap growth over time](https://cdn.example.com/blog/img/cover.png)",
+				"![Lazy chart](https://cdn.example.com/blog/img/lazy.png)",
+				"```\nGOGC=200 GOMEMLIMIT=1GiB ./server\n```",
+			},
+			notWant: []string{"<p>", "SIDEBAR"},
+		},
+		{
+			format:  web.FormatHTML,
+			want:    []string{`<div class="post-content">`, "<h2>Why the collector matters</h2>", `<a href="../about">`},
+			notWant: []string{"<aside", "<script", "SIDEBAR"},
+		},
+		{
+			format:  "",
+			want:    []string{"Why the collector matters\n\nThe Go garbage collector"},
+			notWant: []string{"##", "<p>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			content := scrapeFixture(t, "article.html", tt.format)
+			for _, want := range tt.want {
+				if !strings.Contains(content.Content, want) {
+					t.Errorf("Scrape() content is missing %q:\n%s", want, content.Content)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(content.Content, notWant) {
+					t.Errorf("Scrape() content contains %q:\n%s", notWant, content.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestScrapeCharsets(t *testing.T) {
+	t.Run("meta", func(t *testing.T) {
+		content := scrapeFixture(t, "gbk.html", web.FormatText)
+		if want := "垃圾回收调优指南"; content.Title != want {
+			t.Errorf("Scrape() title = %q, want %q", content.Title, want)
+		}
+		if want := "垃圾回收器用处理器时间换取内存，GOGC 参数控制这种权衡"; !strings.Contains(content.Content, want) {
+			t.Errorf("Scrape() content is missing %q:\n%s", want, content.Content)
+		}
+	})
+
+	t.Run("header", func(t *testing.T) {
+		body := []byte("<html><body><p>Caf\xe9 cr\xe8me, na\xefve r\xe9sum\xe9s and other Latin-1 words.</p></body></html>")
+		server := servePage(t, "text/html; charset=iso-8859-1", body)
+		content, err := NewClient(nil).Scrape(context.Background(), server.URL, nil)
+		if err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		if want := "Café crème, naïve résumés and other Latin-1 words."; content.Content != want {
+			t.Errorf("Scrape() content = %q, want %q", content.Content, want)
+		}
+	})
+}
+
+func TestScrapeTruncatesToMaxBodySize(t *testing.T) {
+	head := "<html><body><div class=\"content\"><p>The first paragraph is within the size limit, so it is kept.</p>"
+	body := head + strings.Repeat("<p>Padding paragraph.</p>", 100) + "<p>TRUNCATED MARKER after the size limit.</p></div></body></html>"
+	server := servePage(t, "text/html", []byte(body))
+
+	client := NewClient(&ClientConfig{MaxBodySize: int64(len(head) + 200)})
+	content, err := client.Scrape(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if !strings.Contains(content.Content, "The first paragraph is within the size limit") {
+		t.Errorf("Scrape() content is missing the first paragraph:\n%s", content.Content)
+	}
+	if strings.Contains(content.Content, "TRUNCATED MARKER") {
+		t.Errorf("Scrape() content contains text beyond the size limit:\n%s", content.Content)
+	}
+}
+
+func TestScrapeContentTypes(t *testing.T) {
+	t.Run("plain text", func(t *testing.T) {
+		server := servePage(t, "text/plain; charset=utf-8", []byte("  just text\n"))
+		content, err := NewClient(nil).Scrape(context.Background(), server.URL, nil)
+		if err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		if content.Content != "just text" {
+			t.Errorf("Scrape() content = %q, want %q", content.Content, "just text")
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		server := servePage(t, "text/html", nil)
+		content, err := NewClient(nil).Scrape(context.Background(), server.URL, nil)
+		if err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		if content.Content != "" || content.URL != server.URL {
+			t.Errorf("Scrape() = %+v, want an empty page of %s", content, server.URL)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		server := servePage(t, "application/pdf", []byte("%PDF-1.7"))
+		if _, err := NewClient(nil).Scrape(context.Background(), server.URL, nil); err == nil {
+			t.Error("Scrape() error = nil, want an unsupported content type error")
+		}
+	})
+}
+
+func TestScrapeStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(nil).Scrape(context.Background(), server.URL, nil)
+	var statusErr *resilience.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Scrape() error = %v, want a status error with code %d", err, http.StatusNotFound)
+	}
+}
+
+func TestScrapeRequestHeaders(t *testing.T) {
+	var userAgent, cookie string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		if c, err := r.Cookie("session"); err == nil {
+			cookie = c.Value
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{UserAgent: "TestBot/1.0"})
+	if _, err := client.Scrape(context.Background(), server.URL, &web.ScrapeOptions{Cookies: map[string]string{"session": "abc"}}); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if userAgent != "TestBot/1.0" || cookie != "abc" {
+		t.Errorf("request User-Agent = %q, cookie = %q, want %q, %q", userAgent, cookie, "TestBot/1.0", "abc")
+	}
+}
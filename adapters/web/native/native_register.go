@@ -0,0 +1,70 @@
+package native
+
+import (
+	"time"
+
+	"github.com/anboat/strato-sdk/adapters/web"
+	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+// scraperName is the name the native adapter is registered under.
+const scraperName = "native"
+
+// ClientOptions holds the keys of the `config` section of the native scraper.
+type ClientOptions struct {
+	UserAgent   string        `mapstructure:"user_agent" description:"User-Agent header sent to the sites"`
+	Timeout     time.Duration `mapstructure:"timeout" description:"Request timeout, in seconds or as a duration string"`
+	MaxBodySize int64         `mapstructure:"max_body_size" description:"Maximum number of bytes read from a page"`
+}
+
+// defaultClientOptions returns the options used for the keys that are not configured.
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		UserAgent:   DefaultUserAgent,
+		Timeout:     DefaultTimeout,
+		MaxBodySize: DefaultMaxBodySize,
+	}
+}
+
+// Validate implements config.ConfigValidator.
+func (o *ClientOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return config.InvalidConfigValue("timeout", "must be positive, got %s", o.Timeout)
+	}
+	if o.MaxBodySize <= 0 {
+		return config.InvalidConfigValue("max_body_size", "must be positive, got %d", o.MaxBodySize)
+	}
+	return nil
+}
+
+// NativeAdapterCreator creates native adapter instances.
+type NativeAdapterCreator struct{}
+
+// CreateAdapter creates a new native adapter instance. The scraper needs no API key or base URL.
+func (c *NativeAdapterCreator) CreateAdapter(scraperConfig types.WebScraperConfig) (web.WebAdapter, error) {
+	options := defaultClientOptions()
+	if err := config.DecodeAdapterConfig(config.ComponentWebScraper, scraperName, scraperConfig.Config, &options); err != nil {
+		return nil, err
+	}
+
+	return NewClient(&ClientConfig{
+		UserAgent:   options.UserAgent,
+		Timeout:     options.Timeout,
+		MaxBodySize: options.MaxBodySize,
+	}), nil
+}
+
+// init registers the native adapter creator.
+func init() {
+	web.RegisterAdapterCreator(scraperName, (&NativeAdapterCreator{}).CreateAdapter)
+	web.RegisterAdapterDescriptor(scraperName, web.AdapterDescriptor{
+		Description: "Built-in scraper that fetches pages directly and extracts the main article",
+		Capabilities: web.AdapterCapabilities{
+			Formats: []web.Format{web.FormatText, web.FormatHTML, web.FormatMarkdown},
+			Links:   true,
+			Images:  true,
+		},
+		ConfigSchema: config.ConfigSchema(defaultClientOptions()),
+	})
+}
@@ -0,0 +1,373 @@
+package native
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minParagraphLength is the minimum text length of a paragraph that adds to the score of its ancestors.
+const minParagraphLength = 25
+
+// Patterns of class names and ids that mark likely and unlikely content, as in Arc90's readability.
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumbs|combx|comment|community|cookie|disqus|extra|footer|gdpr|header|legends|menu|modal|newsletter|pager|pagination|popup|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tweet|twitter|ad-break|agegate|promo`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveWeight     = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeWeight     = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+	whitespace         = regexp.MustCompile(`\s+`)
+)
+
+// removedTags are the elements that never hold the main content.
+var removedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Svg: true, atom.Canvas: true, atom.Object: true, atom.Embed: true,
+	atom.Form: true, atom.Button: true, atom.Input: true, atom.Select: true, atom.Textarea: true,
+	atom.Nav: true, atom.Aside: true, atom.Footer: true,
+}
+
+// blockTags are the elements that start a new block of text.
+var blockTags = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Dd: true,
+	atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true,
+	atom.Footer: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true,
+	atom.H6: true, atom.Header: true, atom.Hr: true, atom.Li: true, atom.Main: true, atom.Ol: true,
+	atom.P: true, atom.Pre: true, atom.Section: true, atom.Table: true, atom.Tr: true, atom.Ul: true,
+}
+
+// extractTitle returns the title of the page: the Open Graph title, the <title> or the first <h1>.
+func extractTitle(doc *html.Node) string {
+	if node := findFirst(doc, func(n *html.Node) bool {
+		return n.DataAtom == atom.Meta && (attr(n, "property") == "og:title" || attr(n, "name") == "twitter:title")
+	}); node != nil {
+		if title := collapse(attr(node, "content")); title != "" {
+			return title
+		}
+	}
+	if node := findFirst(doc, isElement(atom.Title)); node != nil {
+		if title := collapse(textContent(node)); title != "" {
+			return title
+		}
+	}
+	if node := findFirst(doc, isElement(atom.H1)); node != nil {
+		return collapse(textContent(node))
+	}
+	return ""
+}
+
+// extractArticle returns the nodes that make up the main content of the page. It removes the
+// boilerplate, scores the blocks of text by their length, commas, class names and link density,
+// and keeps the best-scoring container together with the siblings that look like part of it.
+// The document is modified in the process.
+func extractArticle(doc *html.Node) []*html.Node {
+	body := findFirst(doc, isElement(atom.Body))
+	if body == nil {
+		body = doc
+	}
+	removeBoilerplate(body)
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addCandidate := func(n *html.Node) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, exists := scores[n]; !exists {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+	}
+
+	walk(body, func(n *html.Node) bool {
+		if !isParagraph(n) {
+			return true
+		}
+		text := collapse(textContent(n))
+		if len(text) < minParagraphLength {
+			return true
+		}
+
+		// One point for the paragraph, one per comma and one per 100 characters, up to 3.
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，"))
+		score += min(float64(len(text))/100, 3)
+
+		parent := n.Parent
+		addCandidate(parent)
+		if parent != nil {
+			scores[parent] += score
+			if grandparent := parent.Parent; grandparent != nil && grandparent.Type == html.ElementNode {
+				addCandidate(grandparent)
+				scores[grandparent] += score / 2
+			}
+		}
+		return true
+	})
+
+	var top *html.Node
+	for _, candidate := range candidates {
+		scores[candidate] *= 1 - linkDensity(candidate)
+		if top == nil || scores[candidate] > scores[top] {
+			top = candidate
+		}
+	}
+	if top == nil {
+		return []*html.Node{body}
+	}
+
+	// Keep the siblings that score well or read like paragraphs of the article.
+	threshold := max(10, scores[top]*0.2)
+	var article []*html.Node
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == top {
+			article = append(article, sibling)
+			continue
+		}
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if score, scored := scores[sibling]; scored && score >= threshold {
+			article = append(article, sibling)
+			continue
+		}
+		if sibling.DataAtom == atom.P {
+			text := collapse(textContent(sibling))
+			density := linkDensity(sibling)
+			if (len(text) > 80 && density < 0.25) || (len(text) > 0 && density == 0 && strings.HasSuffix(text, ".")) {
+				article = append(article, sibling)
+			}
+		}
+	}
+	return article
+}
+
+// removeBoilerplate removes the elements that are hidden, never hold content or, judging by their
+// class name and id, are navigation, comments, ads and the like.
+func removeBoilerplate(root *html.Node) {
+	var remove []*html.Node
+	walk(root, func(n *html.Node) bool {
+		switch n.Type {
+		case html.CommentNode:
+			remove = append(remove, n)
+			return false
+		case html.ElementNode:
+		default:
+			return true
+		}
+
+		if removedTags[n.DataAtom] || isHidden(n) {
+			remove = append(remove, n)
+			return false
+		}
+
+		if n.DataAtom != atom.Body && n.DataAtom != atom.Article && n.DataAtom != atom.Main {
+			match := attr(n, "class") + " " + attr(n, "id")
+			if unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match) && !hasAncestor(n, atom.Article) {
+				remove = append(remove, n)
+				return false
+			}
+		}
+		return true
+	})
+
+	for _, n := range remove {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+}
+
+// initialScore returns the score of a candidate before its paragraphs are counted.
+func initialScore(n *html.Node) float64 {
+	score := classWeight(n)
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	return score
+}
+
+// classWeight scores the class name and id of an element as likely or unlikely content.
+func classWeight(n *html.Node) float64 {
+	var weight float64
+	for _, value := range []string{attr(n, "class"), attr(n, "id")} {
+		if value == "" {
+			continue
+		}
+		if negativeWeight.MatchString(value) {
+			weight -= 25
+		}
+		if positiveWeight.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// isParagraph reports whether the element is a block of text that is scored: a paragraph-like
+// element, or a <div> that holds text directly instead of further blocks.
+func isParagraph(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.DataAtom {
+	case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		return true
+	case atom.Div:
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && blockTags[child.DataAtom] {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// linkDensity returns the share of the text of an element that is link text.
+func linkDensity(n *html.Node) float64 {
+	textLength := len(collapse(textContent(n)))
+	if textLength == 0 {
+		return 0
+	}
+
+	var linkLength int
+	walk(n, func(child *html.Node) bool {
+		if child.Type == html.ElementNode && child.DataAtom == atom.A {
+			linkLength += len(collapse(textContent(child)))
+			return false
+		}
+		return true
+	})
+	return float64(linkLength) / float64(textLength)
+}
+
+// isHidden reports whether the element is hidden from readers.
+func isHidden(n *html.Node) bool {
+	if hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// baseURL returns the URL relative URLs of the page are resolved against, honouring a <base> element.
+func baseURL(doc *html.Node, pageURL *url.URL) *url.URL {
+	if node := findFirst(doc, isElement(atom.Base)); node != nil {
+		if base, err := pageURL.Parse(attr(node, "href")); err == nil && attr(node, "href") != "" {
+			return base
+		}
+	}
+	return pageURL
+}
+
+// resolveURL resolves a link of the page, returning an empty string for links that do not lead
+// to another resource, such as fragments, scripts and data URIs.
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	resolved.Fragment = ""
+	return resolved.String()
+}
+
+// walk calls visit for every node below root in document order; visit returns false to skip the children of a node.
+func walk(root *html.Node, visit func(n *html.Node) bool) {
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		if visit(child) {
+			walk(child, visit)
+		}
+	}
+}
+
+// findFirst returns the first node below root that matches, or nil.
+func findFirst(root *html.Node, match func(n *html.Node) bool) *html.Node {
+	var found *html.Node
+	walk(root, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// isElement returns a matcher for elements of the given tag.
+func isElement(tag atom.Atom) func(n *html.Node) bool {
+	return func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.DataAtom == tag
+	}
+}
+
+// hasAncestor reports whether the node is nested in an element of the given tag.
+func hasAncestor(n *html.Node, tag atom.Atom) bool {
+	for parent := n.Parent; parent != nil; parent = parent.Parent {
+		if parent.Type == html.ElementNode && parent.DataAtom == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent returns the text below a node, without any markup.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	walk(n, func(child *html.Node) bool {
+		if child.Type == html.TextNode {
+			sb.WriteString(child.Data)
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// collapse replaces every run of whitespace with a single space and trims the result.
+func collapse(text string) string {
+	return strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+}
+
+// attr returns the value of an attribute of the element, or an empty string.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether the element has the attribute.
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
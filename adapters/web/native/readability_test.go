@@ -0,0 +1,223 @@
+package native
+
+import (
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// parseFragment parses the HTML and returns its document node.
+func parseFragment(t *testing.T, source string) *html.Node {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+	return doc
+}
+
+// firstElement parses the HTML and returns the first element with the tag.
+func firstElement(t *testing.T, source string, tag atom.Atom) *html.Node {
+	t.Helper()
+	node := findFirst(parseFragment(t, source), isElement(tag))
+	if node == nil {
+		t.Fatalf("no %s element in %s", tag, source)
+	}
+	return node
+}
+
+func TestInitialScore(t *testing.T) {
+	tests := []struct {
+		source string
+		tag    atom.Atom
+		want   float64
+	}{
+		{`<article>x</article>`, atom.Article, 10},
+		{`<main>x</main>`, atom.Main, 10},
+		{`<div>x</div>`, atom.Div, 5},
+		{`<div class="post-content">x</div>`, atom.Div, 30},
+		{`<div class="sidebar">x</div>`, atom.Div, -20},
+		{`<div class="entry" id="comments">x</div>`, atom.Div, 5},
+		{`<pre>x</pre>`, atom.Pre, 3},
+		{`<ul><li>x</li></ul>`, atom.Ul, -3},
+		{`<h2>x</h2>`, atom.H2, -5},
+		{`<span>x</span>`, atom.Span, 0},
+	}
+
+	for _, tt := range tests {
+		if got := initialScore(firstElement(t, tt.source, tt.tag)); got != tt.want {
+			t.Errorf("initialScore(%s) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestLinkDensity(t *testing.T) {
+	tests := []struct {
+		source string
+		want   float64
+	}{
+		{`<div></div>`, 0},
+		{`<div>plain text only</div>`, 0},
+		{`<div><a href="/a">all link</a></div>`, 1},
+		{`<div>0123456789 <a href="/a">link</a></div>`, 4.0 / 15},
+		{`<div><a href="/a">one</a> <a href="/b"><b>two</b></a></div>`, 6.0 / 7},
+	}
+
+	for _, tt := range tests {
+		got := linkDensity(firstElement(t, tt.source, atom.Div))
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("linkDensity(%s) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveBoilerplate(t *testing.T) {
+	doc := parseFragment(t, `<html><body>
+		<script>SCRIPT</script><style>STYLE</style><!-- COMMENT -->
+		<nav>NAV</nav><aside>ASIDE</aside><footer>FOOTER</footer><form>FORM</form>
+		<div hidden>HIDDEN</div>
+		<div aria-hidden="true">ARIA</div>
+		<div style="Visibility: Hidden">INVISIBLE</div>
+		<div class="share-buttons">SHARE</div>
+		<div id="related-posts">RELATED</div>
+		<div class="main-column sidebar">MAYBE</div>
+		<article><div class="comment-style-quote">QUOTE</div></article>
+		<div class="content">CONTENT</div>
+	</body></html>`)
+	body := findFirst(doc, isElement(atom.Body))
+	removeBoilerplate(body)
+	text := textContent(body)
+
+	for _, removed := range []string{"SCRIPT", "STYLE", "COMMENT", "NAV", "ASIDE", "FOOTER", "FORM", "HIDDEN", "ARIA", "INVISIBLE", "SHARE", "RELATED"} {
+		if strings.Contains(text, removed) {
+			t.Errorf("removeBoilerplate() kept %s", removed)
+		}
+	}
+	// Unlikely names are kept when they also look like content or are nested in an article.
+	for _, kept := range []string{"MAYBE", "QUOTE", "CONTENT"} {
+		if !strings.Contains(text, kept) {
+			t.Errorf("removeBoilerplate() removed %s", kept)
+		}
+	}
+}
+
+func TestExtractArticle(t *testing.T) {
+	paragraph := "<p>This paragraph of the article is long enough to count, with a comma, and some more words.</p>"
+
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "article over link lists",
+			source: `<body>
+				<div class="links">
+					<p><a href="/1">A long list of links that looks like a paragraph, one</a></p>
+					<p><a href="/2">A long list of links that looks like a paragraph, two</a></p>
+					<p><a href="/3">A long list of links that looks like a paragraph, three</a></p>
+				</div>
+				<div class="story">` + paragraph + paragraph + `</div>
+			</body>`,
+			want:    []string{"This paragraph of the article"},
+			notWant: []string{"A long list of links"},
+		},
+		{
+			name: "class names outweigh length",
+			source: `<body>
+				<div class="widget">` + paragraph + paragraph + paragraph + `</div>
+				<div class="entry-content">` + paragraph + `<p>ENTRY MARKER, the post itself, with enough text to count.</p></div>
+			</body>`,
+			want:    []string{"ENTRY MARKER"},
+			notWant: nil,
+		},
+		{
+			name: "sibling paragraphs are kept",
+			source: `<body><div class="wrapper">
+				<div class="content">` + paragraph + paragraph + `</div>
+				<p>A short closing sentence.</p>
+				<p>Share: <a href="/x">x</a> <a href="/y">y</a></p>
+			</div></body>`,
+			want:    []string{"This paragraph of the article", "A short closing sentence."},
+			notWant: []string{"Share:"},
+		},
+		{
+			name:    "no paragraphs",
+			source:  `<body><span>Just a short note</span><nav>NAV</nav></body>`,
+			want:    []string{"Just a short note"},
+			notWant: []string{"NAV"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := renderText(extractArticle(parseFragment(t, tt.source)))
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("extractArticle() is missing %q:\n%s", want, text)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(text, notWant) {
+					t.Errorf("extractArticle() contains %q:\n%s", notWant, text)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractTitle(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`<head><meta property="og:title" content=" Open  Graph "><title>Title</title></head>`, "Open Graph"},
+		{`<head><meta name="twitter:title" content="Twitter"><title>Title</title></head>`, "Twitter"},
+		{`<head><meta property="og:title" content=""><title> The
+			title </title></head>`, "The title"},
+		{`<body><h1>Heading</h1></body>`, "Heading"},
+		{`<body><p>No title</p></body>`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractTitle(parseFragment(t, tt.source)); got != tt.want {
+			t.Errorf("extractTitle(%s) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestBaseURLAndResolveURL(t *testing.T) {
+	pageURL, _ := url.Parse("https://example.com/posts/2024/gc.html")
+
+	tests := []struct {
+		name   string
+		source string
+		ref    string
+		want   string
+	}{
+		{"relative to page", `<head></head>`, "../img/a.png", "https://example.com/posts/img/a.png"},
+		{"absolute base", `<head><base href="https://cdn.example.com/assets/"></head>`, "img/a.png", "https://cdn.example.com/assets/img/a.png"},
+		{"relative base", `<head><base href="/static/"></head>`, "a.css", "https://example.com/static/a.css"},
+		{"empty base", `<head><base href=""></head>`, "a.png", "https://example.com/posts/2024/a.png"},
+		{"root-relative", `<head><base href="https://cdn.example.com/assets/"></head>`, "/about", "https://cdn.example.com/about"},
+		{"fragment removed", `<head></head>`, "other.html#section", "https://example.com/posts/2024/other.html"},
+		{"fragment only", `<head></head>`, "#section", ""},
+		{"script", `<head></head>`, "javascript:void(0)", ""},
+		{"mail", `<head></head>`, "mailto:a@example.com", ""},
+		{"empty", `<head></head>`, "  ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := baseURL(parseFragment(t, tt.source), pageURL)
+			if got := resolveURL(base, tt.ref); got != tt.want {
+				t.Errorf("resolveURL(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package native
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/anboat/strato-sdk/adapters/web"
+)
+
+// blankLines matches runs of blank lines, which are collapsed into a single blank line.
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// codeFence delimits preformatted blocks in markdown.
+const codeFence = "```"
+
+// extractLinks returns the links of the page, resolved and without duplicates, in document order.
+func extractLinks(doc *html.Node, base *url.URL) []web.Link {
+	links := make([]web.Link, 0)
+	seen := make(map[string]bool)
+	walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.DataAtom != atom.A {
+			return true
+		}
+		href := resolveURL(base, attr(n, "href"))
+		if href == "" || seen[href] {
+			return false
+		}
+		seen[href] = true
+
+		text := collapse(textContent(n))
+		if text == "" {
+			text = collapse(attr(n, "title"))
+		}
+		links = append(links, web.Link{URL: href, Text: text})
+		return false
+	})
+	return links
+}
+
+// extractImages returns the images of the page, resolved and without duplicates, in document order.
+// Lazy-loaded images are found through their data-src attribute.
+func extractImages(doc *html.Node, base *url.URL) []web.Image {
+	images := make([]web.Image, 0)
+	seen := make(map[string]bool)
+	walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.DataAtom != atom.Img {
+			return true
+		}
+		src := resolveURL(base, imageSource(n))
+		if src == "" || seen[src] {
+			return true
+		}
+		seen[src] = true
+
+		title := collapse(attr(n, "alt"))
+		if title == "" {
+			title = collapse(attr(n, "title"))
+		}
+		images = append(images, web.Image{URL: src, Title: title})
+		return true
+	})
+	return images
+}
+
+// imageSource returns the source of an image, preferring the real source of lazy-loaded images.
+func imageSource(n *html.Node) string {
+	for _, key := range []string{"data-src", "data-original", "src"} {
+		if src := attr(n, key); src != "" && !strings.HasPrefix(src, "data:") {
+			return src
+		}
+	}
+	return ""
+}
+
+// renderText renders the nodes as plain text, with a blank line between blocks.
+func renderText(nodes []*html.Node) string {
+	r := &renderer{}
+	var sb strings.Builder
+	for _, node := range nodes {
+		sb.WriteString(r.render(node))
+	}
+	return normalize(sb.String(), false)
+}
+
+// renderMarkdown renders the nodes as markdown, resolving links and images against the base URL.
+func renderMarkdown(nodes []*html.Node, base *url.URL) string {
+	r := &renderer{markdown: true, base: base}
+	var sb strings.Builder
+	for _, node := range nodes {
+		sb.WriteString(r.render(node))
+	}
+	return normalize(sb.String(), true)
+}
+
+// renderer converts HTML to plain text or markdown.
+type renderer struct {
+	markdown bool
+	base     *url.URL
+}
+
+// render converts a node and its children.
+func (r *renderer) render(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return whitespace.ReplaceAllString(n.Data, " ")
+	case html.ElementNode, html.DocumentNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := collapse(r.children(n))
+		if text == "" {
+			return ""
+		}
+		if r.markdown {
+			level := int(n.Data[1] - '0')
+			text = strings.Repeat("#", level) + " " + text
+		}
+		return "\n\n" + text + "\n\n"
+	case atom.Br:
+		return "\n"
+	case atom.Hr:
+		if r.markdown {
+			return "\n\n---\n\n"
+		}
+		return "\n\n"
+	case atom.Pre:
+		code := strings.Trim(textContent(n), "\n")
+		if r.markdown {
+			return "\n\n" + codeFence + "\n" + code + "\n" + codeFence + "\n\n"
+		}
+		return "\n\n" + code + "\n\n"
+	case atom.Code:
+		return r.wrap(n, "`")
+	case atom.Strong, atom.B:
+		return r.wrap(n, "**")
+	case atom.Em, atom.I:
+		return r.wrap(n, "*")
+	case atom.A:
+		return r.link(n)
+	case atom.Img:
+		return r.image(n)
+	case atom.Ul, atom.Ol:
+		return r.list(n)
+	case atom.Blockquote:
+		return r.blockquote(n)
+	case atom.Table:
+		return r.table(n)
+	}
+
+	if blockTags[n.DataAtom] {
+		return "\n\n" + r.children(n) + "\n\n"
+	}
+	return r.children(n)
+}
+
+// children converts the children of a node.
+func (r *renderer) children(n *html.Node) string {
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(r.render(child))
+	}
+	return sb.String()
+}
+
+// wrap surrounds the inline content of an element with a markdown marker.
+func (r *renderer) wrap(n *html.Node, marker string) string {
+	content := r.children(n)
+	text := strings.TrimSpace(content)
+	if !r.markdown || text == "" {
+		return content
+	}
+	// Keep the surrounding spaces outside of the markers.
+	leading := content[:len(content)-len(strings.TrimLeft(content, " "))]
+	trailing := content[len(strings.TrimRight(content, " ")):]
+	return leading + marker + text + marker + trailing
+}
+
+// link converts a hyperlink.
+func (r *renderer) link(n *html.Node) string {
+	text := collapse(r.children(n))
+	if !r.markdown || text == "" {
+		return r.children(n)
+	}
+	href := resolveURL(r.base, attr(n, "href"))
+	if href == "" {
+		return text
+	}
+	return "[" + text + "](" + href + ")"
+}
+
+// image converts an image; plain text leaves images out.
+func (r *renderer) image(n *html.Node) string {
+	if !r.markdown {
+		return ""
+	}
+	src := resolveURL(r.base, imageSource(n))
+	if src == "" {
+		return ""
+	}
+	return "![" + collapse(attr(n, "alt")) + "](" + src + ")"
+}
+
+// list converts an ordered or unordered list, indenting nested lists below their item.
+func (r *renderer) list(n *html.Node) string {
+	var sb strings.Builder
+	index := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		index = start
+	}
+
+	for item := n.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.DataAtom != atom.Li {
+			continue
+		}
+		content := strings.TrimSpace(r.children(item))
+		if content == "" {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(index) + ". "
+			index++
+		}
+		indent := strings.Repeat(" ", len(marker))
+
+		lines := strings.Split(content, "\n")
+		first := true
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if first {
+				sb.WriteString(marker + strings.TrimSpace(line) + "\n")
+				first = false
+			} else {
+				sb.WriteString(indent + strings.TrimRight(line, " ") + "\n")
+			}
+		}
+	}
+	return "\n\n" + sb.String() + "\n"
+}
+
+// blockquote converts a quotation.
+func (r *renderer) blockquote(n *html.Node) string {
+	content := strings.TrimSpace(normalize(r.children(n), r.markdown))
+	if content == "" {
+		return ""
+	}
+	if !r.markdown {
+		return "\n\n" + content + "\n\n"
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+// table converts a table, one row per line; in markdown, the first row is the header.
+func (r *renderer) table(n *html.Node) string {
+	var rows [][]string
+	walk(n, func(child *html.Node) bool {
+		if child.Type != html.ElementNode {
+			return true
+		}
+		if child.DataAtom == atom.Table {
+			return false // Nested tables are flattened into their cell.
+		}
+		if child.DataAtom != atom.Tr {
+			return true
+		}
+		var cells []string
+		for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+				text := collapse(r.children(cell))
+				if r.markdown {
+					text = strings.ReplaceAll(text, "|", `\|`)
+				}
+				cells = append(cells, text)
+			}
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+		return false
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\n")
+	for i, cells := range rows {
+		if !r.markdown {
+			sb.WriteString(strings.Join(cells, "\t") + "\n")
+			continue
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", len(cells)) + "\n")
+		}
+	}
+	return sb.String() + "\n"
+}
+
+// normalize trims the lines of rendered text and collapses blank lines. In markdown, the
+// indentation of list items and code blocks is kept.
+func normalize(text string, markdown bool) string {
+	lines := strings.Split(text, "\n")
+	inCode := false
+	for i, line := range lines {
+		if markdown && strings.TrimSpace(line) == codeFence {
+			inCode = !inCode
+			lines[i] = codeFence
+			continue
+		}
+		switch {
+		case inCode:
+			lines[i] = strings.TrimRight(line, " \t")
+		case markdown && isIndentedListLine(line):
+			lines[i] = strings.TrimRight(line, " ")
+		default:
+			lines[i] = strings.TrimSpace(line)
+		}
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// isIndentedListLine reports whether a line continues a list item, i.e. is indented by the list renderer.
+func isIndentedListLine(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(trimmed) == len(line) || trimmed == "" {
+		return false
+	}
+	return strings.HasPrefix(trimmed, "- ") || (len(trimmed) > 2 && trimmed[0] >= '0' && trimmed[0] <= '9' && strings.Contains(trimmed[:min(len(trimmed), 5)], ". "))
+}
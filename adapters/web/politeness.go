@@ -0,0 +1,183 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+// Defaults of the politeness layer.
+const (
+	// DefaultRobotsUserAgent is the user agent token checked against robots.txt.
+	DefaultRobotsUserAgent = "Strato-SDK-Bot"
+
+	// DefaultMaxCrawlDelay caps the crawl-delay a site can ask for.
+	DefaultMaxCrawlDelay = 30 * time.Second
+
+	// DefaultRobotsCacheTTL is how long a robots.txt file is cached.
+	DefaultRobotsCacheTTL = time.Hour
+
+	// DefaultRobotsCacheSize is the number of hosts whose robots.txt is cached.
+	DefaultRobotsCacheSize = 1000
+)
+
+// PolitenessConfig holds the configuration of the politeness layer.
+type PolitenessConfig struct {
+	// UserAgent is the user agent checked against robots.txt, e.g. "Strato-SDK-Bot". Its product
+	// token, up to the first "/" or space, is matched against the groups of robots.txt.
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// MinHostInterval is the minimum time between two requests to the same host.
+	// A longer crawl-delay in the host's robots.txt takes precedence.
+	MinHostInterval time.Duration `json:"min_host_interval,omitempty"`
+
+	// MaxCrawlDelay caps the crawl-delay a site can ask for; zero uses DefaultMaxCrawlDelay.
+	MaxCrawlDelay time.Duration `json:"max_crawl_delay,omitempty"`
+
+	// RobotsCacheTTL is how long a robots.txt file is cached; zero uses DefaultRobotsCacheTTL.
+	RobotsCacheTTL time.Duration `json:"robots_cache_ttl,omitempty"`
+
+	// RobotsCacheSize is the number of hosts whose robots.txt is cached; the least recently used
+	// host is evicted beyond it. Zero uses DefaultRobotsCacheSize.
+	RobotsCacheSize int `json:"robots_cache_size,omitempty"`
+
+	// HTTPClient fetches the robots.txt files.
+	HTTPClient *http.Client `json:"-"`
+}
+
+// Politeness makes scraping respect robots.txt and space out the requests to each host.
+// A host's robots.txt is fetched once and cached; pages it disallows for the configured
+// user agent are rejected with a *DisallowedError. Requests to the same host are spaced
+// by the host's crawl-delay, or by the configured minimum interval if that is longer.
+type Politeness struct {
+	config *PolitenessConfig
+	robots *robotsCache
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewPoliteness creates a new politeness layer.
+func NewPoliteness(config *PolitenessConfig) *Politeness {
+	if config == nil {
+		config = &PolitenessConfig{}
+	}
+
+	// Set default values
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultRobotsUserAgent
+	}
+	if config.MaxCrawlDelay <= 0 {
+		config.MaxCrawlDelay = DefaultMaxCrawlDelay
+	}
+	if config.RobotsCacheTTL <= 0 {
+		config.RobotsCacheTTL = DefaultRobotsCacheTTL
+	}
+	if config.RobotsCacheSize <= 0 {
+		config.RobotsCacheSize = DefaultRobotsCacheSize
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: robotsFetchTimeout}
+	}
+
+	return &Politeness{
+		config: config,
+		robots: newRobotsCache(config.HTTPClient, config.UserAgent, config.RobotsCacheTTL, config.RobotsCacheSize),
+		next:   make(map[string]time.Time),
+	}
+}
+
+// Wait checks the URL against the robots.txt of its host and waits until the host may be
+// requested again. Every call that returns nil reserves the next request slot of the host.
+//
+// Parameters:
+//   - ctx: A context.Context for cancellation while fetching robots.txt and waiting.
+//   - rawURL: The page that is about to be scraped.
+//
+// Returns:
+//   - error: A *DisallowedError if robots.txt disallows the page, or the context's error.
+func (p *Politeness) Wait(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("invalid URL %q", rawURL)
+	}
+
+	robots, err := p.robots.get(ctx, target)
+	if err != nil {
+		return fmt.Errorf("failed to check robots.txt of %s: %w", target.Host, err)
+	}
+	if robots.disallowAll {
+		return &DisallowedError{URL: rawURL, UserAgent: p.config.UserAgent, Unreachable: true}
+	}
+	if !robots.group.allowed(target.RequestURI()) {
+		return &DisallowedError{URL: rawURL, UserAgent: p.config.UserAgent}
+	}
+
+	interval := p.config.MinHostInterval
+	if robots.group != nil {
+		interval = max(interval, min(robots.group.crawlDelay, p.config.MaxCrawlDelay))
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	// Reserve the next slot of the host and wait for it.
+	host := strings.ToLower(target.Host)
+	p.mu.Lock()
+	slot := time.Now()
+	if next := p.next[host]; next.After(slot) {
+		slot = next
+	}
+	p.next[host] = slot.Add(interval)
+	p.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shared politeness layer of the configured scrapers.
+var (
+	politenessMu     sync.Mutex
+	politeness       *Politeness
+	politenessConfig types.PolitenessConfig
+)
+
+// sharedPoliteness returns the politeness layer for the configuration, or nil if it is disabled.
+// The layer is shared by every scraper, because robots.txt and request intervals concern the
+// scraped site rather than the scraper; it is kept as long as the configuration is unchanged.
+func sharedPoliteness(cfg types.PolitenessConfig) *Politeness {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	politenessMu.Lock()
+	defer politenessMu.Unlock()
+
+	if politeness == nil || politenessConfig != cfg {
+		politeness = NewPoliteness(&PolitenessConfig{
+			UserAgent:       cfg.UserAgent,
+			MinHostInterval: time.Duration(cfg.MinHostIntervalMs) * time.Millisecond,
+			MaxCrawlDelay:   time.Duration(cfg.MaxCrawlDelaySeconds) * time.Second,
+			RobotsCacheTTL:  time.Duration(cfg.RobotsCacheTTLSeconds) * time.Second,
+			RobotsCacheSize: cfg.RobotsCacheSize,
+		})
+		politenessConfig = cfg
+	}
+	return politeness
+}
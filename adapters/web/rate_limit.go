@@ -0,0 +1,35 @@
+package web
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// scraperRateLimiter returns the rate limiter configured for the scraper, or nil, which never blocks,
+// if rate limiting is disabled or the scraper is not configured. The limiter is shared by every
+// strategy and goroutine, so they draw from the same request budget.
+func scraperRateLimiter(scraper WebScraper) *resilience.RateLimiter {
+	webConfig := config.GetWebConfig()
+	if webConfig == nil {
+		return nil
+	}
+
+	rateLimit := webConfig.Scrapers[string(scraper)].RateLimit
+	if !rateLimit.Enabled || rateLimit.RequestsPerSecond <= 0 {
+		return nil
+	}
+	return resilience.SharedRateLimiter("web:"+string(scraper), float64(rateLimit.RequestsPerSecond), rateLimit.BurstSize)
+}
+
+// waitForRateLimit waits until the scraper's rate limit allows a request. Strategies call it before
+// every attempt, outside the attempt timeout and the circuit breaker, so that waiting for the
+// limiter neither fails attempts nor counts against the scraper's health.
+func waitForRateLimit(ctx context.Context, scraper WebScraper) error {
+	if err := scraperRateLimiter(scraper).Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait for scraper %s aborted: %w", scraper, err)
+	}
+	return nil
+}
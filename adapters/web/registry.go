@@ -1,55 +1,215 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
+// ErrScraperDisabled is returned when a web scraper that was disabled at runtime is used.
+var ErrScraperDisabled = errors.New("web scraper is disabled")
+
 // AdapterFactory defines the function signature for a web adapter factory.
 type AdapterFactory func() (WebAdapter, error)
 
-// WebRegistry is a registry for web adapters.
+// registryEntry is a registered factory together with the revision it was registered at.
+type registryEntry struct {
+	factory  AdapterFactory
+	revision uint64
+}
+
+// WebRegistry is a concurrency-safe registry for web adapters.
+// Scrapers can be registered, replaced, unregistered and disabled at runtime; strategies
+// pick up these changes on their next scrape.
 type WebRegistry struct {
-	mu        sync.RWMutex
-	factories map[WebScraper]AdapterFactory
+	mu       sync.RWMutex
+	entries  map[WebScraper]registryEntry
+	disabled map[WebScraper]bool
+	revision uint64
 }
 
 // NewWebRegistry creates a new web adapter registry.
 func NewWebRegistry() *WebRegistry {
 	return &WebRegistry{
-		factories: make(map[WebScraper]AdapterFactory),
+		entries:  make(map[WebScraper]registryEntry),
+		disabled: make(map[WebScraper]bool),
 	}
 }
 
-// Register registers a web adapter factory.
+// Register registers a web adapter factory, replacing the factory already registered for the scraper.
 func (r *WebRegistry) Register(scraper WebScraper, factory AdapterFactory) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.factories[scraper] = factory
+	r.revision++
+	r.entries[scraper] = registryEntry{factory: factory, revision: r.revision}
+}
+
+// Unregister removes the factory of the scraper and reports whether one was registered.
+func (r *WebRegistry) Unregister(scraper WebScraper) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, exists := r.entries[scraper]
+	delete(r.entries, scraper)
+	return exists
 }
 
 // Create creates a web adapter instance.
 func (r *WebRegistry) Create(scraper WebScraper) (WebAdapter, error) {
+	adapter, _, err := r.create(scraper)
+	return adapter, err
+}
+
+// create creates a web adapter instance and returns the revision of the factory that created it.
+func (r *WebRegistry) create(scraper WebScraper) (WebAdapter, uint64, error) {
 	r.mu.RLock()
-	factory, exists := r.factories[scraper]
+	entry, exists := r.entries[scraper]
+	disabled := r.disabled[scraper]
 	r.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("unregistered web scraper: %s", scraper)
+		return nil, 0, fmt.Errorf("unregistered web scraper: %s", scraper)
+	}
+	if disabled {
+		return nil, 0, fmt.Errorf("%w: %s", ErrScraperDisabled, scraper)
 	}
 
-	return factory()
+	adapter, err := entry.factory()
+	return adapter, entry.revision, err
+}
+
+// List returns the registered scrapers, sorted by name.
+func (r *WebRegistry) List() []WebScraper {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scrapers := make([]WebScraper, 0, len(r.entries))
+	for scraper := range r.entries {
+		scrapers = append(scrapers, scraper)
+	}
+	sort.Slice(scrapers, func(i, j int) bool { return scrapers[i] < scrapers[j] })
+	return scrapers
+}
+
+// IsRegistered reports whether a factory is registered for the scraper.
+func (r *WebRegistry) IsRegistered(scraper WebScraper) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.entries[scraper]
+	return exists
+}
+
+// SetEnabled enables or disables the scraper. A disabled scraper stays registered but is skipped
+// by the strategies until it is enabled again; the setting survives re-registration.
+func (r *WebRegistry) SetEnabled(scraper WebScraper, enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if enabled {
+		delete(r.disabled, scraper)
+	} else {
+		r.disabled[scraper] = true
+	}
+}
+
+// IsEnabled reports whether the scraper has not been disabled.
+func (r *WebRegistry) IsEnabled(scraper WebScraper) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.disabled[scraper]
+}
+
+// Revision returns the revision of the factory registered for the scraper, or zero if there is none.
+// The revision changes whenever the factory is replaced, so adapters created by an older factory can be discarded.
+func (r *WebRegistry) Revision(scraper WebScraper) uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.entries[scraper].revision
+}
+
+// AdapterInfo describes a web scraper known to the SDK.
+type AdapterInfo struct {
+	// Scraper is the name of the scraper.
+	Scraper WebScraper `json:"scraper"`
+
+	// Registered reports whether the scraper is registered, i.e. it can be used by the strategies.
+	Registered bool `json:"registered"`
+
+	// Enabled reports whether the scraper has not been disabled at runtime.
+	Enabled bool `json:"enabled"`
+
+	// AdapterDescriptor is the description the adapter package registered, if any.
+	AdapterDescriptor
 }
 
 // globalRegistry is the global instance of the web adapter registry.
 var globalRegistry = NewWebRegistry()
 
-// RegisterWebAdapter registers a web adapter globally.
-func RegisterWebAdapter(engine WebScraper, factory AdapterFactory) {
-	globalRegistry.Register(engine, factory)
+// RegisterWebAdapter registers a web adapter globally, replacing the adapter already registered for the scraper.
+func RegisterWebAdapter(scraper WebScraper, factory AdapterFactory) {
+	globalRegistry.Register(scraper, factory)
+}
+
+// UnregisterWebAdapter removes a web adapter from the global registry and reports whether it was registered.
+func UnregisterWebAdapter(scraper WebScraper) bool {
+	return globalRegistry.Unregister(scraper)
 }
 
 // CreateWebAdapter creates a web adapter instance from the global registry.
-func CreateWebAdapter(engine WebScraper) (WebAdapter, error) {
-	return globalRegistry.Create(engine)
+func CreateWebAdapter(scraper WebScraper) (WebAdapter, error) {
+	return globalRegistry.Create(scraper)
+}
+
+// EnableWebAdapter enables a web scraper that was disabled at runtime.
+func EnableWebAdapter(scraper WebScraper) {
+	globalRegistry.SetEnabled(scraper, true)
+}
+
+// DisableWebAdapter disables a web scraper at runtime. The strategies skip the scraper
+// from their next scrape on, without a restart, until it is enabled again.
+func DisableWebAdapter(scraper WebScraper) {
+	globalRegistry.SetEnabled(scraper, false)
+}
+
+// IsWebAdapterEnabled reports whether a web scraper has not been disabled at runtime.
+func IsWebAdapterEnabled(scraper WebScraper) bool {
+	return globalRegistry.IsEnabled(scraper)
+}
+
+// ListWebAdapters describes every web scraper that is registered or has an adapter creator, sorted by name.
+func ListWebAdapters() []AdapterInfo {
+	names := make(map[WebScraper]bool)
+	for _, scraper := range globalRegistry.List() {
+		names[scraper] = true
+	}
+	for _, name := range adapterCreatorNames() {
+		names[WebScraper(name)] = true
+	}
+
+	infos := make([]AdapterInfo, 0, len(names))
+	for scraper := range names {
+		info, _ := DescribeWebAdapter(scraper)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Scraper < infos[j].Scraper })
+	return infos
+}
+
+// DescribeWebAdapter describes a web scraper: whether it is registered and enabled,
+// and the capabilities and configuration schema its adapter declares.
+//
+// Parameters:
+//   - scraper: The name of the scraper.
+//
+// Returns:
+//   - AdapterInfo: The description of the scraper.
+//   - bool: Whether the scraper is known, i.e. registered or backed by an adapter creator.
+func DescribeWebAdapter(scraper WebScraper) (AdapterInfo, bool) {
+	descriptor, described := getAdapterDescriptor(string(scraper))
+	registered := globalRegistry.IsRegistered(scraper)
+	return AdapterInfo{
+		Scraper:           scraper,
+		Registered:        registered,
+		Enabled:           globalRegistry.IsEnabled(scraper),
+		AdapterDescriptor: descriptor,
+	}, registered || described || hasAdapterCreator(string(scraper))
 }
@@ -0,0 +1,316 @@
+package web
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits of fetching robots.txt, following RFC 9309.
+const (
+	// robotsMaxSize is the number of bytes of a robots.txt file that are parsed.
+	robotsMaxSize = 500 << 10
+
+	// robotsFetchTimeout bounds fetching a robots.txt file.
+	robotsFetchTimeout = 10 * time.Second
+
+	// robotsErrorTTL is how long a host whose robots.txt could not be fetched stays disallowed
+	// before the file is fetched again.
+	robotsErrorTTL = time.Minute
+)
+
+// robotsGroup holds the rules of robots.txt that apply to a user agent.
+type robotsGroup struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsRule is an allow or disallow rule of robots.txt.
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsFile holds the groups of a robots.txt file by the product token of their user agent,
+// in lower case.
+type robotsFile struct {
+	groups map[string]*robotsGroup
+}
+
+// parseRobots parses a robots.txt file. Groups of the same user agent are merged, and lines
+// that cannot be parsed are ignored.
+func parseRobots(r io.Reader) *robotsFile {
+	file := &robotsFile{groups: make(map[string]*robotsGroup)}
+
+	var agents []string
+	inRules := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A user-agent line after rules starts a new group.
+			if inRules {
+				agents = nil
+				inRules = false
+			}
+			agent := robotsProductToken(value)
+			agents = append(agents, agent)
+			if file.groups[agent] == nil {
+				file.groups[agent] = &robotsGroup{}
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // An empty disallow allows everything.
+			}
+			for _, agent := range agents {
+				group := file.groups[agent]
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			inRules = true
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds < 0 {
+				continue
+			}
+			for _, agent := range agents {
+				if group := file.groups[agent]; group.crawlDelay == 0 {
+					group.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+	return file
+}
+
+// group returns the group that applies to the user agent: the group whose user agent equals
+// the product token of the user agent, ignoring case as RFC 9309 requires, or else the group
+// of "*". It returns nil if no group applies.
+func (f *robotsFile) group(userAgent string) *robotsGroup {
+	if group, exists := f.groups[robotsProductToken(userAgent)]; exists {
+		return group
+	}
+	return f.groups["*"]
+}
+
+// robotsProductToken returns the product token of a user agent in lower case: the user agent
+// up to the first "/" or space, e.g. "strato-sdk-bot" for "Strato-SDK-Bot/1.0 (+https://...)".
+func robotsProductToken(userAgent string) string {
+	token := strings.TrimSpace(userAgent)
+	if i := strings.IndexAny(token, "/ \t"); i >= 0 {
+		token = token[:i]
+	}
+	return strings.ToLower(token)
+}
+
+// allowed reports whether the path, including its query, may be fetched. The longest matching
+// rule decides; on a tie, allow wins.
+func (g *robotsGroup) allowed(path string) bool {
+	if g == nil || path == "/robots.txt" {
+		return true
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range g.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed = rule.allow
+			longest = len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// matchRobotsPattern reports whether the path matches a robots.txt pattern, where "*" matches
+// any sequence of characters and a trailing "$" anchors the pattern at the end of the path.
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// robotsEntry is the cached robots.txt of a host.
+type robotsEntry struct {
+	key         string
+	element     *list.Element
+	ready       chan struct{}
+	group       *robotsGroup
+	disallowAll bool
+	expires     time.Time
+}
+
+// robotsCache fetches and caches the robots.txt files of hosts. It keeps the files of at most
+// maxEntries hosts and evicts the least recently used one beyond that.
+type robotsCache struct {
+	client     *http.Client
+	userAgent  string
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+	lru     *list.List // Entries from the most to the least recently used.
+}
+
+// newRobotsCache creates a cache that keeps the robots.txt files of up to maxEntries hosts for
+// the given time.
+func newRobotsCache(client *http.Client, userAgent string, ttl time.Duration, maxEntries int) *robotsCache {
+	return &robotsCache{
+		client:     client,
+		userAgent:  userAgent,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*robotsEntry),
+		lru:        list.New(),
+	}
+}
+
+// get returns the robots.txt of the URL's host, fetching it if it is not cached or has expired.
+// Concurrent calls for the same host share a single fetch.
+func (c *robotsCache) get(ctx context.Context, target *url.URL) (*robotsEntry, error) {
+	key := target.Scheme + "://" + strings.ToLower(target.Host)
+
+	c.mu.Lock()
+	entry, exists := c.entries[key]
+	if !exists || (isClosed(entry.ready) && time.Now().After(entry.expires)) {
+		if exists {
+			c.lru.Remove(entry.element)
+		}
+		entry = &robotsEntry{key: key, ready: make(chan struct{})}
+		entry.element = c.lru.PushFront(entry)
+		c.entries[key] = entry
+		c.evict()
+		c.mu.Unlock()
+
+		// The fetch is shared, so it must not be cancelled with the context of the first caller.
+		c.fetch(context.WithoutCancel(ctx), key, entry)
+		close(entry.ready)
+		return entry, nil
+	}
+	c.lru.MoveToFront(entry.element)
+	c.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return entry, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// evict removes the least recently used entries beyond the size limit. Callers that are still
+// waiting for an evicted entry are not affected. c.mu must be held.
+func (c *robotsCache) evict() {
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Remove(c.lru.Back()).(*robotsEntry)
+		delete(c.entries, oldest.key)
+	}
+}
+
+// fetch fetches the robots.txt at the origin and fills the entry. As RFC 9309 requires, a missing
+// file (4xx) allows everything, and a file that cannot be fetched (5xx, 429 or a network error)
+// disallows everything until it is fetched again.
+func (c *robotsCache) fetch(ctx context.Context, origin string, entry *robotsEntry) {
+	ctx, cancel := context.WithTimeout(ctx, robotsFetchTimeout)
+	defer cancel()
+
+	entry.expires = time.Now().Add(c.ttl)
+	unreachable := func() {
+		entry.disallowAll = true
+		entry.expires = time.Now().Add(min(c.ttl, robotsErrorTTL))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		unreachable()
+		return
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		unreachable()
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		entry.group = parseRobots(io.LimitReader(resp.Body, robotsMaxSize)).group(c.userAgent)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		unreachable()
+	default:
+		// No robots.txt: everything is allowed.
+	}
+}
+
+// isClosed reports whether the channel is closed, i.e. the entry has been fetched.
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// DisallowedError is returned for a URL that robots.txt disallows for the configured user agent.
+type DisallowedError struct {
+	// URL is the disallowed page.
+	URL string
+	// UserAgent is the user agent the robots.txt rules were checked for.
+	UserAgent string
+	// Unreachable reports that the host's robots.txt could not be fetched, which disallows every page.
+	Unreachable bool
+}
+
+// Error implements the error interface.
+func (e *DisallowedError) Error() string {
+	if e.Unreachable {
+		return fmt.Sprintf("robots.txt for %s could not be fetched, so the page is disallowed", e.URL)
+	}
+	return fmt.Sprintf("robots.txt disallows %s for user agent %q", e.URL, e.UserAgent)
+}
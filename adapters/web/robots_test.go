@@ -0,0 +1,379 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMatchRobotsPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "/", path: "/anything", want: true},
+		{pattern: "/private", path: "/private", want: true},
+		{pattern: "/private", path: "/private/page", want: true},
+		{pattern: "/private", path: "/privateer", want: true},
+		{pattern: "/private", path: "/public", want: false},
+		{pattern: "/private/", path: "/private", want: false},
+		{pattern: "/Private", path: "/private", want: false},
+
+		{pattern: "/*.pdf", path: "/docs/report.pdf", want: true},
+		{pattern: "/*.pdf", path: "/docs/report.pdf?download=1", want: true},
+		{pattern: "/*.pdf", path: "/docs/report.html", want: false},
+		{pattern: "/a*b*c", path: "/a-x-b-y-c", want: true},
+		{pattern: "/a*b*c", path: "/a-x-c-y-b", want: false},
+		{pattern: "*", path: "/anything", want: true},
+
+		{pattern: "/*.pdf$", path: "/docs/report.pdf", want: true},
+		{pattern: "/*.pdf$", path: "/docs/report.pdf?download=1", want: false},
+		{pattern: "/exact$", path: "/exact", want: true},
+		{pattern: "/exact$", path: "/exact/more", want: false},
+		{pattern: "/$", path: "/", want: true},
+		{pattern: "/$", path: "/page", want: false},
+		{pattern: "/*/end$", path: "/a/b/end", want: true},
+	}
+	for _, tt := range tests {
+		if got := matchRobotsPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchRobotsPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRobotsGroupAllowed(t *testing.T) {
+	group := &robotsGroup{rules: []robotsRule{
+		{allow: false, pattern: "/private"},
+		{allow: true, pattern: "/private/public"},
+		{allow: false, pattern: "/*.json$"},
+		{allow: true, pattern: "/tie"},
+		{allow: false, pattern: "/tie"},
+		{allow: false, pattern: "/mixed*"},
+		{allow: true, pattern: "/mixed/"},
+	}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/", want: true},
+		{path: "/private", want: false},
+		{path: "/private/page", want: false},
+		{path: "/private/public/page", want: true}, // The longer allow rule wins.
+		{path: "/data.json", want: false},          // Wildcard and anchor.
+		{path: "/data.json?x=1", want: true},       // The anchor does not match the query.
+		{path: "/tie/page", want: true},            // Allow wins a tie.
+		{path: "/mixed/page", want: true},          // Equal length "/mixed*" and "/mixed/": allow wins.
+		{path: "/mixedup", want: false},
+		{path: "/robots.txt", want: true},
+	}
+	for _, tt := range tests {
+		if got := group.allowed(tt.path); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var none *robotsGroup
+	if !none.allowed("/private") {
+		t.Error("a nil group disallowed a page")
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	const robots = `# Example robots.txt
+User-agent: *
+Disallow: /private   # comment
+Allow: /private/open
+Crawl-delay: 2
+
+User-agent: Strato-SDK-Bot
+User-agent: OtherBot
+Disallow: /bots
+Crawl-delay: 0.5
+
+User-agent: strato-sdk-bot
+Disallow: /more
+Crawl-delay: 10
+
+User-agent: EmptyBot
+Disallow:
+
+User-agent: Bot
+Disallow: /
+
+User-agent: VersionBot/2.0
+Disallow: /versioned
+
+invalid line
+Sitemap: https://example.com/sitemap.xml
+`
+	file := parseRobots(strings.NewReader(robots))
+
+	star := file.groups["*"]
+	if star == nil || len(star.rules) != 2 || star.crawlDelay != 2*time.Second {
+		t.Fatalf("group * = %+v, want 2 rules and a crawl-delay of 2s", star)
+	}
+
+	// The two groups of Strato-SDK-Bot are merged, and the first crawl-delay is kept.
+	bot := file.group("Strato-SDK-Bot/1.0 (+https://example.com/bot)")
+	if bot == nil || bot != file.groups["strato-sdk-bot"] {
+		t.Fatalf("group() did not match the product token of the user agent")
+	}
+	if len(bot.rules) != 2 || bot.crawlDelay != 500*time.Millisecond {
+		t.Errorf("merged group = %+v, want 2 rules and a crawl-delay of 500ms", bot)
+	}
+	if bot.allowed("/bots/page") || bot.allowed("/more") || !bot.allowed("/private") {
+		t.Error("merged group does not apply the rules of both groups, and only those")
+	}
+
+	// A group shared by several user agents applies to each of them.
+	if other := file.group("OtherBot"); other == nil || other.allowed("/bots") || !other.allowed("/more") {
+		t.Errorf("group of OtherBot = %+v", other)
+	}
+
+	// An empty disallow allows everything.
+	if empty := file.group("EmptyBot"); empty == nil || len(empty.rules) != 0 || !empty.allowed("/private") {
+		t.Errorf("group of EmptyBot = %+v, want no rules", empty)
+	}
+
+	// A version in the user-agent line of robots.txt is not part of the product token.
+	if versioned := file.group("versionbot"); versioned == nil || versioned.allowed("/versioned") {
+		t.Errorf("group of VersionBot = %+v", versioned)
+	}
+
+	// Other user agents fall back to the * group. Product tokens are matched as a whole, so a
+	// group does not apply to user agents that merely contain its name.
+	for _, userAgent := range []string{"UnknownBot", "Mozilla/5.0 (compatible; Strato-SDK-Bot/1.0)", "Strato-SDK-Bot-Beta", "Robot"} {
+		if fallback := file.group(userAgent); fallback != star {
+			t.Errorf("group(%q) = %+v, want the * group", userAgent, fallback)
+		}
+	}
+
+	if group := parseRobots(strings.NewReader("User-agent: OnlyBot\nDisallow: /\n")).group("UnknownBot"); group != nil {
+		t.Errorf("group() without a * group = %+v, want nil", group)
+	}
+}
+
+func TestRobotsProductToken(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{userAgent: "Strato-SDK-Bot", want: "strato-sdk-bot"},
+		{userAgent: "Strato-SDK-Bot/1.0", want: "strato-sdk-bot"},
+		{userAgent: "Strato-SDK-Bot/1.0 (+https://example.com/bot)", want: "strato-sdk-bot"},
+		{userAgent: "  ExampleBot extra", want: "examplebot"},
+		{userAgent: "*", want: "*"},
+		{userAgent: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := robotsProductToken(tt.userAgent); got != tt.want {
+			t.Errorf("robotsProductToken(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+func TestRobotsCacheFetch(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		body            string
+		wantDisallowAll bool
+		wantAllowed     bool
+	}{
+		{name: "rules", status: http.StatusOK, body: "User-agent: *\nDisallow: /page\n", wantAllowed: false},
+		{name: "not found", status: http.StatusNotFound, wantAllowed: true},
+		{name: "forbidden", status: http.StatusForbidden, wantAllowed: true},
+		{name: "gone", status: http.StatusGone, wantAllowed: true},
+		{name: "too many requests", status: http.StatusTooManyRequests, wantDisallowAll: true},
+		{name: "server error", status: http.StatusInternalServerError, wantDisallowAll: true},
+		{name: "unavailable", status: http.StatusServiceUnavailable, wantDisallowAll: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgent atomic.Value
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/robots.txt" {
+					t.Errorf("fetched %s, want /robots.txt", r.URL.Path)
+				}
+				userAgent.Store(r.UserAgent())
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cache := newRobotsCache(server.Client(), DefaultRobotsUserAgent, time.Hour, DefaultRobotsCacheSize)
+			target, _ := url.Parse(server.URL + "/page")
+			entry, err := cache.get(context.Background(), target)
+			if err != nil {
+				t.Fatalf("get() error = %v", err)
+			}
+
+			if entry.disallowAll != tt.wantDisallowAll {
+				t.Errorf("disallowAll = %v, want %v", entry.disallowAll, tt.wantDisallowAll)
+			}
+			if !tt.wantDisallowAll && entry.group.allowed("/page") != tt.wantAllowed {
+				t.Errorf("allowed(/page) = %v, want %v", entry.group.allowed("/page"), tt.wantAllowed)
+			}
+			if tt.wantDisallowAll && time.Until(entry.expires) > robotsErrorTTL {
+				t.Errorf("an unreachable robots.txt is cached until %v, want at most %v", entry.expires, robotsErrorTTL)
+			}
+			if got, _ := userAgent.Load().(string); got != DefaultRobotsUserAgent {
+				t.Errorf("User-Agent = %q, want %q", got, DefaultRobotsUserAgent)
+			}
+		})
+	}
+}
+
+func TestRobotsCacheFetchNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	cache := newRobotsCache(server.Client(), DefaultRobotsUserAgent, time.Hour, DefaultRobotsCacheSize)
+	target, _ := url.Parse(server.URL + "/page")
+	entry, err := cache.get(context.Background(), target)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if !entry.disallowAll {
+		t.Error("an unreachable host does not disallow every page")
+	}
+}
+
+func TestRobotsCacheSharesFetches(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	defer server.Close()
+
+	cache := newRobotsCache(server.Client(), DefaultRobotsUserAgent, time.Hour, DefaultRobotsCacheSize)
+	target, _ := url.Parse(server.URL + "/page")
+
+	done := make(chan error)
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, err := cache.get(context.Background(), target)
+			done <- err
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("get() error = %v", err)
+		}
+	}
+	if _, err := cache.get(context.Background(), target); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want once", n)
+	}
+}
+
+func TestRobotsCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	fetches := make(map[string]int)
+	var mu sync.Mutex
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches[r.Host]++
+		mu.Unlock()
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	})
+	servers := make([]*url.URL, 3)
+	for i := range servers {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+		servers[i], _ = url.Parse(server.URL + "/page")
+	}
+
+	cache := newRobotsCache(http.DefaultClient, DefaultRobotsUserAgent, time.Hour, 2)
+	get := func(target *url.URL) {
+		t.Helper()
+		if _, err := cache.get(context.Background(), target); err != nil {
+			t.Fatalf("get() error = %v", err)
+		}
+	}
+
+	get(servers[0])
+	get(servers[1])
+	get(servers[0]) // servers[1] is now the least recently used host.
+	get(servers[2]) // Evicts servers[1].
+	if len(cache.entries) != 2 || cache.lru.Len() != 2 {
+		t.Fatalf("cache holds %d entries and %d list elements, want 2", len(cache.entries), cache.lru.Len())
+	}
+
+	get(servers[0])
+	get(servers[1])
+	mu.Lock()
+	defer mu.Unlock()
+	if n := fetches[servers[0].Host]; n != 1 {
+		t.Errorf("robots.txt of the recently used host fetched %d times, want once", n)
+	}
+	if n := fetches[servers[1].Host]; n != 2 {
+		t.Errorf("robots.txt of the evicted host fetched %d times, want twice", n)
+	}
+}
+
+func TestPolitenessWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\nCrawl-delay: 0.05\n"))
+	}))
+	defer server.Close()
+
+	politeness := NewPoliteness(&PolitenessConfig{HTTPClient: server.Client()})
+	ctx := context.Background()
+
+	var disallowed *DisallowedError
+	if err := politeness.Wait(ctx, server.URL+"/private/page"); !errors.As(err, &disallowed) || disallowed.Unreachable {
+		t.Errorf("Wait() on a disallowed page error = %v, want a *DisallowedError", err)
+	}
+	if err := politeness.Wait(ctx, "ftp://example.com/file"); err == nil {
+		t.Error("Wait() on a non-http URL error = nil, want an error")
+	}
+
+	// Requests to the host are spaced by the crawl-delay.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := politeness.Wait(ctx, server.URL+"/page"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 requests took %v, want them spaced by the 50ms crawl-delay", elapsed)
+	}
+
+	// A cancelled wait returns the context error.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := politeness.Wait(cancelled, server.URL+"/page"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() with a cancelled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestPolitenessCapsCrawlDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 3600\n"))
+	}))
+	defer server.Close()
+
+	politeness := NewPoliteness(&PolitenessConfig{HTTPClient: server.Client(), MaxCrawlDelay: 20 * time.Millisecond})
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := politeness.Wait(context.Background(), server.URL+"/page"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Errorf("2 requests took %v, want them spaced by the 20ms cap", elapsed)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/pkg/logging"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 )
 
 // WebStrategy defines the interface for a web scraping strategy.
@@ -15,6 +18,10 @@ type WebStrategy interface {
 
 	// ExecuteMultiple executes the web scraping strategy for multiple URLs.
 	ExecuteMultiple(ctx context.Context, urls []string, options *ScrapeOptions) ([]*WebContent, error)
+
+	// ExecuteBatch executes the web scraping strategy for multiple URLs and returns one result
+	// per URL, in the order of the URLs.
+	ExecuteBatch(ctx context.Context, urls []string, options *ScrapeOptions) []*ScrapeResult
 }
 
 // WebStrategyConfig holds the configuration for a web scraping strategy.
@@ -28,15 +35,35 @@ type WebStrategyConfig struct {
 	// EnableFallback determines whether to try the next scraper on failure.
 	EnableFallback bool `json:"enable_fallback"`
 
-	// FailFast determines whether to stop immediately after the first failure.
+	// FailFast determines whether to fall back immediately after a scraper failure, without retries.
 	FailFast bool `json:"fail_fast"`
+
+	// MaxRetries is the number of times a scrape is retried on the same scraper before falling back.
+	// Only transient errors are retried: network errors, timeouts, and 408, 429 and 5xx responses.
+	MaxRetries int `json:"max_retries"`
+
+	// Timeout bounds each scraping attempt. Zero leaves attempts bounded only by the caller's context.
+	Timeout time.Duration `json:"timeout"`
+
+	// CircuitBreaker configures the circuit breaker that tracks the health of each scraper
+	// and skips scrapers that keep failing until they recover.
+	CircuitBreaker resilience.BreakerConfig `json:"circuit_breaker"`
+
+	// Hedge configures hedged scrapes: when a scraper is slow to answer, the next scraper in the
+	// fallback order is started in parallel and the first success wins.
+	Hedge resilience.HedgeConfig `json:"hedge"`
+
+	// Politeness, if set, checks every page against robots.txt and spaces out the requests to
+	// each host. Its waits happen before the attempt timeout and the circuit breaker start.
+	Politeness *Politeness `json:"-"`
 }
 
 // DefaultWebStrategy is the default implementation of the web scraping strategy.
 type DefaultWebStrategy struct {
 	mu       sync.RWMutex
 	config   *WebStrategyConfig
-	adapters map[WebScraper]WebAdapter
+	adapters map[WebScraper]strategyAdapter
+	breakers map[WebScraper]*resilience.CircuitBreaker
 }
 
 // NewDefaultWebStrategy creates a new default web scraping strategy.
@@ -54,6 +81,7 @@ func NewDefaultWebStrategy(config *WebStrategyConfig) *DefaultWebStrategy {
 		config.DefaultFallbackOrder = []WebScraper{
 			"jina",
 			"firecrawl",
+			"native",
 		}
 	}
 
@@ -63,7 +91,8 @@ func NewDefaultWebStrategy(config *WebStrategyConfig) *DefaultWebStrategy {
 
 	return &DefaultWebStrategy{
 		config:   config,
-		adapters: make(map[WebScraper]WebAdapter),
+		adapters: make(map[WebScraper]strategyAdapter),
+		breakers: make(map[WebScraper]*resilience.CircuitBreaker),
 	}
 }
 
@@ -86,6 +115,22 @@ func NewDefaultWebStrategyFromConfig() (WebStrategy, error) {
 		DefaultFallbackOrder: convertStringSliceToWebScrapers(webConfig.Strategy.DefaultFallbackOrder),
 		EnableFallback:       webConfig.Strategy.EnableFallback,
 		FailFast:             webConfig.Strategy.FailFast,
+		MaxRetries:           webConfig.Strategy.MaxRetries,
+		Timeout:              time.Duration(webConfig.Strategy.TimeoutSeconds) * time.Second,
+		CircuitBreaker: resilience.BreakerConfig{
+			Enabled:            webConfig.Strategy.CircuitBreaker.Enabled,
+			FailureThreshold:   webConfig.Strategy.CircuitBreaker.FailureThreshold,
+			ErrorRateThreshold: webConfig.Strategy.CircuitBreaker.ErrorRateThreshold,
+			MinRequests:        webConfig.Strategy.CircuitBreaker.MinRequests,
+			WindowSize:         webConfig.Strategy.CircuitBreaker.WindowSize,
+			Cooldown:           time.Duration(webConfig.Strategy.CircuitBreaker.CooldownSeconds) * time.Second,
+		},
+		Hedge: resilience.HedgeConfig{
+			Enabled:  webConfig.Strategy.Hedge.Enabled,
+			Delay:    time.Duration(webConfig.Strategy.Hedge.DelayMs) * time.Millisecond,
+			Adaptive: webConfig.Strategy.Hedge.Adaptive,
+		},
+		Politeness: sharedPoliteness(webConfig.Politeness),
 	}
 
 	// Build the list of scraper configurations (only enabled ones)
@@ -112,76 +157,251 @@ func convertStringSliceToWebScrapers(scrapers []string) []WebScraper {
 }
 
 // Execute executes the web scraping strategy for a single URL.
+// Each scraper retries transient failures according to the retry policy, and with hedging
+// enabled, the next scraper is started in parallel when the current one is slow to answer;
+// the first success wins and the slower scrape is cancelled. With a politeness layer, the URL
+// is checked against robots.txt and the host's turn is awaited before every attempt of every
+// scraper, hedged ones included. The wait counts neither against the attempt timeout nor
+// against the scrapers' health.
 func (s *DefaultWebStrategy) Execute(ctx context.Context, url string, options *ScrapeOptions) (*WebContent, error) {
 	scrapers := s.getScraperOrder()
+	if len(scrapers) == 0 {
+		return nil, fmt.Errorf("all web scrapers are disabled or unavailable: %w", resilience.ErrCircuitOpen)
+	}
+	if !s.config.EnableFallback {
+		scrapers = scrapers[:1]
+	}
 
-	var lastErr error
-	for i, scraper := range scrapers {
+	result, index, err := resilience.Hedge(ctx, len(scrapers), func(i int) time.Duration {
+		return s.config.Hedge.DelayFor(s.breaker(scrapers[i]))
+	}, func(ctx context.Context, i int) (*WebContent, error) {
+		scraper := scrapers[i]
 		adapter, err := s.getOrCreateAdapter(scraper)
 		if err != nil {
-			lastErr = fmt.Errorf("failed to create adapter for %s: %w", scraper, err)
-			if s.config.FailFast || i == len(scrapers)-1 {
-				continue
-			}
+			return nil, fmt.Errorf("failed to create adapter for %s: %w", scraper, err)
 		}
 
-		result, err := adapter.Scrape(ctx, url, options)
-		if err == nil {
-			// On success, you can mark the used engine in the result metadata if needed.
-			if result != nil {
-				if result.Images == nil {
-					result.Images = make([]Image, 0)
-				}
-				if result.Links == nil {
-					result.Links = make([]Link, 0)
-				}
-			}
-			return result, nil
+		result, err := s.scrapeWith(ctx, scraper, adapter, s.retryPolicy(scraper, url), url, options)
+		if err != nil {
+			return nil, fmt.Errorf("scraper %s failed: %w", scraper, err)
 		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("all web scrapers failed, last error: %w", err)
+	}
 
-		lastErr = fmt.Errorf("scraper %s failed: %w", scraper, err)
-
-		// If fallback is disabled or this is the last scraper, return the error.
-		if !s.config.EnableFallback || i == len(scrapers)-1 {
-			break
+	if result != nil {
+		result.Scraper = scrapers[index]
+		if result.Images == nil {
+			result.Images = make([]Image, 0)
+		}
+		if result.Links == nil {
+			result.Links = make([]Link, 0)
 		}
 	}
-
-	return nil, fmt.Errorf("all web scrapers failed, last error: %w", lastErr)
+	return result, nil
 }
 
 // ExecuteMultiple executes the web scraping strategy for multiple URLs.
+// It returns the pages that were scraped, in the order of the URLs, and fails only if no page
+// could be scraped. Use ExecuteBatch to learn why individual pages failed.
 func (s *DefaultWebStrategy) ExecuteMultiple(ctx context.Context, urls []string, options *ScrapeOptions) ([]*WebContent, error) {
+	contents, err := BatchContents(s.ExecuteBatch(ctx, urls, options))
+	if err != nil {
+		return nil, fmt.Errorf("all web scrapers failed to scrape multiple URLs: %w", err)
+	}
+	return contents, nil
+}
+
+// ExecuteBatch executes the web scraping strategy for multiple URLs and returns one result per
+// URL, in the order of the URLs. The URLs are scraped as a batch on the first scraper; only the
+// URLs that failed or came back empty, but were not disallowed by robots.txt, are passed on to
+// the next scraper in fallback order, and so on. Transient failures are retried on the same
+// scraper first, according to the retry policy.
+// Each result records the scraper that produced the page, or that failed last.
+func (s *DefaultWebStrategy) ExecuteBatch(ctx context.Context, urls []string, options *ScrapeOptions) []*ScrapeResult {
+	results := make([]*ScrapeResult, len(urls))
+	pending := make([]int, len(urls))
+	for i, url := range urls {
+		results[i] = &ScrapeResult{URL: url}
+		pending[i] = i
+	}
+
 	scrapers := s.getScraperOrder()
+	if len(scrapers) == 0 {
+		err := fmt.Errorf("all web scrapers are disabled or unavailable: %w", resilience.ErrCircuitOpen)
+		for _, result := range results {
+			result.Err = NewScrapeError(result.URL, err)
+		}
+		return results
+	}
+	if !s.config.EnableFallback {
+		scrapers = scrapers[:1]
+	}
 
-	var lastErr error
-	for i, scraper := range scrapers {
-		adapter, err := s.getOrCreateAdapter(scraper)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create adapter for %s: %w", scraper, err)
-			if s.config.FailFast || i == len(scrapers)-1 {
-				continue
+	for _, scraper := range scrapers {
+		if len(pending) == 0 || ctx.Err() != nil {
+			break
+		}
+		s.scrapeBatchWith(ctx, scraper, results, pending, options)
+
+		// Pass the pages that failed or came back empty on to the next scraper. Pages that
+		// robots.txt disallows are disallowed for every scraper.
+		var failed []int
+		for _, index := range pending {
+			if result := results[index]; !result.OK() && (result.Err == nil || result.Err.Kind != ScrapeErrorDisallowed) {
+				failed = append(failed, index)
 			}
 		}
-
-		results, err := adapter.ScrapeMultiple(ctx, urls, options)
-		if err == nil {
-			return results, nil
+		if len(failed) > 0 {
+			logging.Warnf("Web scraper %s failed to scrape %d of %d URLs", scraper, len(failed), len(pending))
 		}
+		pending = failed
+	}
+	return results
+}
 
-		lastErr = fmt.Errorf("scraper %s failed to scrape multiple URLs: %w", scraper, err)
+// scrapeBatchWith scrapes the pending URLs of a batch concurrently with the scraper, within the
+// scraper's batch limits, and stores the outcome of each URL in results. Each URL is retried on
+// its own according to the retry policy, and takes its turn with the politeness layer before
+// every attempt.
+func (s *DefaultWebStrategy) scrapeBatchWith(ctx context.Context, scraper WebScraper, results []*ScrapeResult, pending []int, options *ScrapeOptions) {
+	urls := make([]string, len(pending))
+	for i, index := range pending {
+		urls[i] = results[index].URL
+	}
 
-		// If fallback is disabled or this is the last scraper, return the error.
-		if !s.config.EnableFallback || i == len(scrapers)-1 {
-			break
+	var batch []*ScrapeResult
+	adapter, err := s.getOrCreateAdapter(scraper)
+	if err != nil {
+		err = fmt.Errorf("failed to create adapter for %s: %w", scraper, err)
+		batch = make([]*ScrapeResult, len(urls))
+		for i, url := range urls {
+			batch[i] = &ScrapeResult{URL: url, Err: NewScrapeError(url, err)}
 		}
+	} else {
+		batch = ScrapeConcurrently(ctx, func(ctx context.Context, url string, options *ScrapeOptions) (*WebContent, error) {
+			return s.scrapeWith(ctx, scraper, adapter, s.retryPolicy(scraper, url), url, options)
+		}, urls, options, batchOptionsOf(adapter))
+	}
+
+	for i, result := range batch {
+		result.Scraper = scraper
+		if result.Content != nil {
+			result.Content.Scraper = scraper
+		}
+		results[pending[i]] = result
+	}
+}
+
+// scrapeWith scrapes a page with the scraper, retrying transient failures according to the
+// policy and recording the outcome of every attempt with the scraper's circuit breaker.
+func (s *DefaultWebStrategy) scrapeWith(ctx context.Context, scraper WebScraper, adapter WebAdapter, policy resilience.RetryPolicy, url string, options *ScrapeOptions) (*WebContent, error) {
+	return resilience.Retry(ctx, policy, func(ctx context.Context) (*WebContent, error) {
+		var result *WebContent
+		err := s.track(scraper, func() (err error) {
+			result, err = adapter.Scrape(ctx, url, options)
+			return err
+		})
+		return result, err
+	})
+}
+
+// waitPolitely checks the URL against robots.txt and waits for the host's turn, if the strategy
+// has a politeness layer.
+func (s *DefaultWebStrategy) waitPolitely(ctx context.Context, url string) error {
+	if s.config.Politeness == nil {
+		return nil
 	}
+	return s.config.Politeness.Wait(ctx, url)
+}
 
-	return nil, fmt.Errorf("all web scrapers failed, last error: %w", lastErr)
+// retryPolicy returns the policy for retrying transient failures of the given scraper on the URL.
+// Every attempt first takes its turn with the politeness layer and then waits for the scraper's
+// rate limit, outside the attempt timeout and the circuit breaker.
+func (s *DefaultWebStrategy) retryPolicy(scraper WebScraper, url string) resilience.RetryPolicy {
+	policy := resilience.RetryPolicy{
+		MaxRetries:     s.config.MaxRetries,
+		AttemptTimeout: s.config.Timeout,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			logging.Warnf("Web scraper %s failed (attempt %d), retrying in %s: %v", scraper, attempt, delay, err)
+		},
+		BeforeAttempt: func(ctx context.Context, attempt int) error {
+			if err := s.waitPolitely(ctx, url); err != nil {
+				return err
+			}
+			return waitForRateLimit(ctx, scraper)
+		},
+	}
+	if s.config.FailFast {
+		policy.MaxRetries = 0
+	}
+	return policy
+}
+
+// track runs a call to the scraper through its circuit breaker, recording the outcome and latency.
+func (s *DefaultWebStrategy) track(scraper WebScraper, call func() error) error {
+	breaker := s.breaker(scraper)
+	if !breaker.Allow() {
+		return fmt.Errorf("scraper %s is unavailable: %w", scraper, resilience.ErrCircuitOpen)
+	}
+
+	start := time.Now()
+	err := call()
+	breaker.Record(err, time.Since(start))
+	return err
+}
+
+// Health returns the current health of every configured scraper, as tracked by its circuit breaker.
+func (s *DefaultWebStrategy) Health() map[WebScraper]resilience.Health {
+	scrapers := s.getConfiguredScrapers()
+
+	health := make(map[WebScraper]resilience.Health, len(scrapers))
+	for _, scraper := range scrapers {
+		health[scraper] = s.breaker(scraper).Health()
+	}
+	return health
+}
+
+// breaker returns the circuit breaker of the scraper, creating it if needed.
+func (s *DefaultWebStrategy) breaker(scraper WebScraper) *resilience.CircuitBreaker {
+	s.mu.RLock()
+	breaker, exists := s.breakers[scraper]
+	s.mu.RUnlock()
+	if exists {
+		return breaker
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Double check
+	if breaker, exists = s.breakers[scraper]; exists {
+		return breaker
+	}
+
+	breaker = resilience.NewCircuitBreaker(s.config.CircuitBreaker)
+	s.breakers[scraper] = breaker
+	return breaker
 }
 
 // getScraperOrder returns the order of scrapers to be executed.
+// Disabled scrapers are skipped, as are scrapers whose circuit breaker is open until their cooldown has elapsed.
 func (s *DefaultWebStrategy) getScraperOrder() []WebScraper {
+	configured := s.getConfiguredScrapers()
+
+	scrapers := make([]WebScraper, 0, len(configured))
+	for _, scraper := range configured {
+		if IsWebAdapterEnabled(scraper) && s.breaker(scraper).Ready() {
+			scrapers = append(scrapers, scraper)
+		}
+	}
+	return scrapers
+}
+
+// getConfiguredScrapers returns the configured order of scrapers.
+func (s *DefaultWebStrategy) getConfiguredScrapers() []WebScraper {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -197,30 +417,44 @@ func (s *DefaultWebStrategy) getScraperOrder() []WebScraper {
 	return []WebScraper{s.config.DefaultScraper}
 }
 
+// strategyAdapter is an adapter instance cached by a strategy, together with the registry
+// revision of the factory that created it.
+type strategyAdapter struct {
+	adapter  WebAdapter
+	revision uint64
+}
+
 // getOrCreateAdapter gets or creates an adapter for the given scraper.
+// A cached adapter is replaced when the scraper was registered anew since it was created.
 func (s *DefaultWebStrategy) getOrCreateAdapter(scraper WebScraper) (WebAdapter, error) {
+	if !IsWebAdapterEnabled(scraper) {
+		return nil, fmt.Errorf("%w: %s", ErrScraperDisabled, scraper)
+	}
+	revision := globalRegistry.Revision(scraper)
+
 	s.mu.RLock()
-	adapter, exists := s.adapters[scraper]
+	cached, exists := s.adapters[scraper]
 	s.mu.RUnlock()
 
-	if exists {
-		return adapter, nil
+	if exists && cached.revision == revision {
+		return cached.adapter, nil
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// Double check
-	adapter, exists = s.adapters[scraper]
-	if exists {
-		return adapter, nil
+	cached, exists = s.adapters[scraper]
+	if exists && cached.revision == revision {
+		return cached.adapter, nil
 	}
 
-	newAdapter, err := CreateWebAdapter(scraper)
+	newAdapter, revision, err := globalRegistry.create(scraper)
 	if err != nil {
+		delete(s.adapters, scraper)
 		return nil, err
 	}
 
-	s.adapters[scraper] = newAdapter
+	s.adapters[scraper] = strategyAdapter{adapter: newAdapter, revision: revision}
 	return newAdapter, nil
 }
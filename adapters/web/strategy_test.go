@@ -0,0 +1,347 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/config/types"
+	"github.com/anboat/strato-sdk/pkg/resilience"
+)
+
+// registerFakeAdapters registers the fake adapters in the global registry under names unique to
+// the test, and returns the names in the order of the adapters.
+func registerFakeAdapters(t *testing.T, adapters ...*fakeAdapter) []WebScraper {
+	t.Helper()
+	scrapers := make([]WebScraper, len(adapters))
+	for i, adapter := range adapters {
+		scraper := WebScraper(t.Name() + "/" + string(rune('a'+i)))
+		RegisterWebAdapter(scraper, func() (WebAdapter, error) { return adapter, nil })
+		t.Cleanup(func() { UnregisterWebAdapter(scraper) })
+		scrapers[i] = scraper
+	}
+	return scrapers
+}
+
+// newTestStrategy creates a strategy that falls back across the scrapers without retries.
+func newTestStrategy(scrapers []WebScraper) *DefaultWebStrategy {
+	return NewDefaultWebStrategy(&WebStrategyConfig{
+		DefaultScraper:       scrapers[0],
+		DefaultFallbackOrder: scrapers,
+		EnableFallback:       true,
+		FailFast:             true,
+	})
+}
+
+func TestExecuteFallsBack(t *testing.T) {
+	failing := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		return nil, &resilience.StatusError{StatusCode: http.StatusBadGateway, Message: "bad gateway"}
+	})
+	working := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, failing, working)
+
+	content, err := newTestStrategy(scrapers).Execute(context.Background(), "https://example.com/page", nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if content.Scraper != scrapers[1] {
+		t.Errorf("Scraper = %q, want %q", content.Scraper, scrapers[1])
+	}
+	if content.Links == nil || content.Images == nil {
+		t.Error("Execute() left nil links or images")
+	}
+	if len(failing.called()) != 1 || len(working.called()) != 1 {
+		t.Errorf("calls = %v and %v, want one each", failing.called(), working.called())
+	}
+}
+
+func TestExecuteAllFail(t *testing.T) {
+	failure := &resilience.StatusError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+	failing := func(ctx context.Context, url string) (*WebContent, error) { return nil, failure }
+	scrapers := registerFakeAdapters(t, newFakeAdapter(failing), newFakeAdapter(failing))
+
+	_, err := newTestStrategy(scrapers).Execute(context.Background(), "https://example.com/page", nil)
+	if !errors.Is(err, failure) {
+		t.Errorf("Execute() error = %v, want %v", err, failure)
+	}
+}
+
+func TestExecuteBatchPassesOnOnlyFailedPages(t *testing.T) {
+	const (
+		okURL         = "https://one.example/ok"
+		failedURL     = "https://two.example/failed"
+		emptyURL      = "https://three.example/empty"
+		disallowedURL = "https://four.example/private"
+		okTooURL      = "https://one.example/ok-too"
+	)
+	urls := []string{okURL, failedURL, emptyURL, disallowedURL, okTooURL}
+
+	first := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		switch url {
+		case failedURL:
+			return nil, &resilience.StatusError{StatusCode: http.StatusForbidden, Message: "forbidden"}
+		case emptyURL:
+			return &WebContent{URL: url}, nil
+		case disallowedURL:
+			return nil, &DisallowedError{URL: url, UserAgent: DefaultRobotsUserAgent}
+		}
+		return pageContent(ctx, url)
+	})
+	second := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, first, second)
+
+	results := newTestStrategy(scrapers).ExecuteBatch(context.Background(), urls, nil)
+	if len(results) != len(urls) {
+		t.Fatalf("got %d results, want %d", len(results), len(urls))
+	}
+
+	passedOn := second.called()
+	slices.Sort(passedOn)
+	if want := []string{emptyURL, failedURL}; !slices.Equal(passedOn, want) {
+		t.Errorf("pages passed on to the second scraper = %v, want %v", passedOn, want)
+	}
+
+	wantScrapers := []WebScraper{scrapers[0], scrapers[1], scrapers[1], scrapers[0], scrapers[0]}
+	for i, result := range results {
+		if result.URL != urls[i] {
+			t.Errorf("results[%d].URL = %q, want %q", i, result.URL, urls[i])
+		}
+		if result.Scraper != wantScrapers[i] {
+			t.Errorf("results[%d].Scraper = %q, want %q", i, result.Scraper, wantScrapers[i])
+		}
+		if i == 3 {
+			continue
+		}
+		if !result.OK() || result.Content.Scraper != wantScrapers[i] {
+			t.Errorf("results[%d] = %+v, want the page scraped by %s", i, result, wantScrapers[i])
+		}
+	}
+	if err := results[3].Err; results[3].OK() || err == nil || err.Kind != ScrapeErrorDisallowed {
+		t.Errorf("results[3] = %+v, want a disallowed error", results[3])
+	}
+}
+
+func TestExecuteBatchWithoutFallback(t *testing.T) {
+	failing := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		if strings.HasSuffix(url, "/failed") {
+			return nil, errors.New("failed")
+		}
+		return pageContent(ctx, url)
+	})
+	second := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, failing, second)
+
+	strategy := newTestStrategy(scrapers)
+	strategy.config.EnableFallback = false
+	results := strategy.ExecuteBatch(context.Background(), []string{"https://example.com/ok", "https://example.com/failed"}, nil)
+
+	if !results[0].OK() || results[1].OK() {
+		t.Errorf("results = %+v, %+v, want the first page only", results[0], results[1])
+	}
+	if calls := second.called(); len(calls) != 0 {
+		t.Errorf("second scraper called for %v with fallback disabled", calls)
+	}
+}
+
+func TestExecuteBatchRetriesTransientFailures(t *testing.T) {
+	attempts := make(map[string]int)
+	flaky := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		attempts[url]++
+		if attempts[url] == 1 {
+			return nil, &resilience.StatusError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+		}
+		return pageContent(ctx, url)
+	})
+	scrapers := registerFakeAdapters(t, flaky)
+
+	strategy := newTestStrategy(scrapers)
+	strategy.config.FailFast = false
+	strategy.config.MaxRetries = 1
+
+	results := strategy.ExecuteBatch(context.Background(), []string{"https://example.com/page"}, &ScrapeOptions{})
+	if !results[0].OK() {
+		t.Fatalf("result = %+v, want the page after a retry", results[0])
+	}
+	if attempts["https://example.com/page"] != 2 {
+		t.Errorf("attempts = %d, want 2", attempts["https://example.com/page"])
+	}
+	if health := strategy.Health()[scrapers[0]]; health.Requests != 2 || health.Failures != 1 {
+		t.Errorf("health = %+v, want every attempt recorded", health)
+	}
+}
+
+func TestExecuteMultiple(t *testing.T) {
+	adapter := newFakeAdapter(func(ctx context.Context, url string) (*WebContent, error) {
+		if strings.HasSuffix(url, "/failed") {
+			return nil, errors.New("failed")
+		}
+		return pageContent(ctx, url)
+	})
+	scrapers := registerFakeAdapters(t, adapter)
+	strategy := newTestStrategy(scrapers)
+
+	contents, err := strategy.ExecuteMultiple(context.Background(), []string{"https://example.com/failed", "https://example.com/ok"}, nil)
+	if err != nil || len(contents) != 1 || contents[0].URL != "https://example.com/ok" {
+		t.Errorf("ExecuteMultiple() = %v, %v, want the successful page", contents, err)
+	}
+
+	if _, err := strategy.ExecuteMultiple(context.Background(), []string{"https://example.com/failed"}, nil); err == nil {
+		t.Error("ExecuteMultiple() error = nil, want an error when every page failed")
+	}
+}
+
+// newPolitenessServer serves a robots.txt that disallows /private, and returns the politeness
+// layer that spaces requests to it by the interval.
+func newPolitenessServer(t *testing.T, interval time.Duration) (*httptest.Server, *Politeness) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server, NewPoliteness(&PolitenessConfig{HTTPClient: server.Client(), MinHostInterval: interval})
+}
+
+func TestExecutePolitenessWaitIsNotAnAttempt(t *testing.T) {
+	server, politeness := newPolitenessServer(t, 100*time.Millisecond)
+	adapter := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, adapter)
+
+	// The host interval is longer than the attempt timeout: waiting for it must neither time out
+	// the attempt nor count against the scraper.
+	strategy := newTestStrategy(scrapers)
+	strategy.config.Timeout = 30 * time.Millisecond
+	strategy.config.Politeness = politeness
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := strategy.Execute(context.Background(), server.URL+"/page", nil); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("3 scrapes took %v, want them spaced by the 100ms host interval", elapsed)
+	}
+
+	health := strategy.Health()[scrapers[0]]
+	if health.Failures != 0 || health.Requests != 3 {
+		t.Errorf("health = %+v, want 3 successful requests", health)
+	}
+	if health.AvgLatency > 30*time.Millisecond {
+		t.Errorf("average latency = %v, want it to exclude the politeness wait", health.AvgLatency)
+	}
+
+	var disallowed *DisallowedError
+	if _, err := strategy.Execute(context.Background(), server.URL+"/private", nil); !errors.As(err, &disallowed) {
+		t.Errorf("Execute() on a disallowed page error = %v, want a *DisallowedError", err)
+	}
+	if calls := adapter.called(); len(calls) != 3 {
+		t.Errorf("adapter called for %v, want the disallowed page skipped", calls)
+	}
+}
+
+func TestExecuteRateLimitWaitIsNotAnAttempt(t *testing.T) {
+	adapter := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, adapter)
+
+	webConfig := config.GetWebConfig()
+	if webConfig.Scrapers == nil {
+		webConfig.Scrapers = make(map[string]types.WebScraperConfig)
+	}
+	webConfig.Scrapers[string(scrapers[0])] = types.WebScraperConfig{
+		RateLimit: types.RateLimitConfig{Enabled: true, RequestsPerSecond: 10, BurstSize: 1},
+	}
+	t.Cleanup(func() { delete(webConfig.Scrapers, string(scrapers[0])) })
+
+	// The rate limit allows a request every 100ms, longer than the attempt timeout: waiting for
+	// it must neither time out the attempt nor count against the scraper.
+	strategy := newTestStrategy(scrapers)
+	strategy.config.Timeout = 30 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := strategy.Execute(context.Background(), "https://example.com/page", nil); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("3 scrapes took %v, want them spaced by the rate limit", elapsed)
+	}
+
+	health := strategy.Health()[scrapers[0]]
+	if health.Failures != 0 || health.Requests != 3 || health.AvgLatency > 30*time.Millisecond {
+		t.Errorf("health = %+v, want 3 fast successful requests", health)
+	}
+}
+
+func TestExecuteHedgedScrapersWaitPolitely(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	server, politeness := newPolitenessServer(t, interval)
+
+	slow := newFakeAdapter(pageContent)
+	slow.delay = 3 * interval
+	fast := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, slow, fast)
+
+	strategy := newTestStrategy(scrapers)
+	strategy.config.Hedge = resilience.HedgeConfig{Enabled: true, Delay: 10 * time.Millisecond}
+	strategy.config.Politeness = politeness
+
+	start := time.Now()
+	content, err := strategy.Execute(context.Background(), server.URL+"/page", nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if content.Scraper != scrapers[1] {
+		t.Errorf("Scraper = %q, want the hedged scraper %q", content.Scraper, scrapers[1])
+	}
+	// The hedged scraper starts after the hedge delay and then waits for a host slot of its own.
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Errorf("hedged scrape took %v, want it to wait for the %v host interval", elapsed, interval)
+	}
+
+	host := strings.ToLower(strings.TrimPrefix(server.URL, "http://"))
+	politeness.mu.Lock()
+	next := politeness.next[host]
+	politeness.mu.Unlock()
+	if reserved := next.Sub(start); reserved < 2*interval {
+		t.Errorf("next slot of the host %v after the start, want a slot reserved by each scraper", reserved)
+	}
+}
+
+func TestExecuteBatchWaitsPolitely(t *testing.T) {
+	server, politeness := newPolitenessServer(t, 50*time.Millisecond)
+	adapter := newFakeAdapter(pageContent)
+	scrapers := registerFakeAdapters(t, adapter)
+
+	strategy := newTestStrategy(scrapers)
+	strategy.config.Timeout = 20 * time.Millisecond
+	strategy.config.Politeness = politeness
+
+	urls := []string{server.URL + "/1", server.URL + "/private", server.URL + "/2", server.URL + "/3"}
+	start := time.Now()
+	results := strategy.ExecuteBatch(context.Background(), urls, nil)
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 scrapes of one host took %v, want them spaced by the 50ms host interval", elapsed)
+	}
+
+	for i, result := range results {
+		if i == 1 {
+			if result.Err == nil || result.Err.Kind != ScrapeErrorDisallowed {
+				t.Errorf("results[1] = %+v, want a disallowed error", result)
+			}
+			continue
+		}
+		if !result.OK() {
+			t.Errorf("results[%d] error = %v, want the page", i, result.Err)
+		}
+	}
+	if health := strategy.Health()[scrapers[0]]; health.Failures != 0 || health.Requests != 3 {
+		t.Errorf("health = %+v, want 3 successful requests", health)
+	}
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+// Components whose engine-specific configuration is decoded with DecodeAdapterConfig.
+const (
+	ComponentSearchEngine = "search engine"
+	ComponentWebScraper   = "web scraper"
+)
+
+var (
+	// ErrUnknownConfigKey is reported for a key the adapter does not know, e.g. a misspelt key.
+	ErrUnknownConfigKey = errors.New("unknown key")
+
+	// ErrMissingConfigValue is reported for a required value that is not set.
+	ErrMissingConfigValue = errors.New("required value is not set")
+)
+
+// ConfigError is an invalid value in the configuration of a search engine or web scraper.
+type ConfigError struct {
+	// Component is the kind of adapter, e.g. ComponentSearchEngine.
+	Component string
+	// Name is the name of the engine or scraper.
+	Name string
+	// Key is the configuration key, empty if the error is not about a single key.
+	Key string
+	// Err describes what is wrong with the value.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ConfigError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s %q: %v", e.Component, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s %q: config key %q: %v", e.Component, e.Name, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidConfigValue returns an error for an invalid value of a configuration key. It is meant to be
+// returned from the Validate method of an adapter configuration; DecodeAdapterConfig adds the adapter.
+//
+// Parameters:
+//   - key: The configuration key.
+//   - format: The format of the description of the problem, followed by its arguments.
+//
+// Returns:
+//   - error: A *ConfigError for the key.
+func InvalidConfigValue(key string, format string, args ...interface{}) error {
+	return &ConfigError{Key: key, Err: fmt.Errorf(format, args...)}
+}
+
+// ConfigValidator is implemented by adapter configurations that validate their values after decoding.
+type ConfigValidator interface {
+	Validate() error
+}
+
+// durationType is the reflect type of time.Duration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// DecodeAdapterConfig decodes the engine-specific `config` section of a search engine or web scraper
+// into a typed configuration struct. The struct names its keys with `mapstructure` tags and holds
+// the default values on input; keys marked `required:"true"` must be set. Durations accept a number
+// of seconds or a duration string such as "1m30s". Unknown keys and values of the wrong type are
+// reported, as are the errors of the Validate method if the struct implements ConfigValidator.
+//
+// Parameters:
+//   - component: The kind of adapter, ComponentSearchEngine or ComponentWebScraper.
+//   - name: The name of the engine or scraper.
+//   - raw: The `config` section; nil keeps the defaults.
+//   - target: A pointer to the configuration struct.
+//
+// Returns:
+//   - error: The *ConfigError of every invalid key, joined, or nil if the configuration is valid.
+func DecodeAdapterConfig(component, name string, raw map[string]interface{}, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%s %q: config target must be a pointer to a struct, got %T", component, name, target)
+	}
+	fields := configFields(value.Elem().Type())
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		field, exists := fields[strings.ToLower(key)]
+		if !exists {
+			errs = append(errs, &ConfigError{Component: component, Name: name, Key: key, Err: ErrUnknownConfigKey})
+			continue
+		}
+		seen[field.key] = true
+
+		fieldValue := value.Elem().FieldByIndex(field.index)
+		if err := decodeConfigValue(raw[key], fieldValue.Addr().Interface()); err != nil {
+			errs = append(errs, &ConfigError{
+				Component: component,
+				Name:      name,
+				Key:       field.key,
+				Err:       fmt.Errorf("expected %s, got %v (%T)", configTypeName(fieldValue.Type()), raw[key], raw[key]),
+			})
+		}
+	}
+
+	for _, field := range sortedConfigFields(fields) {
+		if field.required && !seen[field.key] {
+			errs = append(errs, &ConfigError{Component: component, Name: name, Key: field.key, Err: ErrMissingConfigValue})
+		}
+	}
+
+	if len(errs) == 0 {
+		if validator, ok := target.(ConfigValidator); ok {
+			if err := validator.Validate(); err != nil {
+				errs = append(errs, withAdapter(component, name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConfigSchema describes the keys of a configuration struct as accepted by DecodeAdapterConfig.
+// The defaults are taken from the values of the struct and the descriptions from `description` tags.
+//
+// Parameters:
+//   - defaults: The configuration struct, or a pointer to it, holding the default values.
+//
+// Returns:
+//   - []types.ConfigField: The keys of the configuration, in declaration order.
+func ConfigSchema(defaults interface{}) []types.ConfigField {
+	value := reflect.Indirect(reflect.ValueOf(defaults))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := sortedConfigFields(configFields(value.Type()))
+	schema := make([]types.ConfigField, 0, len(fields))
+	for _, field := range fields {
+		fieldValue := value.FieldByIndex(field.index)
+		schema = append(schema, types.ConfigField{
+			Key:         field.key,
+			Type:        configTypeName(fieldValue.Type()),
+			Default:     configDefault(fieldValue),
+			Required:    field.required,
+			Description: field.description,
+		})
+	}
+	return schema
+}
+
+// configField is a key of a configuration struct.
+type configField struct {
+	key         string
+	index       []int
+	required    bool
+	description string
+}
+
+// configFields returns the keys of a configuration struct, by lower-case key.
+func configFields(structType reflect.Type) map[string]configField {
+	fields := make(map[string]configField)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		key := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		fields[strings.ToLower(key)] = configField{
+			key:         key,
+			index:       field.Index,
+			required:    field.Tag.Get("required") == "true",
+			description: field.Tag.Get("description"),
+		}
+	}
+	return fields
+}
+
+// sortedConfigFields returns the fields in declaration order.
+func sortedConfigFields(fields map[string]configField) []configField {
+	sorted := make([]configField, 0, len(fields))
+	for _, field := range fields {
+		sorted = append(sorted, field)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].index[0] < sorted[j].index[0] })
+	return sorted
+}
+
+// decodeConfigValue decodes a single configuration value without weak type conversion,
+// so that e.g. a string is not silently accepted for a number.
+func decodeConfigValue(input interface{}, output interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: secondsToDurationHook,
+		Result:     output,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
+}
+
+// secondsToDurationHook decodes a number of seconds or a duration string into a time.Duration.
+func secondsToDurationHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != durationType {
+		return data, nil
+	}
+
+	value := reflect.ValueOf(data)
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(value.Int()) * time.Second, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(value.Uint()) * time.Second, nil
+	case reflect.Float32, reflect.Float64:
+		return time.Duration(value.Float() * float64(time.Second)), nil
+	case reflect.String:
+		return time.ParseDuration(value.String())
+	default:
+		return data, nil
+	}
+}
+
+// configTypeName returns the name of a configuration value type as shown to users.
+func configTypeName(valueType reflect.Type) string {
+	if valueType == durationType {
+		return "duration"
+	}
+	switch valueType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice:
+		return "[]" + configTypeName(valueType.Elem())
+	case reflect.Map:
+		return "map"
+	default:
+		return valueType.Kind().String()
+	}
+}
+
+// configDefault formats the default value of a configuration key, empty for a zero value.
+func configDefault(value reflect.Value) string {
+	if value.IsZero() {
+		return ""
+	}
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// withAdapter adds the adapter to the errors returned by the Validate method of a configuration.
+func withAdapter(component, name string, err error) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		wrapped := make([]error, 0, len(errs))
+		for _, e := range errs {
+			wrapped = append(wrapped, withAdapter(component, name, e))
+		}
+		return errors.Join(wrapped...)
+	}
+
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		configErr.Component = component
+		configErr.Name = name
+		return configErr
+	}
+	return &ConfigError{Component: component, Name: name, Err: err}
+}
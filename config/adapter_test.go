@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testAdapterConfig is a configuration struct as declared by adapters.
+type testAdapterConfig struct {
+	APIKey     string        `mapstructure:"api_key" required:"true"`
+	MaxResults int           `mapstructure:"max_results"`
+	Ratio      float64       `mapstructure:"ratio"`
+	Enabled    bool          `mapstructure:"enabled"`
+	Tags       []string      `mapstructure:"tags"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+}
+
+// Validate implements ConfigValidator.
+func (c *testAdapterConfig) Validate() error {
+	if c.MaxResults < 0 {
+		return InvalidConfigValue("max_results", "must not be negative, got %d", c.MaxResults)
+	}
+	return nil
+}
+
+func TestDecodeAdapterConfig(t *testing.T) {
+	raw := map[string]interface{}{
+		"api_key":     "secret",
+		"MAX_RESULTS": 20,
+		"ratio":       0.5,
+		"enabled":     true,
+		"tags":        []interface{}{"a", "b"},
+	}
+	cfg := testAdapterConfig{Timeout: 30 * time.Second}
+	if err := DecodeAdapterConfig(ComponentSearchEngine, "test", raw, &cfg); err != nil {
+		t.Fatalf("DecodeAdapterConfig() error = %v", err)
+	}
+
+	if cfg.APIKey != "secret" || cfg.MaxResults != 20 || cfg.Ratio != 0.5 || !cfg.Enabled {
+		t.Errorf("decoded config = %+v", cfg)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a" || cfg.Tags[1] != "b" {
+		t.Errorf("Tags = %v, want [a b]", cfg.Tags)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want the default of 30s", cfg.Timeout)
+	}
+}
+
+func TestDecodeAdapterConfigDurations(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  time.Duration
+	}{
+		{name: "seconds", value: 45, want: 45 * time.Second},
+		{name: "fractional seconds", value: 1.5, want: 1500 * time.Millisecond},
+		{name: "duration string", value: "1m30s", want: 90 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testAdapterConfig{}
+			raw := map[string]interface{}{"api_key": "secret", "timeout": tt.value}
+			if err := DecodeAdapterConfig(ComponentWebScraper, "test", raw, &cfg); err != nil {
+				t.Fatalf("DecodeAdapterConfig() error = %v", err)
+			}
+			if cfg.Timeout != tt.want {
+				t.Errorf("Timeout = %v, want %v", cfg.Timeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeAdapterConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantKey string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "unknown key",
+			raw:     map[string]interface{}{"api_key": "secret", "format": "json"},
+			wantKey: "format",
+			wantErr: ErrUnknownConfigKey,
+		},
+		{
+			name:    "missing required key",
+			raw:     map[string]interface{}{"max_results": 10},
+			wantKey: "api_key",
+			wantErr: ErrMissingConfigValue,
+		},
+		{
+			name:    "string for int",
+			raw:     map[string]interface{}{"api_key": "secret", "max_results": "10"},
+			wantKey: "max_results",
+			wantMsg: "expected int",
+		},
+		{
+			name:    "int for bool",
+			raw:     map[string]interface{}{"api_key": "secret", "enabled": 1},
+			wantKey: "enabled",
+			wantMsg: "expected bool",
+		},
+		{
+			name:    "invalid duration string",
+			raw:     map[string]interface{}{"api_key": "secret", "timeout": "soon"},
+			wantKey: "timeout",
+			wantMsg: "expected duration",
+		},
+		{
+			name:    "validation error",
+			raw:     map[string]interface{}{"api_key": "secret", "max_results": -1},
+			wantKey: "max_results",
+			wantMsg: "must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testAdapterConfig{}
+			err := DecodeAdapterConfig(ComponentSearchEngine, "test", tt.raw, &cfg)
+			if err == nil {
+				t.Fatal("DecodeAdapterConfig() error = nil, want an error")
+			}
+
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("error %v is not a *ConfigError", err)
+			}
+			if configErr.Component != ComponentSearchEngine || configErr.Name != "test" {
+				t.Errorf("error adapter = %s %q, want %s %q", configErr.Component, configErr.Name, ComponentSearchEngine, "test")
+			}
+			if configErr.Key != tt.wantKey {
+				t.Errorf("error key = %q, want %q", configErr.Key, tt.wantKey)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %v does not wrap %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error %q does not contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestDecodeAdapterConfigReportsEveryKey(t *testing.T) {
+	raw := map[string]interface{}{"format": "json", "ratio": "high"}
+	err := DecodeAdapterConfig(ComponentSearchEngine, "test", raw, &testAdapterConfig{})
+	if err == nil {
+		t.Fatal("DecodeAdapterConfig() error = nil, want an error")
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("error %v is not joined", err)
+	}
+	var keys []string
+	for _, e := range joined.Unwrap() {
+		var configErr *ConfigError
+		if errors.As(e, &configErr) {
+			keys = append(keys, configErr.Key)
+		}
+	}
+	want := []string{"format", "ratio", "api_key"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("error keys = %v, want %v", keys, want)
+	}
+}
+
+func TestDecodeAdapterConfigTarget(t *testing.T) {
+	if err := DecodeAdapterConfig(ComponentSearchEngine, "test", nil, testAdapterConfig{}); err == nil {
+		t.Error("DecodeAdapterConfig() with a struct value, error = nil, want an error")
+	}
+}
@@ -88,6 +88,11 @@ func GetResearchConfig() *types.ResearchConfig {
 	return &config.Agent.Research
 }
 
+// GetCanonicalizationConfig returns the URL canonicalization configuration.
+func GetCanonicalizationConfig() *types.CanonicalizationConfig {
+	return &config.Canonicalization
+}
+
 // UpdateConfig updates the global configuration with a new config object.
 func UpdateConfig(newConfig *types.Config) {
 	config = *newConfig
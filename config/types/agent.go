@@ -25,4 +25,23 @@ type ResearchConfig struct {
 
 	// Channel buffer size.
 	ChannelBuffer int `json:"channel_buffer" yaml:"channel_buffer" mapstructure:"channel_buffer"`
+
+	// Default recency preference of a research run: day, week, month or year (empty means no preference).
+	Recency string `json:"recency" yaml:"recency" mapstructure:"recency"`
+
+	// Additional languages every sub-question is searched in (e.g., en, zh-CN); empty disables cross-lingual search.
+	SearchLanguages []string `json:"search_languages" yaml:"search_languages" mapstructure:"search_languages"`
+
+	// Whether the claims of each analysis are cross-checked against its sources; adds one model call per sub-question.
+	CorroborateClaims bool `json:"corroborate_claims" yaml:"corroborate_claims" mapstructure:"corroborate_claims"`
+
+	// Minimum number of supporting sources a claim needs to avoid being flagged as single-source.
+	MinCorroboratingSources int `json:"min_corroborating_sources" yaml:"min_corroborating_sources" mapstructure:"min_corroborating_sources"`
+
+	// Whether each generated research plan waits for approval before it is researched.
+	// Only runs started with StartResearch wait, since only they can approve plans.
+	RequirePlanApproval bool `json:"require_plan_approval" yaml:"require_plan_approval" mapstructure:"require_plan_approval"`
+
+	// Seconds after which a pending research plan is auto-approved (0 waits until the run is cancelled).
+	PlanApprovalTimeout int `json:"plan_approval_timeout" yaml:"plan_approval_timeout" mapstructure:"plan_approval_timeout"`
 }
@@ -0,0 +1,30 @@
+package types
+
+// CanonicalizationConfig holds the URL canonicalization rules that decide which URLs search
+// deduplication, web scraping and source citations treat as the same page. The URLs themselves
+// are kept as the engines returned them. By default every normalization is applied.
+type CanonicalizationConfig struct {
+	// Treat http and https URLs as different pages.
+	KeepScheme bool `json:"keep_scheme" yaml:"keep_scheme" mapstructure:"keep_scheme"`
+
+	// Keep the "www." prefix of host names.
+	KeepWWW bool `json:"keep_www" yaml:"keep_www" mapstructure:"keep_www"`
+
+	// Keep URL fragments (#section).
+	KeepFragment bool `json:"keep_fragment" yaml:"keep_fragment" mapstructure:"keep_fragment"`
+
+	// Keep trailing slashes of paths.
+	KeepTrailingSlash bool `json:"keep_trailing_slash" yaml:"keep_trailing_slash" mapstructure:"keep_trailing_slash"`
+
+	// Keep AMP variants of pages instead of mapping them to the regular page.
+	KeepAMP bool `json:"keep_amp" yaml:"keep_amp" mapstructure:"keep_amp"`
+
+	// Keep mobile hosts (m., mobile.) instead of mapping them to the regular host.
+	KeepMobile bool `json:"keep_mobile" yaml:"keep_mobile" mapstructure:"keep_mobile"`
+
+	// Additional tracking query parameters to strip; a trailing "*" matches a prefix (e.g., "pk_*").
+	StripParams []string `json:"strip_params" yaml:"strip_params" mapstructure:"strip_params"`
+
+	// Query parameters that are never stripped, even if they match a tracking rule.
+	KeepParams []string `json:"keep_params" yaml:"keep_params" mapstructure:"keep_params"`
+}
@@ -20,4 +20,7 @@ type Config struct {
 
 	// Agent configuration.
 	Agent AgentConfig `json:"agent" yaml:"agent" mapstructure:"agent"`
+
+	// URL canonicalization configuration.
+	Canonicalization CanonicalizationConfig `json:"canonicalization" yaml:"canonicalization" mapstructure:"canonicalization"`
 }
@@ -0,0 +1,19 @@
+package types
+
+// ConfigField describes a key of the engine-specific `config` section of a search engine or web scraper.
+type ConfigField struct {
+	// Key is the name of the key in the `config` section.
+	Key string `json:"key"`
+
+	// Type is the type of the value, e.g. string, int, bool or duration.
+	Type string `json:"type"`
+
+	// Default is the value used when the key is not set, empty if there is none.
+	Default string `json:"default,omitempty"`
+
+	// Required reports whether the key must be set.
+	Required bool `json:"required,omitempty"`
+
+	// Description explains what the key configures.
+	Description string `json:"description,omitempty"`
+}
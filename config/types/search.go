@@ -7,6 +7,67 @@ type SearchConfig struct {
 
 	// Specific configurations for each search engine.
 	Engines map[string]EngineConfig `json:"engines" yaml:"engines" mapstructure:"engines"`
+
+	// Search result cache configuration.
+	Cache SearchCacheConfig `json:"cache" yaml:"cache" mapstructure:"cache"`
+
+	// Intent-based engine routing configuration.
+	Routing SearchRoutingConfig `json:"routing" yaml:"routing" mapstructure:"routing"`
+}
+
+// SearchRoutingConfig holds the configuration for routing queries to engines by intent.
+type SearchRoutingConfig struct {
+	// Whether to classify queries and route them to the engines of their class.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// Query classifier: keyword (keyword rules, default) or llm (a language model call).
+	Classifier string `json:"classifier" yaml:"classifier" mapstructure:"classifier"`
+
+	// Model used by the llm classifier (default model if empty).
+	Model string `json:"model" yaml:"model" mapstructure:"model"`
+
+	// Routes per query class; classes without a route use the default strategy.
+	Routes []SearchRouteConfig `json:"routes" yaml:"routes" mapstructure:"routes"`
+}
+
+// SearchRouteConfig holds the engines and parameters used for a query class.
+type SearchRouteConfig struct {
+	// Query class: news, social, academic, code or general.
+	Class string `json:"class" yaml:"class" mapstructure:"class"`
+
+	// Engines used for the class, in fallback order.
+	Engines []string `json:"engines" yaml:"engines" mapstructure:"engines"`
+
+	// Whether to search the engines concurrently and fuse their results.
+	Mixed bool `json:"mixed" yaml:"mixed" mapstructure:"mixed"`
+
+	// Engine parameters added to the requests of the class, e.g., SearXNG categories.
+	EngineParams map[string]interface{} `json:"engine_params" yaml:"engine_params" mapstructure:"engine_params"`
+
+	// Extra keywords of the class for the keyword classifier.
+	Keywords []string `json:"keywords" yaml:"keywords" mapstructure:"keywords"`
+}
+
+// SearchCacheConfig holds the configuration for the search result cache.
+type SearchCacheConfig struct {
+	// Whether to cache search responses.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// Cache backend: memory (in-process LRU, default) or disk (shared across runs).
+	Backend string `json:"backend" yaml:"backend" mapstructure:"backend"`
+
+	// How long responses are cached, in seconds (default 3600).
+	TTLSeconds int `json:"ttl_seconds" yaml:"ttl_seconds" mapstructure:"ttl_seconds"`
+
+	// TTL overrides for requests with a time range (day, week, month, year), in seconds.
+	TimeRangeTTLSeconds map[string]int `json:"time_range_ttl_seconds" yaml:"time_range_ttl_seconds" mapstructure:"time_range_ttl_seconds"`
+
+	// Maximum number of responses held by the cache (default 1000 for the memory backend,
+	// 10000 for the disk backend, which also removes expired files periodically).
+	MaxEntries int `json:"max_entries" yaml:"max_entries" mapstructure:"max_entries"`
+
+	// Directory of the disk backend (default .cache/search).
+	Directory string `json:"directory" yaml:"directory" mapstructure:"directory"`
 }
 
 // SearchStrategyConfig holds the configuration for the search strategy.
@@ -34,6 +95,50 @@ type SearchStrategyConfig struct {
 
 	// Timeout in seconds.
 	TimeoutSeconds int `json:"timeout_seconds" yaml:"timeout_seconds" mapstructure:"timeout_seconds"`
+
+	// Circuit breaker configuration.
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker" yaml:"circuit_breaker" mapstructure:"circuit_breaker"`
+
+	// Hedged request configuration.
+	Hedge HedgeConfig `json:"hedge" yaml:"hedge" mapstructure:"hedge"`
+
+	// Only keep results from these domains and their subdomains (empty means all domains are allowed).
+	DomainAllowlist []string `json:"domain_allowlist" yaml:"domain_allowlist" mapstructure:"domain_allowlist"`
+
+	// Drop results from these domains and their subdomains.
+	DomainDenylist []string `json:"domain_denylist" yaml:"domain_denylist" mapstructure:"domain_denylist"`
+
+	// Credibility weights per domain or domain suffix.
+	DomainWeights []DomainWeightConfig `json:"domain_weights" yaml:"domain_weights" mapstructure:"domain_weights"`
+
+	// Result merger for mixed search: rrf (reciprocal rank fusion, default) or score (normalized score sum).
+	Merger string `json:"merger" yaml:"merger" mapstructure:"merger"`
+
+	// Rank constant of reciprocal rank fusion (default 60).
+	RRFK int `json:"rrf_k" yaml:"rrf_k" mapstructure:"rrf_k"`
+
+	// Whether to request further pages from engines that support pagination until the requested number of results is collected.
+	AutoPaginate bool `json:"auto_paginate" yaml:"auto_paginate" mapstructure:"auto_paginate"`
+
+	// Maximum number of pages requested from an engine per search (default 5).
+	MaxPages int `json:"max_pages" yaml:"max_pages" mapstructure:"max_pages"`
+
+	// Mixed search returns once this many unique results have arrived (0 waits for all engines).
+	MinResults int `json:"min_results" yaml:"min_results" mapstructure:"min_results"`
+
+	// Mixed search returns with the results that have arrived after this many seconds (0 waits for all engines).
+	SoftDeadlineSeconds int `json:"soft_deadline_seconds" yaml:"soft_deadline_seconds" mapstructure:"soft_deadline_seconds"`
+}
+
+// DomainWeightConfig holds the credibility weight of a domain.
+// It is a list entry rather than a map key because domain names contain dots,
+// which the configuration loader treats as key separators.
+type DomainWeightConfig struct {
+	// Domain or domain suffix, e.g., "gov" or "arxiv.org".
+	Domain string `json:"domain" yaml:"domain" mapstructure:"domain"`
+
+	// Weight applied to results from the domain; 1.0 is neutral.
+	Weight float64 `json:"weight" yaml:"weight" mapstructure:"weight"`
 }
 
 // EngineConfig holds the configuration for a single search engine.
@@ -53,6 +158,39 @@ type EngineConfig struct {
 	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit" mapstructure:"rate_limit"`
 }
 
+// CircuitBreakerConfig holds the circuit breaker configuration of a search or web strategy.
+type CircuitBreakerConfig struct {
+	// Whether failing engines are skipped; health is tracked either way.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// Number of consecutive failures that opens the breaker (default 5).
+	FailureThreshold int `json:"failure_threshold" yaml:"failure_threshold" mapstructure:"failure_threshold"`
+
+	// Failure rate (0-1) over the recent requests that opens the breaker (0 disables the check).
+	ErrorRateThreshold float64 `json:"error_rate_threshold" yaml:"error_rate_threshold" mapstructure:"error_rate_threshold"`
+
+	// Minimum number of recent requests before the failure rate is considered (default 10).
+	MinRequests int `json:"min_requests" yaml:"min_requests" mapstructure:"min_requests"`
+
+	// Number of recent requests the failure rate is computed over (default 20).
+	WindowSize int `json:"window_size" yaml:"window_size" mapstructure:"window_size"`
+
+	// Seconds the breaker stays open before a probe request is let through (default 30).
+	CooldownSeconds int `json:"cooldown_seconds" yaml:"cooldown_seconds" mapstructure:"cooldown_seconds"`
+}
+
+// HedgeConfig holds the hedged request configuration of a search or web strategy.
+type HedgeConfig struct {
+	// Whether to start the next engine in the fallback order in parallel when the current one is slow.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// Milliseconds an engine may take before the next one is started (default 2000).
+	DelayMs int `json:"delay_ms" yaml:"delay_ms" mapstructure:"delay_ms"`
+
+	// Whether to use the p90 latency of the engine as the delay once enough requests have been observed.
+	Adaptive bool `json:"adaptive" yaml:"adaptive" mapstructure:"adaptive"`
+}
+
 // RateLimitConfig holds the rate limiting configuration.
 type RateLimitConfig struct {
 	// Whether to enable rate limiting.
@@ -8,6 +8,31 @@ type WebConfig struct {
 
 	// Scrapers is a map of web scraper configurations.
 	Scrapers map[string]WebScraperConfig `json:"scrapers" yaml:"scrapers" mapstructure:"scrapers"`
+
+	// Politeness is the robots.txt and per-host request interval configuration shared by all scrapers.
+	Politeness PolitenessConfig `json:"politeness" yaml:"politeness" mapstructure:"politeness"`
+}
+
+// PolitenessConfig holds the configuration for respecting robots.txt and spacing out requests to each host.
+type PolitenessConfig struct {
+	// Enabled determines whether pages are checked against robots.txt and requests to a host are spaced out.
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// UserAgent is the user agent checked against robots.txt by its product token. Empty uses "Strato-SDK-Bot".
+	UserAgent string `json:"user_agent" yaml:"user_agent" mapstructure:"user_agent"`
+
+	// MinHostIntervalMs is the minimum time between two requests to the same host in milliseconds.
+	// A longer crawl-delay in the host's robots.txt takes precedence.
+	MinHostIntervalMs int `json:"min_host_interval_ms" yaml:"min_host_interval_ms" mapstructure:"min_host_interval_ms"`
+
+	// MaxCrawlDelaySeconds caps the crawl-delay a site can ask for. Zero uses the default of 30 seconds.
+	MaxCrawlDelaySeconds int `json:"max_crawl_delay_seconds" yaml:"max_crawl_delay_seconds" mapstructure:"max_crawl_delay_seconds"`
+
+	// RobotsCacheTTLSeconds is how long a robots.txt file is cached. Zero uses the default of one hour.
+	RobotsCacheTTLSeconds int `json:"robots_cache_ttl_seconds" yaml:"robots_cache_ttl_seconds" mapstructure:"robots_cache_ttl_seconds"`
+
+	// RobotsCacheSize is the number of hosts whose robots.txt is cached. Zero uses the default of 1000.
+	RobotsCacheSize int `json:"robots_cache_size" yaml:"robots_cache_size" mapstructure:"robots_cache_size"`
 }
 
 // WebStrategyConfig holds the configuration for the web scraping strategy.
@@ -23,6 +48,18 @@ type WebStrategyConfig struct {
 
 	// FailFast determines whether to fall back immediately if a single engine fails.
 	FailFast bool `json:"fail_fast" yaml:"fail_fast" mapstructure:"fail_fast"`
+
+	// MaxRetries is the maximum number of retries on the same scraper for transient errors.
+	MaxRetries int `json:"max_retries" yaml:"max_retries" mapstructure:"max_retries"`
+
+	// TimeoutSeconds is the timeout of each scraping attempt in seconds.
+	TimeoutSeconds int `json:"timeout_seconds" yaml:"timeout_seconds" mapstructure:"timeout_seconds"`
+
+	// CircuitBreaker is the circuit breaker configuration.
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker" yaml:"circuit_breaker" mapstructure:"circuit_breaker"`
+
+	// Hedge is the hedged request configuration.
+	Hedge HedgeConfig `json:"hedge" yaml:"hedge" mapstructure:"hedge"`
 }
 
 // WebScraperConfig holds the configuration for a single web scraper (simplified).
@@ -36,4 +73,20 @@ type WebScraperConfig struct {
 
 	// Scraper-specific parameters.
 	Config map[string]interface{} `json:"config" yaml:"config" mapstructure:"config"`
+
+	// Rate limit configuration.
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit" mapstructure:"rate_limit"`
+
+	// Batch is the concurrency configuration for scraping multiple URLs.
+	Batch BatchConfig `json:"batch" yaml:"batch" mapstructure:"batch"`
+}
+
+// BatchConfig holds the concurrency limits for scraping multiple URLs.
+type BatchConfig struct {
+	// Concurrency is the maximum number of pages scraped at the same time. Zero uses the default of 4.
+	Concurrency int `json:"concurrency" yaml:"concurrency" mapstructure:"concurrency"`
+
+	// PerDomainConcurrency is the maximum number of pages of the same host scraped at the same time.
+	// Zero uses the default of 2.
+	PerDomainConcurrency int `json:"per_domain_concurrency" yaml:"per_domain_concurrency" mapstructure:"per_domain_concurrency"`
 }
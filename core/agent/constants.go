@@ -17,13 +17,19 @@ const (
 	ActionGenerateQuestions    Action = "generate_questions"
 	ActionQuestionSelection    Action = "question_selection"
 	ActionNetworkSearch        Action = "network_search"
+	ActionQueryTranslation     Action = "query_translation"
+	ActionQueryRefinement      Action = "query_refinement"
 	ActionSearchComplete       Action = "search_complete"
 	ActionWebScraping          Action = "web_scraping"
 	ActionSkipScraping         Action = "skip_scraping"
+	ActionReuseSearchContent   Action = "reuse_search_content"
 	ActionScrapingComplete     Action = "scraping_complete"
+	ActionScrapingDisallowed   Action = "scraping_disallowed"
 	ActionContentAnalysis      Action = "content_analysis"
 	ActionRealtimeAnalysis     Action = "realtime_analysis"
 	ActionAnalysisComplete     Action = "analysis_complete"
+	ActionClaimCorroboration   Action = "claim_corroboration"
+	ActionCorroborationDone    Action = "corroboration_complete"
 	ActionSynthesisAnalysis    Action = "synthesis_analysis"
 	ActionRealtimeSynthesis    Action = "realtime_synthesis"
 	ActionIterationIncrement   Action = "iteration_increment"
@@ -36,6 +42,9 @@ const (
 	ActionStepAllocation       Action = "step_allocation"
 	ActionQuestionLimitReached Action = "question_limit_reached"
 	ActionQuestionGenComplete  Action = "question_gen_complete"
+	ActionPlanProposed         Action = "plan_proposed"
+	ActionPlanApproved         Action = "plan_approved"
+	ActionPlanAutoApproved     Action = "plan_auto_approved"
 	ActionResearchComplete     Action = "research_complete"
 	ActionError                Action = "error"
 
@@ -47,10 +56,12 @@ const (
 	// Eino node name constants.
 	NodeStartThinking         = "start_thinking"
 	NodeGenerateQuestions     = "generate_questions"
+	NodeApprovePlan           = "approve_plan"
 	NodeSelectQuestion        = "select_question"
 	NodeSearchQuestion        = "search_question"
 	NodeScrapeWebContent      = "scrape_web_content"
 	NodeAnalyzeQuestion       = "analyze_question"
+	NodeCorroborateClaims     = "corroborate_claims"
 	NodeSynthesizeFinalAnswer = "synthesize_final_answer"
 	NodeIncrementIteration    = "increment_iteration"
 
@@ -61,10 +72,24 @@ const (
 	// Steps required for researching each sub-question: selectQuestion(1) + selectBranch(1) + searchQuestion(1) + scrapeWebContent(1) + analyzeQuestion(1) + checkCompletion(1)
 	StepsPerQuestion = 6
 
+	// Additional steps per sub-question when claim corroboration is enabled: corroborateClaims(1)
+	CorroborationStepsPerQuestion = 1
+
+	// Additional steps per iteration when plan approval is enabled: approvePlan(1)
+	PlanApprovalStepsPerIteration = 1
+
 	// Question ID prefix.
 	QuestionIDPrefix = "q_"
 
 	// Similarity threshold constants.
 	SimilarityThreshold = 0.5 // Threshold for question similarity judgment.
 	MinWordLength       = 2   // Minimum word length.
+
+	// DefaultMinCorroboratingSources is the number of supporting sources a claim needs
+	// before it is no longer flagged as single-source.
+	DefaultMinCorroboratingSources = 2
+
+	// MinUsefulSearchResults is the number of results below which a search is considered poor,
+	// and the query is re-run with the engine's spelling correction or suggestion, if any.
+	MinUsefulSearchResults = 3
 )
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"time"
+
+	"github.com/anboat/strato-sdk/config"
+)
+
+// ResearchOption configures a single research run.
+type ResearchOption func(*researchOptions)
+
+// researchOptions holds the run-level settings of a research run.
+type researchOptions struct {
+	// recency is the recency preference of the run (day, week, month or year).
+	recency string
+
+	// asOf is the date the run treats as today.
+	asOf time.Time
+
+	// searchLanguages are the additional languages every question is searched in.
+	searchLanguages []string
+
+	// corroboration enables cross-checking the claims of each analysis against its sources.
+	corroboration bool
+
+	// planApproval enables the approval gate on each generated research plan.
+	planApproval bool
+
+	// planApprovalTimeout is the time after which a pending plan is auto-approved, zero to wait indefinitely.
+	planApprovalTimeout time.Duration
+
+	// planApprovalRequested records that WithPlanApproval was passed, rather than configured.
+	planApprovalRequested bool
+}
+
+// WithRecency sets the recency preference of the run: "day", "week", "month" or "year".
+// Searches are restricted to that time range and sources published before it are down-ranked.
+// It overrides the recency configured in the research configuration.
+func WithRecency(recency string) ResearchOption {
+	return func(opts *researchOptions) {
+		opts.recency = recency
+	}
+}
+
+// WithAsOf sets the date the run treats as today, e.g., to reproduce a report as of a past date.
+// By default the run uses the time at which it starts.
+func WithAsOf(asOf time.Time) ResearchOption {
+	return func(opts *researchOptions) {
+		opts.asOf = asOf
+	}
+}
+
+// WithSearchLanguages sets the languages every sub-question is additionally searched in, e.g., "en", "zh-CN".
+// Each question is translated into these languages and the results of all languages are merged,
+// while the findings are still written in the language of the original query.
+// It overrides the search languages configured in the research configuration.
+func WithSearchLanguages(languages ...string) ResearchOption {
+	return func(opts *researchOptions) {
+		opts.searchLanguages = languages
+	}
+}
+
+// WithCorroboration enables or disables claim corroboration: after each sub-question is analyzed,
+// the claims of the analysis are cross-checked against the scraped sources, and single-source or
+// conflicting claims are flagged for the synthesis and reported in the final thought.
+// Corroboration costs one model call and one graph step per sub-question, so fewer sub-questions
+// fit into the configured max steps. It overrides the corroborate_claims setting of the research configuration.
+func WithCorroboration(enabled bool) ResearchOption {
+	return func(opts *researchOptions) {
+		opts.corroboration = enabled
+	}
+}
+
+// WithPlanApproval pauses the run after each research plan is generated until the plan is
+// approved or edited through the ResearchRun handle. A plan that receives no decision within
+// the timeout is auto-approved; a zero timeout waits until the run is cancelled.
+// It overrides the plan approval settings of the research configuration and is only accepted
+// by StartResearch, whose ResearchRun approves the plans.
+func WithPlanApproval(timeout time.Duration) ResearchOption {
+	return func(opts *researchOptions) {
+		opts.planApproval = true
+		opts.planApprovalTimeout = timeout
+		opts.planApprovalRequested = true
+	}
+}
+
+// applyResearchOptions applies the given options on top of the defaults from the research configuration.
+func applyResearchOptions(options ...ResearchOption) *researchOptions {
+	researchConfig := config.GetResearchConfig()
+	opts := &researchOptions{
+		recency:             researchConfig.Recency,
+		asOf:                time.Now(),
+		searchLanguages:     researchConfig.SearchLanguages,
+		corroboration:       researchConfig.CorroborateClaims,
+		planApproval:        researchConfig.RequirePlanApproval,
+		planApprovalTimeout: time.Duration(researchConfig.PlanApprovalTimeout) * time.Second,
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+	return opts
+}
+
+// researchFeatures are the optional stages of a research run. They determine the nodes of the
+// research graph the run executes and the number of graph steps each sub-question takes.
+type researchFeatures struct {
+	// corroboration adds the claim corroboration node after each analysis.
+	corroboration bool
+
+	// planApproval adds the plan approval node after each generated research plan.
+	planApproval bool
+}
+
+// features returns the optional stages enabled by the options.
+func (opts *researchOptions) features() researchFeatures {
+	return researchFeatures{corroboration: opts.corroboration, planApproval: opts.planApproval}
+}
+
+// stepsPerQuestion returns the number of graph steps researching one sub-question takes.
+func (f researchFeatures) stepsPerQuestion() int {
+	steps := StepsPerQuestion
+	if f.corroboration {
+		steps += CorroborationStepsPerQuestion
+	}
+	return steps
+}
+
+// reservedSteps returns the number of graph steps reserved for the stages outside the sub-questions,
+// such as generating questions, iterating and synthesizing, over a run of at most maxIterations iterations.
+func (f researchFeatures) reservedSteps(maxIterations int) int {
+	steps := 5
+	if f.planApproval {
+		steps += PlanApprovalStepsPerIteration * maxIterations
+	}
+	return steps
+}
@@ -0,0 +1,268 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tools2 "github.com/anboat/strato-sdk/core/tools"
+)
+
+// ErrNoPendingPlan is returned when a plan decision is submitted while no research plan is awaiting approval.
+var ErrNoPendingPlan = errors.New("no research plan is awaiting approval")
+
+// ResearchRun is a handle to a running research process.
+// It streams the agent's thoughts and, when plan approval is enabled, accepts
+// the reviewer's decision on each proposed research plan.
+type ResearchRun struct {
+	thoughts <-chan *StreamingThought // Channel for receiving streaming thoughts.
+	gate     *planGate                // Approval gate of the run, nil when plan approval is disabled.
+}
+
+// Thoughts returns the channel that provides real-time thoughts and updates from the agent.
+// The channel is closed when the research process ends.
+func (run *ResearchRun) Thoughts() <-chan *StreamingThought {
+	return run.thoughts
+}
+
+// PendingPlan returns a copy of the research plan awaiting approval, or nil if there is none.
+func (run *ResearchRun) PendingPlan() []*ResearchQuestion {
+	if run.gate == nil {
+		return nil
+	}
+	return run.gate.pending()
+}
+
+// ApprovePlan approves the pending research plan as proposed.
+//
+// Returns:
+//   - error: ErrNoPendingPlan if no plan is awaiting approval, or an error if a decision was already submitted.
+func (run *ResearchRun) ApprovePlan() error {
+	if run.gate == nil {
+		return ErrNoPendingPlan
+	}
+	return run.gate.submit(nil)
+}
+
+// SubmitPlan approves the pending research plan with the reviewer's edits.
+// The submitted questions replace the proposed ones:
+//   - A question whose ID matches a proposed question rewrites and reprioritizes it.
+//   - A question repeating the ID of an earlier one is ignored.
+//   - A proposed question missing from the plan is removed.
+//   - A question without an ID is added to the plan.
+//
+// Parameters:
+//   - plan: The edited research plan, usually derived from the Plan of the plan_proposed thought.
+//
+// Returns:
+//   - error: ErrNoPendingPlan if no plan is awaiting approval, or an error if a decision was already submitted.
+func (run *ResearchRun) SubmitPlan(plan []*ResearchQuestion) error {
+	if run.gate == nil {
+		return ErrNoPendingPlan
+	}
+	if plan == nil {
+		plan = make([]*ResearchQuestion, 0)
+	}
+	return run.gate.submit(plan)
+}
+
+// planGate pauses a research run until the proposed research plan is approved or edited.
+type planGate struct {
+	timeout   time.Duration            // Time after which the plan is auto-approved; zero waits until the run is cancelled.
+	mu        sync.Mutex               // Protects proposed.
+	proposed  []*ResearchQuestion      // The plan awaiting approval, nil when nothing is pending.
+	decisions chan []*ResearchQuestion // The reviewer's decision; a nil plan approves the proposal as is.
+}
+
+// newPlanGate creates an approval gate that auto-approves after the given timeout.
+func newPlanGate(timeout time.Duration) *planGate {
+	return &planGate{
+		timeout:   timeout,
+		decisions: make(chan []*ResearchQuestion, 1),
+	}
+}
+
+// open marks the given plan as awaiting approval and discards any stale decision.
+func (g *planGate) open(plan []*ResearchQuestion) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	select {
+	case <-g.decisions:
+	default:
+	}
+	g.proposed = copyQuestions(plan)
+}
+
+// close marks that no plan is awaiting approval anymore.
+func (g *planGate) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.proposed = nil
+}
+
+// pending returns a copy of the plan awaiting approval.
+func (g *planGate) pending() []*ResearchQuestion {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.proposed == nil {
+		return nil
+	}
+	return copyQuestions(g.proposed)
+}
+
+// submit hands the reviewer's decision to the waiting run.
+func (g *planGate) submit(plan []*ResearchQuestion) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.proposed == nil {
+		return ErrNoPendingPlan
+	}
+	select {
+	case g.decisions <- copyQuestions(plan):
+		return nil
+	default:
+		return fmt.Errorf("a decision on the pending research plan was already submitted")
+	}
+}
+
+// wait blocks until a decision is submitted, the timeout elapses or the context is done.
+//
+// Returns:
+//   - []*ResearchQuestion: The edited plan, or nil if the plan was approved as proposed.
+//   - bool: True if the plan was auto-approved because the timeout elapsed.
+//   - error: The context error if the run was cancelled while waiting.
+func (g *planGate) wait(ctx context.Context) ([]*ResearchQuestion, bool, error) {
+	var timeoutChan <-chan time.Time
+	if g.timeout > 0 {
+		timer := time.NewTimer(g.timeout)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
+
+	select {
+	case plan := <-g.decisions:
+		return plan, false, nil
+	case <-timeoutChan:
+		return nil, true, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// applyPlanEdits applies the reviewer's edited plan to the proposed questions of the state.
+//
+// Parameters:
+//   - state: The current research state, whose pending questions form the proposed plan.
+//   - plan: The edited plan submitted by the reviewer.
+//   - maxSteps: The maximum number of graph steps, which bounds the number of added questions.
+//
+// Returns:
+//   - string: A summary of the applied edits.
+func applyPlanEdits(state *StreamingResearchState, plan []*ResearchQuestion, maxSteps int) string {
+	proposed := make(map[string]*ResearchQuestion)
+	for _, q := range pendingQuestions(state) {
+		proposed[q.ID] = q
+	}
+
+	// Rewrite and reprioritize the proposed questions kept in the plan.
+	kept := make(map[string]bool)
+	var additions []*ResearchQuestion
+	rewritten := 0
+	for _, edit := range plan {
+		if edit == nil {
+			continue
+		}
+		text := strings.TrimSpace(edit.Question)
+
+		if kept[edit.ID] {
+			// Only the first question with a proposed ID edits it; repeats are ignored.
+			continue
+		}
+		original, ok := proposed[edit.ID]
+		if !ok {
+			if text != "" {
+				additions = append(additions, edit)
+			}
+			continue
+		}
+
+		kept[edit.ID] = true
+		if text != "" && text != original.Question {
+			original.Question = text
+			rewritten++
+		}
+		if edit.Priority > 0 {
+			original.Priority = edit.Priority
+		}
+	}
+
+	// Remove the proposed questions that are missing from the plan.
+	questions := make([]*ResearchQuestion, 0, len(state.ResearchQuestions)+len(additions))
+	removed := 0
+	for _, q := range state.ResearchQuestions {
+		if _, ok := proposed[q.ID]; ok && !kept[q.ID] {
+			removed++
+			continue
+		}
+		questions = append(questions, q)
+	}
+
+	// Add the new questions within the step budget.
+	_, maxNewQuestions := calculateMaxQuestions(maxSteps, state.MaxIterations, len(questions), state.features)
+	added := 0
+	for _, edit := range additions {
+		if added >= maxNewQuestions {
+			break
+		}
+		added++
+		questions = append(questions, &ResearchQuestion{
+			ID:            fmt.Sprintf("%s%d_r%d", QuestionIDPrefix, state.CurrentIteration, added),
+			Question:      strings.TrimSpace(edit.Question),
+			Status:        QuestionStatusPending,
+			SearchResults: make([]*tools2.SearchResponse, 0),
+			WebContents:   make([]*tools2.WebScrapeResponse, 0),
+			Analysis:      "",
+			Claims:        make([]*Claim, 0),
+			Priority:      edit.Priority,
+		})
+	}
+	state.ResearchQuestions = questions
+
+	summary := fmt.Sprintf("Research plan approved with edits: %d rewritten, %d removed, %d added", rewritten, removed, added)
+	if dropped := len(additions) - added; dropped > 0 {
+		summary += fmt.Sprintf(", %d additions dropped because of the question limit", dropped)
+	}
+	return summary
+}
+
+// copyQuestions returns copies of the given questions, so the reviewer cannot mutate the run's state directly.
+func copyQuestions(questions []*ResearchQuestion) []*ResearchQuestion {
+	if questions == nil {
+		return nil
+	}
+	copies := make([]*ResearchQuestion, 0, len(questions))
+	for _, q := range questions {
+		if q == nil {
+			continue
+		}
+		c := *q
+		copies = append(copies, &c)
+	}
+	return copies
+}
+
+// pendingQuestions returns the questions of the state that have not been researched yet.
+func pendingQuestions(state *StreamingResearchState) []*ResearchQuestion {
+	pending := make([]*ResearchQuestion, 0)
+	for _, q := range state.ResearchQuestions {
+		if q.Status == QuestionStatusPending {
+			pending = append(pending, q)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,307 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// planState returns a state at the given iteration with a completed question and the pending questions.
+func planState(iteration int, pending ...*ResearchQuestion) *StreamingResearchState {
+	questions := []*ResearchQuestion{{ID: "q_0_1", Question: "done", Status: QuestionStatusCompleted, Priority: 9}}
+	for _, q := range pending {
+		q.Status = QuestionStatusPending
+		questions = append(questions, q)
+	}
+	return &StreamingResearchState{CurrentIteration: iteration, MaxIterations: 3, ResearchQuestions: questions}
+}
+
+// questionIDs returns the IDs of the questions, in order.
+func questionIDs(questions []*ResearchQuestion) []string {
+	ids := make([]string, len(questions))
+	for i, q := range questions {
+		ids[i] = q.ID
+	}
+	return ids
+}
+
+// findQuestion returns the question with the ID, or nil.
+func findQuestion(questions []*ResearchQuestion, id string) *ResearchQuestion {
+	for _, q := range questions {
+		if q.ID == id {
+			return q
+		}
+	}
+	return nil
+}
+
+func TestApplyPlanEditsRewrite(t *testing.T) {
+	state := planState(1,
+		&ResearchQuestion{ID: "q_1_1", Question: "first", Priority: 5},
+		&ResearchQuestion{ID: "q_1_2", Question: "second", Priority: 3},
+	)
+
+	summary := applyPlanEdits(state, []*ResearchQuestion{
+		{ID: "q_1_1", Question: "  first, rewritten  ", Priority: 8},
+		{ID: "q_1_2", Question: " second "},
+		nil,
+	}, 50)
+
+	if want := "Research plan approved with edits: 1 rewritten, 0 removed, 0 added"; summary != want {
+		t.Errorf("applyPlanEdits() = %q, want %q", summary, want)
+	}
+	first := findQuestion(state.ResearchQuestions, "q_1_1")
+	if first.Question != "first, rewritten" || first.Priority != 8 {
+		t.Errorf("rewritten question = %q with priority %d, want %q with priority 8", first.Question, first.Priority, "first, rewritten")
+	}
+	second := findQuestion(state.ResearchQuestions, "q_1_2")
+	if second.Question != "second" || second.Priority != 3 {
+		t.Errorf("kept question = %q with priority %d, want %q with priority 3", second.Question, second.Priority, "second")
+	}
+}
+
+func TestApplyPlanEditsRemove(t *testing.T) {
+	state := planState(1,
+		&ResearchQuestion{ID: "q_1_1", Question: "first"},
+		&ResearchQuestion{ID: "q_1_2", Question: "second"},
+		&ResearchQuestion{ID: "q_1_3", Question: "third"},
+	)
+
+	summary := applyPlanEdits(state, []*ResearchQuestion{{ID: "q_1_2"}}, 50)
+
+	if want := "Research plan approved with edits: 0 rewritten, 2 removed, 0 added"; summary != want {
+		t.Errorf("applyPlanEdits() = %q, want %q", summary, want)
+	}
+	if got, want := questionIDs(state.ResearchQuestions), []string{"q_0_1", "q_1_2"}; !equalStrings(got, want) {
+		t.Errorf("questions = %v, want %v", got, want)
+	}
+
+	// An empty plan removes every proposed question but keeps the researched ones.
+	summary = applyPlanEdits(state, []*ResearchQuestion{}, 50)
+	if want := "Research plan approved with edits: 0 rewritten, 1 removed, 0 added"; summary != want {
+		t.Errorf("applyPlanEdits() with an empty plan = %q, want %q", summary, want)
+	}
+	if got, want := questionIDs(state.ResearchQuestions), []string{"q_0_1"}; !equalStrings(got, want) {
+		t.Errorf("questions after an empty plan = %v, want %v", got, want)
+	}
+}
+
+func TestApplyPlanEditsDuplicateIDs(t *testing.T) {
+	state := planState(1, &ResearchQuestion{ID: "q_1_1", Question: "first"})
+
+	summary := applyPlanEdits(state, []*ResearchQuestion{
+		{ID: "q_1_1", Question: "first, rewritten"},
+		{ID: "q_1_1", Question: "first, rewritten again"},
+		{ID: "q_0_1", Question: "a researched question cannot be edited"},
+	}, 50)
+
+	if want := "Research plan approved with edits: 1 rewritten, 0 removed, 1 added"; summary != want {
+		t.Errorf("applyPlanEdits() = %q, want %q", summary, want)
+	}
+	if got := findQuestion(state.ResearchQuestions, "q_1_1").Question; got != "first, rewritten" {
+		t.Errorf("question edited twice = %q, want the first edit", got)
+	}
+	if got := findQuestion(state.ResearchQuestions, "q_0_1").Question; got != "done" {
+		t.Errorf("researched question = %q, want it unchanged", got)
+	}
+	if got, want := questionIDs(state.ResearchQuestions), []string{"q_0_1", "q_1_1", "q_1_r1"}; !equalStrings(got, want) {
+		t.Errorf("questions = %v, want %v", got, want)
+	}
+}
+
+func TestApplyPlanEditsAddPastLimit(t *testing.T) {
+	state := planState(2, &ResearchQuestion{ID: "q_2_1", Question: "first"})
+
+	// The reserved steps plus three questions: the researched one, the proposed one and one addition.
+	maxSteps := state.features.reservedSteps(state.MaxIterations) + 3*state.features.stepsPerQuestion()
+	summary := applyPlanEdits(state, []*ResearchQuestion{
+		{ID: "q_2_1"},
+		{Question: "  added  ", Priority: 7},
+		{Question: "   "},
+		{Question: "over the limit"},
+		{Question: "also over the limit"},
+	}, maxSteps)
+
+	if want := "Research plan approved with edits: 0 rewritten, 0 removed, 1 added, 2 additions dropped because of the question limit"; summary != want {
+		t.Errorf("applyPlanEdits() = %q, want %q", summary, want)
+	}
+	added := findQuestion(state.ResearchQuestions, "q_2_r1")
+	if added == nil {
+		t.Fatalf("questions = %v, want an added q_2_r1", questionIDs(state.ResearchQuestions))
+	}
+	if added.Question != "added" || added.Priority != 7 || added.Status != QuestionStatusPending {
+		t.Errorf("added question = %q, priority %d, status %s, want %q, priority 7, status %s",
+			added.Question, added.Priority, added.Status, "added", QuestionStatusPending)
+	}
+	if len(state.ResearchQuestions) != 3 {
+		t.Errorf("questions = %v, want 3", questionIDs(state.ResearchQuestions))
+	}
+
+	// Plan approval reserves a step per iteration, which leaves no room for the addition.
+	state = planState(2, &ResearchQuestion{ID: "q_2_1", Question: "first"})
+	state.features.planApproval = true
+	summary = applyPlanEdits(state, []*ResearchQuestion{{ID: "q_2_1"}, {Question: "added"}}, maxSteps)
+	if want := "Research plan approved with edits: 0 rewritten, 0 removed, 0 added, 1 additions dropped because of the question limit"; summary != want {
+		t.Errorf("applyPlanEdits() with plan approval = %q, want %q", summary, want)
+	}
+}
+
+// equalStrings reports whether the slices hold the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPlanGateDecision(t *testing.T) {
+	gate := newPlanGate(0)
+	if err := gate.submit(nil); !errors.Is(err, ErrNoPendingPlan) {
+		t.Errorf("submit() without a pending plan error = %v, want %v", err, ErrNoPendingPlan)
+	}
+
+	proposed := []*ResearchQuestion{{ID: "q_1_1", Question: "first"}}
+	gate.open(proposed)
+	pending := gate.pending()
+	pending[0].Question = "changed by the reviewer"
+	if proposed[0].Question != "first" || gate.pending()[0].Question != "first" {
+		t.Error("pending() returned the proposed questions instead of copies")
+	}
+
+	edited := []*ResearchQuestion{{ID: "q_1_1", Question: "edited"}}
+	if err := gate.submit(edited); err != nil {
+		t.Fatalf("submit() error = %v", err)
+	}
+	edited[0].Question = "changed after submitting"
+
+	plan, timedOut, err := gate.wait(context.Background())
+	if err != nil || timedOut {
+		t.Fatalf("wait() = %v, %v, want no timeout and no error", timedOut, err)
+	}
+	if len(plan) != 1 || plan[0].Question != "edited" {
+		t.Errorf("wait() plan = %v, want the submitted copy", questionIDs(plan))
+	}
+
+	gate.close()
+	if got := gate.pending(); got != nil {
+		t.Errorf("pending() after close = %v, want nil", questionIDs(got))
+	}
+	if err := gate.submit(nil); !errors.Is(err, ErrNoPendingPlan) {
+		t.Errorf("submit() after close error = %v, want %v", err, ErrNoPendingPlan)
+	}
+}
+
+func TestPlanGateApproveAsProposed(t *testing.T) {
+	gate := newPlanGate(time.Minute)
+	gate.open([]*ResearchQuestion{{ID: "q_1_1"}})
+	if err := gate.submit(nil); err != nil {
+		t.Fatalf("submit() error = %v", err)
+	}
+
+	plan, timedOut, err := gate.wait(context.Background())
+	if plan != nil || timedOut || err != nil {
+		t.Errorf("wait() = %v, %v, %v, want nil, false, nil", questionIDs(plan), timedOut, err)
+	}
+}
+
+func TestPlanGateDoubleSubmit(t *testing.T) {
+	gate := newPlanGate(0)
+	gate.open([]*ResearchQuestion{{ID: "q_1_1"}})
+
+	if err := gate.submit(nil); err != nil {
+		t.Fatalf("first submit() error = %v", err)
+	}
+	err := gate.submit([]*ResearchQuestion{{ID: "q_1_1", Question: "late edit"}})
+	if err == nil || errors.Is(err, ErrNoPendingPlan) {
+		t.Errorf("second submit() error = %v, want an already submitted error", err)
+	}
+
+	plan, _, _ := gate.wait(context.Background())
+	if plan != nil {
+		t.Errorf("wait() plan = %v, want the first decision", questionIDs(plan))
+	}
+}
+
+func TestPlanGateTimeoutAutoApproves(t *testing.T) {
+	gate := newPlanGate(20 * time.Millisecond)
+	gate.open([]*ResearchQuestion{{ID: "q_1_1"}})
+
+	start := time.Now()
+	plan, timedOut, err := gate.wait(context.Background())
+	if plan != nil || !timedOut || err != nil {
+		t.Errorf("wait() = %v, %v, %v, want nil, true, nil", questionIDs(plan), timedOut, err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("wait() returned after %v, before the timeout", elapsed)
+	}
+}
+
+func TestPlanGateCancel(t *testing.T) {
+	gate := newPlanGate(0)
+	gate.open([]*ResearchQuestion{{ID: "q_1_1"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, timedOut, err := gate.wait(ctx); timedOut || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() = %v, %v, want no timeout and %v", timedOut, err, context.DeadlineExceeded)
+	}
+}
+
+func TestPlanGateOpenDrainsStaleDecision(t *testing.T) {
+	gate := newPlanGate(20 * time.Millisecond)
+
+	// A decision that arrives after the plan was auto-approved stays in the channel.
+	gate.open([]*ResearchQuestion{{ID: "q_1_1"}})
+	if _, timedOut, _ := gate.wait(context.Background()); !timedOut {
+		t.Fatal("wait() did not time out")
+	}
+	if err := gate.submit([]*ResearchQuestion{{ID: "q_1_1", Question: "stale"}}); err != nil {
+		t.Fatalf("submit() error = %v", err)
+	}
+	gate.close()
+
+	// The next plan must not be decided by the stale decision.
+	gate.open([]*ResearchQuestion{{ID: "q_2_1"}})
+	plan, timedOut, err := gate.wait(context.Background())
+	if plan != nil || !timedOut || err != nil {
+		t.Errorf("wait() for the next plan = %v, %v, %v, want nil, true, nil", questionIDs(plan), timedOut, err)
+	}
+
+	// A fresh decision on the next plan is accepted.
+	gate.open([]*ResearchQuestion{{ID: "q_3_1"}})
+	if err := gate.submit(nil); err != nil {
+		t.Errorf("submit() on the next plan error = %v", err)
+	}
+}
+
+func TestResearchRunWithoutPlanApproval(t *testing.T) {
+	run := &ResearchRun{}
+	if got := run.PendingPlan(); got != nil {
+		t.Errorf("PendingPlan() = %v, want nil", questionIDs(got))
+	}
+	if err := run.ApprovePlan(); !errors.Is(err, ErrNoPendingPlan) {
+		t.Errorf("ApprovePlan() error = %v, want %v", err, ErrNoPendingPlan)
+	}
+	if err := run.SubmitPlan(nil); !errors.Is(err, ErrNoPendingPlan) {
+		t.Errorf("SubmitPlan() error = %v, want %v", err, ErrNoPendingPlan)
+	}
+}
+
+func TestWithPlanApproval(t *testing.T) {
+	opts := applyResearchOptions(WithPlanApproval(time.Minute))
+	if !opts.planApproval || opts.planApprovalTimeout != time.Minute || !opts.planApprovalRequested {
+		t.Errorf("WithPlanApproval() options = %+v, want plan approval with a minute timeout", *opts)
+	}
+	if !opts.features().planApproval {
+		t.Error("WithPlanApproval() did not enable the plan approval stage")
+	}
+	if applyResearchOptions().features().planApproval {
+		t.Error("plan approval is enabled by default")
+	}
+}
@@ -65,8 +65,9 @@ const (
 		3.  **Cite Sources**: For every significant piece of information, statistic, or claim you include in your answer, you MUST provide an inline citation using the format [URL] where URL is the complete source URL from the context.
 		4.  **Track URLs**: Keep track of all URLs you reference in your answer for later compilation.
 		5.  **Accuracy**: Ensure your answer is accurate and strictly derived from the provided "Context". Do not add any information from external knowledge. If the context does not contain enough information to answer the question, explicitly state that.
-		6.  **Language**: The answer MUST be in the same language as the "Research Question".
-		7.  **Format**: Present the answer as a clear, concise text with proper citations. Do not add any conversational fluff or introductory phrases like "Here is the answer:". Just provide the answer directly.
+		6.  **Recency**: Each source shows its publication date when known. When sources disagree over time-sensitive facts, prefer the most recent one, and state the date a figure refers to (e.g., "as of 2023"). Never present information from an old source as current.
+		7.  **Language**: The answer MUST be in the same language as the "Research Question". Sources may be written in other languages; translate the relevant information into the language of the "Research Question" and keep citing the original source URL.
+		8.  **Format**: Present the answer as a clear, concise text with proper citations. Do not add any conversational fluff or introductory phrases like "Here is the answer:". Just provide the answer directly.
 		
 		## Output Format
 		Your response must include:
@@ -118,6 +119,42 @@ const (
 		-   **Verify URL Integration**: Ensure every URL from the research findings is properly integrated into the report with appropriate context.
 		-   **Check Citation Consistency**: Make sure all inline citations correspond to **URLs** in the References section.
 		-   **Completeness Check**: Confirm that no important information from the research findings is omitted from the final report.
+
+		### 5. Weakly Corroborated Claims
+		-   Some findings are followed by a "Claims Requiring Caution" list. Each entry is marked [single source] when only one source backs it, or [conflicting] when the sources disagree.
+		-   Never use such a claim as a headline finding, a key takeaway, or the basis of the conclusion.
+		-   When you mention a [single source] claim, say explicitly that it is reported by a single source.
+		-   When you mention a [conflicting] claim, present both the supporting and the contradicting sources and make the disagreement clear to the reader.
+	`
+
+	// CorroborateClaimsPromptTemplate is the prompt template for cross-checking the analysis of a research question.
+	// It asks the LLM to break the analysis into atomic claims and to decide, for every claim,
+	// which of the scraped sources support it and which contradict it.
+	// The output is expected in a strict JSON format.
+	CorroborateClaimsPromptTemplate = `
+		You are a meticulous fact-checker. Your task is to verify an analysis against the source documents it was written from.
+		# Analysis to Verify
+		%s
+
+		# Source Documents
+		%s
+
+		## Your Task
+		1.  **Extract Claims**: Break the "Analysis to Verify" into atomic factual claims. Each claim must state exactly one fact, figure, or assertion. Skip opinions, transitions, and statements about the lack of information.
+		2.  **Check Every Source**: For each claim, read every document in "Source Documents" and decide whether it supports the claim, contradicts the claim, or does not address it.
+		3.  **Be Strict**: A source supports a claim only if it states the same fact. A source contradicts a claim if it states an incompatible fact (e.g., a different figure, date, or outcome). Citing a URL in the analysis is not evidence; only the document content counts.
+		4.  **Use Only Given URLs**: Refer to sources only by the exact **Link** values in "Source Documents". Do not invent URLs.
+		5.  **Language**: Write each claim in the same language as the "Analysis to Verify".
+
+		# Output Format
+		You MUST provide your response ONLY in the following JSON format. Do not include any other text before or after the JSON block.
+		[
+		{
+			"claim": "A single atomic claim taken from the analysis.",
+			"supporting_sources": ["https://source-that-supports.example"],
+			"contradicting_sources": []
+		}
+		]
 	`
 
 	// ShouldSynthesizeEarlyPromptTemplate is the prompt template for determining if enough information
@@ -145,4 +182,37 @@ const (
 		-   If there are significant gaps, a lack of depth, or unanswered questions, respond with **only** the word false.
 		Do not provide any explanations or other text. Your entire output must be either true or false.
 	`
+
+	// TranslateQueryPromptTemplate is the prompt template for translating a research question into search queries
+	// in other languages for cross-lingual search. It asks the LLM to detect the language of the question and to
+	// write a natural search query for each target language.
+	// The output is expected in a strict JSON format.
+	TranslateQueryPromptTemplate = `
+		You are an expert multilingual search specialist. Your task is to turn a research question into effective web search queries in several languages.
+		# Research Question
+		%s
+		# Target Languages
+		%s
+		## Your Task
+		1.  **Detect the Source Language**: Identify the language of the "Research Question" and report it using the same code style as the "Target Languages" (e.g., "en", "zh-CN").
+		2.  **Translate for Search**: For each target language, write the query a native speaker would type into a search engine to research the question. Keep names, product names, and technical terms in the form that is most common in that language.
+		3.  **Preserve Meaning**: Do not broaden, narrow, or answer the question.
+		# Output Format
+		You MUST provide your response ONLY in the following JSON format. Do not include any other text before or after the JSON block.
+		{
+			"source_language": "zh-CN",
+			"queries": [
+				{"lang": "en", "query": "The search query in English."}
+			]
+		}
+	`
+
+	// DateContextPromptTemplate is prepended to every prompt of a research run.
+	// It tells the LLM the date the run treats as today, so that dated information
+	// is presented "as of" its date instead of as current fact.
+	DateContextPromptTemplate = `
+		# Current Date
+		Today's date is %s. Use it as "now" when reasoning about time: phrases such as "latest", "current", "this year", or "recent" refer to this date.
+		Information from earlier years is historical, not current. When you state a time-sensitive fact, figure, or status, say which date it refers to (e.g., "as of March 2024").
+	`
 )
@@ -5,14 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/anboat/strato-sdk/adapters/llm"
+	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/adapters/web"
 	"github.com/anboat/strato-sdk/config"
 	tools2 "github.com/anboat/strato-sdk/core/tools"
 	"github.com/anboat/strato-sdk/pkg/logging"
+	"github.com/anboat/strato-sdk/pkg/urlcanon"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	// Anonymous imports to ensure adapter init functions are called.
 	_ "github.com/anboat/strato-sdk/adapters/search/firecrawl"
@@ -21,17 +26,36 @@ import (
 	// Anonymous imports to ensure adapter init functions are called.
 	_ "github.com/anboat/strato-sdk/adapters/web/firecrawl"
 	_ "github.com/anboat/strato-sdk/adapters/web/jina"
+	_ "github.com/anboat/strato-sdk/adapters/web/native"
 )
 
 // StreamingThought represents a single thought or piece of information streamed
 // during the research process. It provides real-time updates on the agent's state and actions.
 type StreamingThought struct {
-	Timestamp  time.Time `json:"timestamp"`   // Timestamp of when the thought was generated.
-	Stage      string    `json:"stage"`       // Current stage: thinking, searching, analyzing, synthesizing.
-	Content    string    `json:"content"`     // The specific content of the thought or analysis result.
-	Action     Action    `json:"action"`      // The action currently being executed.
-	IsComplete bool      `json:"is_complete"` // Indicates if the entire research process is complete.
-	Sources    []string  `json:"sources"`     // List of source URLs for traceability.
+	Timestamp       time.Time           `json:"timestamp"`                  // Timestamp of when the thought was generated.
+	Stage           string              `json:"stage"`                      // Current stage: thinking, searching, analyzing, synthesizing.
+	Content         string              `json:"content"`                    // The specific content of the thought or analysis result.
+	Action          Action              `json:"action"`                     // The action currently being executed.
+	IsComplete      bool                `json:"is_complete"`                // Indicates if the entire research process is complete.
+	Sources         []string            `json:"sources"`                    // List of source URLs for traceability.
+	Claims          []*Claim            `json:"claims,omitempty"`           // Claims flagged during corroboration (single-source or conflicting).
+	SourceLanguages map[string]string   `json:"source_languages,omitempty"` // Language each source was found in, keyed by URL (cross-lingual search only).
+	Plan            []*ResearchQuestion `json:"plan,omitempty"`             // Proposed research plan awaiting approval (plan approval only).
+}
+
+// Claim is an atomic factual claim extracted from a question's analysis,
+// together with the scraped sources that support or contradict it.
+type Claim struct {
+	Claim                string   `json:"claim"`                 // The atomic claim.
+	SupportingSources    []string `json:"supporting_sources"`    // URLs of the sources that support the claim.
+	ContradictingSources []string `json:"contradicting_sources"` // URLs of the sources that contradict the claim.
+	SingleSource         bool     `json:"single_source"`         // The claim is backed by fewer sources than required.
+	Conflicting          bool     `json:"conflicting"`           // At least one source contradicts the claim.
+}
+
+// IsFlagged reports whether the claim is weakly corroborated and must be treated with caution.
+func (c *Claim) IsFlagged() bool {
+	return c.SingleSource || c.Conflicting
 }
 
 // ResearchQuestion represents a specific sub-question within the research process,
@@ -43,6 +67,7 @@ type ResearchQuestion struct {
 	SearchResults []*tools2.SearchResponse    `json:"search_results"` // List of results from the search engine.
 	WebContents   []*tools2.WebScrapeResponse `json:"web_contents"`   // Scraped web content details.
 	Analysis      string                      `json:"analysis"`       // In-depth analysis based on the collected information.
+	Claims        []*Claim                    `json:"claims"`         // Claims extracted from the analysis and checked against the sources.
 	Priority      int                         `json:"priority"`       // Priority of the question (1-10), higher is more important.
 }
 
@@ -59,16 +84,24 @@ type StreamingResearchState struct {
 	FinalAnswer         string                 `json:"final_answer"`         // The final answer synthesized from all research findings.
 	IsComplete          bool                   `json:"is_complete"`          // Indicates if the entire research process is complete.
 	CompletedQuestions  int                    `json:"completed_questions"`  // The number of completed research questions.
+	AsOf                time.Time              `json:"as_of"`                // The date the run treats as today.
+	Recency             string                 `json:"recency"`              // Recency preference of the run (day, week, month, year), empty for none.
+	SearchLanguages     []string               `json:"search_languages"`     // Additional languages every question is searched in.
 	ThoughtChannel      chan *StreamingThought `json:"-"`                    // Channel for transmitting streaming thoughts (not serialized to JSON).
+	planGate            *planGate              `json:"-"`                    // Approval gate for generated research plans, nil when plan approval is disabled.
+	features            researchFeatures       `json:"-"`                    // Optional stages of the run, such as claim corroboration.
 }
 
 // StreamingResearchAgent is an intelligent research agent based on the Eino framework,
 // supporting real-time, streaming output of the research process.
 type StreamingResearchAgent struct {
-	chatModel  model.ToolCallingChatModel                                         // The large language model for generating questions, analyzing content, and synthesizing answers.
-	searchTool tool.InvokableTool                                                 // The search tool, supporting various search engine adapters.
-	webTool    tool.InvokableTool                                                 // The web scraping tool for fetching detailed web content.
-	graph      compose.Runnable[*StreamingResearchState, *StreamingResearchState] // The Eino workflow graph defining the research process logic.
+	chatModel  model.ToolCallingChatModel // The large language model for generating questions, analyzing content, and synthesizing answers.
+	searchTool tool.InvokableTool         // The search tool, supporting various search engine adapters.
+	webTool    tool.InvokableTool         // The web scraping tool for fetching detailed web content.
+	canon      *urlcanon.Canonicalizer    // Keys URLs to deduplicate scraping and source citations.
+
+	graphMu sync.Mutex                                                                              // Guards graphs.
+	graphs  map[researchFeatures]compose.Runnable[*StreamingResearchState, *StreamingResearchState] // The Eino workflow graphs defining the research process logic, per set of optional stages.
 }
 
 // NewStreamingResearchAgent creates a new StreamingResearchAgent.
@@ -103,36 +136,97 @@ func NewStreamingResearchAgent(ctx context.Context) (*StreamingResearchAgent, er
 		chatModel:  chatModel,
 		searchTool: searchTool,
 		webTool:    webTool,
+		canon:      urlcanon.New(config.GetCanonicalizationConfig()),
+		graphs:     make(map[researchFeatures]compose.Runnable[*StreamingResearchState, *StreamingResearchState]),
 	}
 
-	// Build the research graph.
-	graph, err := agent.buildStreamingResearchGraph(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build streaming research graph: %w", err)
+	// Build the research graph for the configured stages; runs that enable others build theirs on first use.
+	if _, err := agent.researchGraph(ctx, applyResearchOptions().features()); err != nil {
+		return nil, err
 	}
 
-	agent.graph = graph
 	return agent, nil
 }
 
+// researchGraph returns the research graph with the given optional stages, building it on first use.
+func (agent *StreamingResearchAgent) researchGraph(ctx context.Context, features researchFeatures) (compose.Runnable[*StreamingResearchState, *StreamingResearchState], error) {
+	agent.graphMu.Lock()
+	defer agent.graphMu.Unlock()
+
+	if graph, ok := agent.graphs[features]; ok {
+		return graph, nil
+	}
+
+	graph, err := agent.buildStreamingResearchGraph(ctx, features)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build streaming research graph: %w", err)
+	}
+	agent.graphs[features] = graph
+	return graph, nil
+}
+
 // ResearchWithStreaming executes a streaming research process.
 // It starts an asynchronous research workflow and returns a channel that provides
 // real-time thoughts and updates from the agent.
+// Runs started here never wait for plan approval, because the caller has no ResearchRun to
+// approve plans with: require_plan_approval is ignored and WithPlanApproval is rejected.
+// Use StartResearch instead to approve or edit research plans.
 //
 // Parameters:
 //   - ctx: A context.Context to control the research lifecycle.
 //   - query: The user's original research query.
+//   - opts: Optional run-level settings, such as WithRecency.
 //
 // Returns:
 //   - <-chan *StreamingThought: A read-only channel for receiving streaming thoughts.
+//   - error: An error if the research process fails to start or WithPlanApproval is passed.
+func (agent *StreamingResearchAgent) ResearchWithStreaming(ctx context.Context, query string, opts ...ResearchOption) (<-chan *StreamingThought, error) {
+	if applyResearchOptions(opts...).planApprovalRequested {
+		return nil, fmt.Errorf("plan approval requires StartResearch, whose ResearchRun approves the plans")
+	}
+
+	opts = append(opts, func(opts *researchOptions) {
+		opts.planApproval = false
+	})
+	run, err := agent.StartResearch(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return run.Thoughts(), nil
+}
+
+// StartResearch starts an asynchronous research workflow and returns a handle to the run.
+// The handle streams the agent's thoughts and, when plan approval is enabled, accepts the
+// decision on each proposed research plan through ApprovePlan or SubmitPlan.
+//
+// Parameters:
+//   - ctx: A context.Context to control the research lifecycle.
+//   - query: The user's original research query.
+//   - opts: Optional run-level settings, such as WithPlanApproval.
+//
+// Returns:
+//   - *ResearchRun: A handle to the running research process.
 //   - error: An error if the research process fails to start.
-func (agent *StreamingResearchAgent) ResearchWithStreaming(ctx context.Context, query string) (<-chan *StreamingThought, error) {
+func (agent *StreamingResearchAgent) StartResearch(ctx context.Context, query string, opts ...ResearchOption) (*ResearchRun, error) {
 	// Get the research configuration.
 	researchConfig := config.GetResearchConfig()
+	runOptions := applyResearchOptions(opts...)
+
+	// Get the research graph with the stages enabled for the run.
+	graph, err := agent.researchGraph(ctx, runOptions.features())
+	if err != nil {
+		return nil, err
+	}
 
 	// Create the thought channel.
 	thoughtChan := make(chan *StreamingThought, researchConfig.ChannelBuffer)
 
+	// Create the plan approval gate if enabled.
+	var gate *planGate
+	if runOptions.planApproval {
+		gate = newPlanGate(runOptions.planApprovalTimeout)
+	}
+
 	// Initialize the research state.
 	initialState := &StreamingResearchState{
 		OriginalQuery:       query,
@@ -144,7 +238,12 @@ func (agent *StreamingResearchAgent) ResearchWithStreaming(ctx context.Context,
 		FinalAnswer:         "",
 		IsComplete:          false,
 		CompletedQuestions:  0,
+		AsOf:                runOptions.asOf,
+		Recency:             search.NormalizeTimeRange(runOptions.recency),
+		SearchLanguages:     runOptions.searchLanguages,
 		ThoughtChannel:      thoughtChan,
+		planGate:            gate,
+		features:            runOptions.features(),
 	}
 
 	// Execute the research in a goroutine.
@@ -154,7 +253,7 @@ func (agent *StreamingResearchAgent) ResearchWithStreaming(ctx context.Context,
 		logging.Infof("Starting streaming research: %s", query)
 
 		// Invoke the research graph.
-		finalState, err := agent.graph.Invoke(ctx, initialState)
+		finalState, err := graph.Invoke(ctx, initialState)
 		if err != nil {
 			logging.Errorf("Research graph execution failed: %v", err)
 			// Send an error message.
@@ -171,31 +270,36 @@ func (agent *StreamingResearchAgent) ResearchWithStreaming(ctx context.Context,
 		// Send the final answer, with a nil check for finalState.
 		if finalState != nil && finalState.IsComplete {
 			thoughtChan <- &StreamingThought{
-				Timestamp:  time.Now(),
-				Stage:      StageCompleted,
-				Content:    finalState.FinalAnswer,
-				Action:     ActionResearchComplete,
-				IsComplete: true,
-				Sources:    agent.extractSources(finalState),
+				Timestamp:       time.Now(),
+				Stage:           StageCompleted,
+				Content:         finalState.FinalAnswer,
+				Action:          ActionResearchComplete,
+				IsComplete:      true,
+				Sources:         agent.extractSources(finalState),
+				Claims:          agent.extractFlaggedClaims(finalState),
+				SourceLanguages: agent.extractSourceLanguages(finalState),
 			}
 		} else if finalState == nil {
 			logging.Warnf("Research graph returned a nil finalState without an error.")
 		}
 	}()
 
-	return thoughtChan, nil
+	return &ResearchRun{thoughts: thoughtChan, gate: gate}, nil
 }
 
 // buildStreamingResearchGraph constructs the complex workflow graph using the Eino framework.
 // This graph defines the execution logic, branching, and iteration control for the research process.
 //
+// Optional stages, such as claim corroboration and plan approval, are only added to the graph when enabled.
+//
 // Parameters:
 //   - ctx: A context.Context for the graph compilation process.
+//   - features: The optional stages of the research process.
 //
 // Returns:
 //   - compose.Runnable: An executable workflow graph instance.
 //   - error: An error if the graph construction fails.
-func (agent *StreamingResearchAgent) buildStreamingResearchGraph(ctx context.Context) (compose.Runnable[*StreamingResearchState, *StreamingResearchState], error) {
+func (agent *StreamingResearchAgent) buildStreamingResearchGraph(ctx context.Context, features researchFeatures) (compose.Runnable[*StreamingResearchState, *StreamingResearchState], error) {
 	// Get research configuration.
 	researchConfig := config.GetResearchConfig()
 
@@ -213,10 +317,16 @@ func (agent *StreamingResearchAgent) buildStreamingResearchGraph(ctx context.Con
 
 	// Add nodes
 	_ = g.AddLambdaNode(NodeGenerateQuestions, generateQuestionsLambda)
+	if features.planApproval {
+		_ = g.AddLambdaNode(NodeApprovePlan, compose.InvokableLambda(agent.createApprovePlanNode()))
+	}
 	_ = g.AddLambdaNode(NodeSelectQuestion, selectQuestionLambda)
 	_ = g.AddLambdaNode(NodeSearchQuestion, searchQuestionLambda)
 	_ = g.AddLambdaNode(NodeScrapeWebContent, scrapeWebContentLambda)
 	_ = g.AddLambdaNode(NodeAnalyzeQuestion, analyzeQuestionLambda)
+	if features.corroboration {
+		_ = g.AddLambdaNode(NodeCorroborateClaims, compose.InvokableLambda(agent.createCorroborateClaimsNode()))
+	}
 	_ = g.AddLambdaNode(NodeSynthesizeFinalAnswer, synthesizeFinalAnswerLambda)
 	_ = g.AddLambdaNode(NodeIncrementIteration, incrementIterationLambda)
 
@@ -259,7 +369,7 @@ func (agent *StreamingResearchAgent) buildStreamingResearchGraph(ctx context.Con
 					completedContent.WriteString("\n")
 				}
 			}
-			prompt := fmt.Sprintf(ShouldSynthesizeEarlyPromptTemplate, state.OriginalQuery, completedContent.String())
+			prompt := withDateContext(state, fmt.Sprintf(ShouldSynthesizeEarlyPromptTemplate, state.OriginalQuery, completedContent.String()))
 			messages := []*schema.Message{{Role: schema.User, Content: prompt}}
 			response, err := agent.chatModel.Generate(ctx, messages)
 			if err == nil && strings.Contains(strings.ToLower(response.Content), "true") {
@@ -338,11 +448,21 @@ func (agent *StreamingResearchAgent) buildStreamingResearchGraph(ctx context.Con
 
 	// Add edges and branches - starting directly from the checkCompletion branch
 	_ = g.AddBranch(compose.START, checkCompletionBranch)
-	_ = g.AddEdge(NodeGenerateQuestions, NodeIncrementIteration)
+	if features.planApproval {
+		_ = g.AddEdge(NodeGenerateQuestions, NodeApprovePlan)
+		_ = g.AddEdge(NodeApprovePlan, NodeIncrementIteration)
+	} else {
+		_ = g.AddEdge(NodeGenerateQuestions, NodeIncrementIteration)
+	}
 	_ = g.AddBranch(NodeSelectQuestion, selectBranch)
 	_ = g.AddEdge(NodeSearchQuestion, NodeScrapeWebContent)
 	_ = g.AddEdge(NodeScrapeWebContent, NodeAnalyzeQuestion)
-	_ = g.AddBranch(NodeAnalyzeQuestion, checkCompletionBranch)
+	if features.corroboration {
+		_ = g.AddEdge(NodeAnalyzeQuestion, NodeCorroborateClaims)
+		_ = g.AddBranch(NodeCorroborateClaims, checkCompletionBranch)
+	} else {
+		_ = g.AddBranch(NodeAnalyzeQuestion, checkCompletionBranch)
+	}
 	_ = g.AddBranch(NodeIncrementIteration, checkCompletionBranch)
 	_ = g.AddEdge(NodeSynthesizeFinalAnswer, compose.END)
 
@@ -377,7 +497,7 @@ func (agent *StreamingResearchAgent) createGenerateQuestionsNode() func(context.
 			researchedText = fmt.Sprintf("\n\n# Already researched questions (please avoid repeating)\n%s", strings.Join(researchedList, "\n"))
 		}
 
-		prompt := fmt.Sprintf(GenerateQuestionsPromptTemplate, state.OriginalQuery, researchedText)
+		prompt := withDateContext(state, fmt.Sprintf(GenerateQuestionsPromptTemplate, state.OriginalQuery, researchedText))
 
 		messages := []*schema.Message{
 			{
@@ -405,16 +525,16 @@ func (agent *StreamingResearchAgent) createGenerateQuestionsNode() func(context.
 		var newQuestions []*ResearchQuestion
 
 		// Calculate maxTotalQuestions and maxNewQuestions based on maxSteps.
-		maxTotalQuestions, maxNewQuestions := calculateMaxQuestions(researchConfig.MaxSteps, len(state.ResearchQuestions))
+		maxTotalQuestions, maxNewQuestions := calculateMaxQuestions(researchConfig.MaxSteps, state.MaxIterations, len(state.ResearchQuestions), state.features)
 
 		logging.Infof("Step allocation calculation - Max steps: %d, Steps per question: %d, Max total questions: %d, Existing questions: %d, Can add: %d",
-			researchConfig.MaxSteps, StepsPerQuestion, maxTotalQuestions, len(state.ResearchQuestions), maxNewQuestions)
+			researchConfig.MaxSteps, state.features.stepsPerQuestion(), maxTotalQuestions, len(state.ResearchQuestions), maxNewQuestions)
 
 		agent.sendThought(state, &StreamingThought{
 			Timestamp: time.Now(),
 			Stage:     StageThinking,
 			Content: fmt.Sprintf("Step allocation calculation: Max steps %d, Steps per question %d, Can research %d questions, Existing %d, Can add %d",
-				researchConfig.MaxSteps, StepsPerQuestion, maxTotalQuestions, len(state.ResearchQuestions), maxNewQuestions),
+				researchConfig.MaxSteps, state.features.stepsPerQuestion(), maxTotalQuestions, len(state.ResearchQuestions), maxNewQuestions),
 			Action: ActionStepAllocation,
 		})
 
@@ -446,6 +566,7 @@ func (agent *StreamingResearchAgent) createGenerateQuestionsNode() func(context.
 				SearchResults: make([]*tools2.SearchResponse, 0),
 				WebContents:   make([]*tools2.WebScrapeResponse, 0),
 				Analysis:      "",
+				Claims:        make([]*Claim, 0),
 				Priority:      data.Priority,
 			}
 			newQuestions = append(newQuestions, question)
@@ -466,6 +587,66 @@ func (agent *StreamingResearchAgent) createGenerateQuestionsNode() func(context.
 	}
 }
 
+// createApprovePlanNode creates a node that submits the generated research plan for approval.
+// It emits the pending questions as a plan event and pauses the run until the plan is approved,
+// edited or auto-approved after the timeout. The node is only part of the research graph when
+// plan approval is enabled; without an approval gate it passes the state through unchanged.
+// Returns a function that performs the node's logic.
+func (agent *StreamingResearchAgent) createApprovePlanNode() func(context.Context, *StreamingResearchState) (*StreamingResearchState, error) {
+	return func(ctx context.Context, state *StreamingResearchState) (*StreamingResearchState, error) {
+		gate := state.planGate
+		if gate == nil {
+			return state, nil
+		}
+
+		proposed := pendingQuestions(state)
+		if len(proposed) == 0 {
+			return state, nil
+		}
+
+		gate.open(proposed)
+		defer gate.close()
+
+		// The plan event must not be dropped, so it is sent blocking unlike regular thoughts.
+		planThought := &StreamingThought{
+			Timestamp: time.Now(),
+			Stage:     StageThinking,
+			Content:   fmt.Sprintf("Proposed a research plan with %d questions, waiting for approval", len(proposed)),
+			Action:    ActionPlanProposed,
+			Plan:      copyQuestions(proposed),
+		}
+		select {
+		case state.ThoughtChannel <- planThought:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("research plan approval was cancelled: %w", ctx.Err())
+		}
+
+		plan, timedOut, err := gate.wait(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("research plan approval was cancelled: %w", err)
+		}
+
+		content := "Research plan approved as proposed"
+		action := ActionPlanApproved
+		switch {
+		case timedOut:
+			content = fmt.Sprintf("No decision on the research plan within %s, auto-approved as proposed", gate.timeout)
+			action = ActionPlanAutoApproved
+		case plan != nil:
+			content = applyPlanEdits(state, plan, config.GetResearchConfig().MaxSteps)
+		}
+
+		logging.Infof("%s", content)
+		agent.sendThought(state, &StreamingThought{
+			Timestamp: time.Now(),
+			Stage:     StageThinking,
+			Content:   content,
+			Action:    action,
+		})
+		return state, nil
+	}
+}
+
 // createSelectQuestionNode creates a node for selecting the next research question.
 // It selects the pending question with the highest priority to be researched next,
 // following a greedy strategy.
@@ -522,35 +703,37 @@ func (agent *StreamingResearchAgent) createSearchQuestionNode() func(context.Con
 			Action:    ActionNetworkSearch,
 		})
 
-		// Build the search request.
-		searchReq := &tools2.SearchRequest{
-			Query: state.CurrentResearchQ.Question,
-		}
-
-		searchReqJSON, err := json.Marshal(searchReq)
+		// Execute the search in the question's own language, restricted to the run's recency preference.
+		searchResp, err := agent.runSearch(ctx, state.CurrentResearchQ.Question, "", state.Recency)
 		if err != nil {
-			return nil, fmt.Errorf("failed to serialize search request: %w", err)
+			return nil, err
 		}
 
-		// Execute the search.
-		resultStr, err := agent.searchTool.InvokableRun(ctx, string(searchReqJSON))
-		if err != nil {
-			return nil, fmt.Errorf("search failed: %w", err)
+		// Re-run a poor search with the engine's correction or suggestion of the query.
+		if len(searchResp.Results) < MinUsefulSearchResults {
+			searchResp = agent.refineSearch(ctx, state, searchResp)
 		}
 
-		// Deserialize the search result.
-		var searchResp tools2.SearchResponse
-		if err := json.Unmarshal([]byte(resultStr), &searchResp); err != nil {
-			return nil, fmt.Errorf("failed to deserialize search result: %w", err)
+		// Fan the search out to the configured languages, if any.
+		if len(state.SearchLanguages) > 0 {
+			searchResp = agent.searchAcrossLanguages(ctx, state, searchResp)
 		}
 
+		// Move sources published before the recency window to the end of the list.
+		staleCount := rankByRecency(searchResp.Results, state.AsOf, state.Recency)
+
 		// Update the search results for the question.
-		state.CurrentResearchQ.SearchResults = append(state.CurrentResearchQ.SearchResults, &searchResp)
+		state.CurrentResearchQ.SearchResults = append(state.CurrentResearchQ.SearchResults, searchResp)
+
+		searchSummary := fmt.Sprintf("Search complete, found %d relevant results", len(searchResp.Results))
+		if staleCount > 0 {
+			searchSummary += fmt.Sprintf(" (%d published before the %s window were down-ranked)", staleCount, state.Recency)
+		}
 
 		agent.sendThought(state, &StreamingThought{
 			Timestamp: time.Now(),
 			Stage:     StageSearching,
-			Content:   fmt.Sprintf("Search complete, found %d relevant results", len(searchResp.Results)),
+			Content:   searchSummary,
 			Action:    ActionSearchComplete,
 		})
 
@@ -559,6 +742,229 @@ func (agent *StreamingResearchAgent) createSearchQuestionNode() func(context.Con
 	}
 }
 
+// runSearch executes the search tool for a single query.
+//
+// Parameters:
+//   - ctx: A context.Context to control the search.
+//   - query: The search query.
+//   - lang: The search language, or an empty string to let the search engines decide.
+//   - timeRange: The time range to restrict the search to, or an empty string for no restriction.
+//
+// Returns:
+//   - *tools2.SearchResponse: The search response; search failures are reported through its Success field.
+//   - error: An error if the tool could not be invoked.
+func (agent *StreamingResearchAgent) runSearch(ctx context.Context, query, lang, timeRange string) (*tools2.SearchResponse, error) {
+	// Build the search request.
+	searchReq := &tools2.SearchRequest{
+		Query:     query,
+		Lang:      lang,
+		TimeRange: timeRange,
+	}
+
+	searchReqJSON, err := json.Marshal(searchReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize search request: %w", err)
+	}
+
+	// Execute the search.
+	resultStr, err := agent.searchTool.InvokableRun(ctx, string(searchReqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	// Deserialize the search result.
+	var searchResp tools2.SearchResponse
+	if err := json.Unmarshal([]byte(resultStr), &searchResp); err != nil {
+		return nil, fmt.Errorf("failed to deserialize search result: %w", err)
+	}
+
+	return &searchResp, nil
+}
+
+// refineSearch re-runs a search that returned few results with the query the engine proposed:
+// its spelling correction if there is one, otherwise its first suggestion. The results of the
+// refined query are merged into the original response; a failed refinement keeps the original.
+//
+// Parameters:
+//   - ctx: A context.Context to control the search.
+//   - state: The current research state.
+//   - original: The response of the poor search.
+//
+// Returns:
+//   - *tools2.SearchResponse: The original response with the results of the refined query merged in.
+func (agent *StreamingResearchAgent) refineSearch(ctx context.Context, state *StreamingResearchState, original *tools2.SearchResponse) *tools2.SearchResponse {
+	question := state.CurrentResearchQ.Question
+
+	var refined string
+	for _, candidate := range append(append([]string(nil), original.Corrections...), original.Suggestions...) {
+		if candidate = strings.TrimSpace(candidate); candidate != "" && !strings.EqualFold(candidate, question) {
+			refined = candidate
+			break
+		}
+	}
+	if refined == "" {
+		return original
+	}
+
+	agent.sendThought(state, &StreamingThought{
+		Timestamp: time.Now(),
+		Stage:     StageSearching,
+		Content:   fmt.Sprintf("Only %d results found, searching again for: \"%s\"", len(original.Results), refined),
+		Action:    ActionQueryRefinement,
+	})
+
+	searchResp, err := agent.runSearch(ctx, refined, "", state.Recency)
+	if err != nil || !searchResp.Success {
+		logging.Warnf("Refined search failed for query %q", refined)
+		return original
+	}
+
+	return mergeSearchResponses([]*tools2.SearchResponse{original, searchResp}, agent.canon)
+}
+
+// searchAcrossLanguages translates the current question into the run's search languages,
+// searches each translation with the matching language, and merges the results into the
+// response of the original search. Every merged result records its source language in
+// its metadata. Translation or search failures only reduce the fan-out; the original
+// results are always kept.
+//
+// Parameters:
+//   - ctx: A context.Context to control the translation and the searches.
+//   - state: The current research state.
+//   - original: The response of the search in the question's own language.
+//
+// Returns:
+//   - *tools2.SearchResponse: The original response with the results of all languages merged in.
+func (agent *StreamingResearchAgent) searchAcrossLanguages(ctx context.Context, state *StreamingResearchState, original *tools2.SearchResponse) *tools2.SearchResponse {
+	question := state.CurrentResearchQ.Question
+
+	agent.sendThought(state, &StreamingThought{
+		Timestamp: time.Now(),
+		Stage:     StageSearching,
+		Content:   fmt.Sprintf("Translating the search query into: %s", strings.Join(state.SearchLanguages, ", ")),
+		Action:    ActionQueryTranslation,
+	})
+
+	prompt := withDateContext(state, fmt.Sprintf(TranslateQueryPromptTemplate, question, strings.Join(state.SearchLanguages, ", ")))
+	messages := []*schema.Message{
+		{
+			Role:    schema.User,
+			Content: prompt,
+		},
+	}
+
+	response, err := agent.chatModel.Generate(ctx, messages)
+	if err != nil {
+		logging.Warnf("Failed to translate search query: %v", err)
+		return original
+	}
+
+	// Parse the JSON response.
+	var translation struct {
+		SourceLanguage string `json:"source_language"`
+		Queries        []struct {
+			Lang  string `json:"lang"`
+			Query string `json:"query"`
+		} `json:"queries"`
+	}
+
+	if err := json.Unmarshal([]byte(response.Content), &translation); err != nil {
+		logging.Warnf("Failed to parse search query translation JSON: %v", err)
+		return original
+	}
+
+	// Tag the results of the original search with the question's language.
+	tagSourceLanguage(original.Results, translation.SourceLanguage)
+
+	responses := []*tools2.SearchResponse{original}
+	for _, translated := range translation.Queries {
+		query := strings.TrimSpace(translated.Query)
+		// Skip the question's own language, which the original search already covers.
+		if query == "" || query == question || strings.EqualFold(translated.Lang, translation.SourceLanguage) {
+			continue
+		}
+
+		searchResp, err := agent.runSearch(ctx, query, translated.Lang, state.Recency)
+		if err != nil || !searchResp.Success {
+			logging.Warnf("Search in language %s failed for query %q", translated.Lang, query)
+			continue
+		}
+
+		tagSourceLanguage(searchResp.Results, translated.Lang)
+		responses = append(responses, searchResp)
+
+		agent.sendThought(state, &StreamingThought{
+			Timestamp: time.Now(),
+			Stage:     StageSearching,
+			Content:   fmt.Sprintf("Searched in %s for: \"%s\", found %d results", translated.Lang, query, len(searchResp.Results)),
+			Action:    ActionNetworkSearch,
+		})
+	}
+
+	return mergeSearchResponses(responses, agent.canon)
+}
+
+// tagSourceLanguage records the language a search result was found in.
+func tagSourceLanguage(results []*search.SearchResultItem, lang string) {
+	if lang == "" {
+		return
+	}
+	for _, result := range results {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
+		result.Metadata["source_lang"] = lang
+	}
+}
+
+// mergeSearchResponses merges the results and direct answers of several searches into the first response.
+// Results are interleaved round-robin so that every search is represented at the top of
+// the list, and URLs with the same canonical form are kept only once.
+func mergeSearchResponses(responses []*tools2.SearchResponse, canon *urlcanon.Canonicalizer) *tools2.SearchResponse {
+	merged := responses[0]
+	if len(responses) == 1 {
+		return merged
+	}
+
+	var results []*search.SearchResultItem
+	seenURLs := make(map[string]bool)
+	for i := 0; ; i++ {
+		added := false
+		for _, resp := range responses {
+			if i >= len(resp.Results) {
+				continue
+			}
+			added = true
+
+			result := resp.Results[i]
+			urlKey := canon.Key(result.URL)
+			if result.URL != "" && seenURLs[urlKey] {
+				continue
+			}
+			seenURLs[urlKey] = true
+			results = append(results, result)
+		}
+		if !added {
+			break
+		}
+	}
+
+	// Keep the direct answers of every search as evidence.
+	for _, resp := range responses[1:] {
+		merged.Answers = append(merged.Answers, resp.Answers...)
+		merged.Infoboxes = append(merged.Infoboxes, resp.Infoboxes...)
+	}
+
+	// A failed original search is recovered by the results of the other searches.
+	if len(results) > 0 {
+		merged.Success = true
+		merged.Error = ""
+	}
+	merged.Results = results
+	merged.TotalCount = len(results)
+	return merged
+}
+
 // createScrapeWebContentNode creates a node for scraping web content.
 // It extracts URLs from search results and uses the web scraping tool to get detailed content.
 // It supports multiple scraping adapters (Firecrawl, Jina, etc.) and limits the number of scrapes to avoid overload.
@@ -576,13 +982,25 @@ func (agent *StreamingResearchAgent) createScrapeWebContentNode() func(context.C
 			Action:    ActionWebScraping,
 		})
 
-		// Get URLs from the latest search results.
-		latestSearch := state.CurrentResearchQ.SearchResults[len(state.CurrentResearchQ.SearchResults)-1]
-		var urls []string
-		for _, item := range latestSearch.Results {
-			if item.URL != "" {
-				urls = append(urls, item.URL)
-			}
+		urls, provided := agent.scrapeTargets(state.CurrentResearchQ)
+		if len(provided) > 0 {
+			state.CurrentResearchQ.WebContents = append(state.CurrentResearchQ.WebContents, &tools2.WebScrapeResponse{
+				Success: true,
+				Message: "content provided by the search engine",
+				Results: provided,
+			})
+
+			agent.sendThought(state, &StreamingThought{
+				Timestamp: time.Now(),
+				Stage:     StageAnalyzing,
+				Content:   fmt.Sprintf("Reused the content of %d pages from the search results instead of scraping them", len(provided)),
+				Action:    ActionReuseSearchContent,
+			})
+			logging.Infof("Reused search content - saved %d scrapes", len(provided))
+		}
+
+		if len(urls) == 0 && len(provided) > 0 {
+			return state, nil
 		}
 
 		if len(urls) == 0 {
@@ -621,6 +1039,23 @@ func (agent *StreamingResearchAgent) createScrapeWebContentNode() func(context.C
 		// Update the web content for the question.
 		state.CurrentResearchQ.WebContents = append(state.CurrentResearchQ.WebContents, &webResp)
 
+		// Report the pages that robots.txt disallows, rather than dropping them silently.
+		var disallowed []string
+		for _, failure := range webResp.Failures {
+			if failure.Kind == string(web.ScrapeErrorDisallowed) {
+				disallowed = append(disallowed, failure.URL)
+			}
+		}
+		if len(disallowed) > 0 {
+			agent.sendThought(state, &StreamingThought{
+				Timestamp: time.Now(),
+				Stage:     StageAnalyzing,
+				Content:   fmt.Sprintf("Skipped %d pages that robots.txt disallows: %s", len(disallowed), strings.Join(disallowed, ", ")),
+				Action:    ActionScrapingDisallowed,
+			})
+			logging.Infof("Web scraping skipped %d pages disallowed by robots.txt", len(disallowed))
+		}
+
 		agent.sendThought(state, &StreamingThought{
 			Timestamp: time.Now(),
 			Stage:     StageAnalyzing,
@@ -633,6 +1068,44 @@ func (agent *StreamingResearchAgent) createScrapeWebContentNode() func(context.C
 	}
 }
 
+// scrapeTargets splits the results of the latest search of a question into the URLs to scrape and
+// the pages whose content the search engine already returned, e.g., Firecrawl search with scrape options.
+// Pages already scraped for the question, pages that robots.txt disallows and duplicate URLs are skipped.
+func (agent *StreamingResearchAgent) scrapeTargets(question *ResearchQuestion) ([]string, []*web.WebContent) {
+	scraped := make(map[string]bool)
+	for _, webBatch := range question.WebContents {
+		for _, content := range webBatch.Results {
+			scraped[agent.canon.Key(content.URL)] = true
+		}
+		for _, failure := range webBatch.Failures {
+			if failure.Kind == string(web.ScrapeErrorDisallowed) {
+				scraped[agent.canon.Key(failure.URL)] = true
+			}
+		}
+	}
+
+	latestSearch := question.SearchResults[len(question.SearchResults)-1]
+	var urls []string
+	var provided []*web.WebContent
+	for _, item := range latestSearch.Results {
+		if item.URL == "" {
+			continue
+		}
+		urlKey := agent.canon.Key(item.URL)
+		if scraped[urlKey] {
+			continue
+		}
+		scraped[urlKey] = true
+
+		if item.HasContent() {
+			provided = append(provided, &web.WebContent{URL: item.URL, Title: item.Title, Content: item.Content})
+			continue
+		}
+		urls = append(urls, item.URL)
+	}
+	return urls, provided
+}
+
 // createAnalyzeQuestionNode creates a node for analyzing the gathered information.
 // It uses the LLM to perform an in-depth analysis of the collected web content and generate a detailed answer.
 // It includes a content truncation mechanism to stay within model context limits and requires source citation.
@@ -654,42 +1127,8 @@ func (agent *StreamingResearchAgent) createAnalyzeQuestionNode() func(context.Co
 		})
 
 		// Build analysis prompt, limiting content length.
-		var contentBuilder strings.Builder
-		contentBuilder.WriteString("# Research Question\n")
-		contentBuilder.WriteString(state.CurrentResearchQ.Question)
-		contentBuilder.WriteString("\n\n# Collected Web Content\n")
-
-		// Add web content, but limit total length.
-		currentLength := 0
-		webPageIndex := 1
-
-		for _, webBatch := range state.CurrentResearchQ.WebContents {
-			for _, content := range webBatch.Results {
-				if currentLength >= researchConfig.MaxContentLength {
-					contentBuilder.WriteString("\nNote: Due to excessive content, only a portion of the web content is displayed.\n")
-					break
-				}
-
-				// If a single web page's content is too long, truncate it.
-				truncatedContent := content.Content
-				if len(truncatedContent) > researchConfig.MaxSingleContent {
-					truncatedContent = truncatedContent[:researchConfig.MaxSingleContent] + "...(content truncated)"
-				}
-
-				entryContent := fmt.Sprintf("## Source Web Page %d\n**Link**: %s\n**Title**: %s\n**Content**:\n%s\n\n---\n\n",
-					webPageIndex, content.URL, content.Title, truncatedContent)
-
-				contentBuilder.WriteString(entryContent)
-				currentLength += len(entryContent)
-				webPageIndex++
-			}
-
-			if currentLength >= researchConfig.MaxContentLength {
-				break
-			}
-		}
-
-		analyzePrompt := fmt.Sprintf(AnalyzeQuestionPromptTemplate, contentBuilder.String())
+		webContext := buildWebContentContext(state.CurrentResearchQ, researchConfig.MaxContentLength, researchConfig.MaxSingleContent)
+		analyzePrompt := withDateContext(state, fmt.Sprintf(AnalyzeQuestionPromptTemplate, state.CurrentResearchQ.Question, webContext))
 
 		messages := []*schema.Message{
 			{
@@ -739,10 +1178,96 @@ func (agent *StreamingResearchAgent) createAnalyzeQuestionNode() func(context.Co
 			Action:    ActionAnalysisComplete,
 		})
 
+		logging.Infof("Analysis complete - Completed questions: %d", state.CompletedQuestions)
+
+		// Clear the current question to prepare for the next one, unless the corroboration node still needs it.
+		if !state.features.corroboration {
+			state.CurrentResearchQ = nil
+		}
+		return state, nil
+	}
+}
+
+// createCorroborateClaimsNode creates a node for cross-checking the analysis against its sources.
+// It uses the LLM to extract atomic claims from the analysis of the current question and to decide
+// which scraped sources support or contradict each claim. Claims backed by too few sources,
+// or by conflicting sources, are flagged so that the synthesis step can treat them with caution.
+// Corroboration is best-effort: a failure is logged and does not abort the research.
+// Returns a function that performs the node's logic and releases the current question.
+func (agent *StreamingResearchAgent) createCorroborateClaimsNode() func(context.Context, *StreamingResearchState) (*StreamingResearchState, error) {
+	return func(ctx context.Context, state *StreamingResearchState) (*StreamingResearchState, error) {
+		question := state.CurrentResearchQ
+		if question == nil {
+			return state, nil
+		}
+
 		// Clear the current question to prepare for the next one.
-		state.CurrentResearchQ = nil
+		defer func() {
+			state.CurrentResearchQ = nil
+		}()
 
-		logging.Infof("Analysis complete - Completed questions: %d", state.CompletedQuestions)
+		// Get research configuration.
+		researchConfig := config.GetResearchConfig()
+
+		sourceContext := buildWebContentContext(question, researchConfig.MaxContentLength, researchConfig.MaxSingleContent)
+		if question.Analysis == "" || sourceContext == "" {
+			logging.Infof("Skipping claim corroboration for question without analysis or sources: %s", question.Question)
+			return state, nil
+		}
+
+		agent.sendThought(state, &StreamingThought{
+			Timestamp: time.Now(),
+			Stage:     StageAnalyzing,
+			Content:   fmt.Sprintf("Cross-checking the claims of the analysis against the sources for: %s", question.Question),
+			Action:    ActionClaimCorroboration,
+		})
+
+		prompt := withDateContext(state, fmt.Sprintf(CorroborateClaimsPromptTemplate, question.Analysis, sourceContext))
+		messages := []*schema.Message{
+			{
+				Role:    schema.User,
+				Content: prompt,
+			},
+		}
+
+		response, err := agent.chatModel.Generate(ctx, messages)
+		if err != nil {
+			logging.Warnf("Claim corroboration failed: %v", err)
+			return state, nil
+		}
+
+		// Only URLs that were actually shown to the model count as evidence.
+		knownURLs := make(map[string]string)
+		for _, webBatch := range question.WebContents {
+			for _, content := range webBatch.Results {
+				knownURLs[agent.canon.Key(content.URL)] = content.URL
+			}
+		}
+
+		claims, err := parseClaims(response.Content, knownURLs, agent.canon, researchConfig.MinCorroboratingSources)
+		if err != nil {
+			logging.Warnf("Failed to parse claim corroboration JSON: %v", err)
+			return state, nil
+		}
+
+		var flagged []*Claim
+		question.Claims = claims
+		for _, claim := range claims {
+			if claim.IsFlagged() {
+				flagged = append(flagged, claim)
+			}
+		}
+
+		agent.sendThought(state, &StreamingThought{
+			Timestamp: time.Now(),
+			Stage:     StageAnalyzing,
+			Content: fmt.Sprintf("Corroboration complete: checked %d claims, %d flagged as single-source or conflicting",
+				len(question.Claims), len(flagged)),
+			Action: ActionCorroborationDone,
+			Claims: flagged,
+		})
+
+		logging.Infof("Corroboration complete - Claims: %d, Flagged: %d", len(question.Claims), len(flagged))
 		return state, nil
 	}
 }
@@ -770,11 +1295,12 @@ func (agent *StreamingResearchAgent) createSynthesizeFinalAnswerNode() func(cont
 			if q.Status == QuestionStatusCompleted && q.Analysis != "" {
 				contentBuilder.WriteString(fmt.Sprintf("## Research Question %d: %s\n", i+1, q.Question))
 				contentBuilder.WriteString(q.Analysis)
+				contentBuilder.WriteString(formatFlaggedClaims(q.Claims))
 				contentBuilder.WriteString("\n\n---\n\n")
 			}
 		}
 
-		synthesizePrompt := fmt.Sprintf(SynthesizeFinalAnswerPromptTemplate, contentBuilder.String())
+		synthesizePrompt := withDateContext(state, fmt.Sprintf(SynthesizeFinalAnswerPromptTemplate, state.OriginalQuery, contentBuilder.String()))
 
 		messages := []*schema.Message{
 			{
@@ -898,22 +1424,24 @@ func (agent *StreamingResearchAgent) sendThought(state *StreamingResearchState,
 //
 // Parameters:
 //   - maxSteps: The maximum number of steps.
+//   - maxIterations: The maximum number of iterations.
 //   - currentQuestionCount: The current number of questions.
+//   - features: The optional stages of the run, which add steps per question or per iteration.
 //
 // Returns:
 //   - maxTotalQuestions: The total maximum number of questions.
 //   - maxNewQuestions: The maximum number of new questions that can be added.
-func calculateMaxQuestions(maxSteps, currentQuestionCount int) (int, int) {
-	// Reserve some steps for generating questions, iterating, synthesizing, etc.
-	reservedSteps := 5
-	availableSteps := maxSteps - reservedSteps
+func calculateMaxQuestions(maxSteps, maxIterations, currentQuestionCount int, features researchFeatures) (int, int) {
+	// Reserve some steps for generating questions, approving plans, iterating, synthesizing, etc.
+	availableSteps := maxSteps - features.reservedSteps(maxIterations)
 
 	// Ensure there are enough steps to perform basic operations.
-	if availableSteps < StepsPerQuestion {
+	stepsPerQuestion := features.stepsPerQuestion()
+	if availableSteps < stepsPerQuestion {
 		return 0, 0
 	}
 
-	maxTotalQuestions := availableSteps / StepsPerQuestion
+	maxTotalQuestions := availableSteps / stepsPerQuestion
 	maxNewQuestions := maxTotalQuestions - currentQuestionCount
 
 	if maxNewQuestions < 0 {
@@ -923,8 +1451,320 @@ func calculateMaxQuestions(maxSteps, currentQuestionCount int) (int, int) {
 	return maxTotalQuestions, maxNewQuestions
 }
 
+// buildWebContentContext renders the instant answers and scraped web pages of a question as prompt context.
+// It truncates each page to maxSingleContent and stops adding pages once maxContentLength is reached.
+//
+// Parameters:
+//   - question: The research question whose web contents are rendered.
+//   - maxContentLength: The maximum total length of the rendered context.
+//   - maxSingleContent: The maximum length of a single page's content.
+//
+// Returns:
+//   - string: The rendered context, or an empty string if the question has no answers or web content.
+func buildWebContentContext(question *ResearchQuestion, maxContentLength, maxSingleContent int) string {
+	var contentBuilder strings.Builder
+
+	// Instant answers and infoboxes from the search engines come first, as they are short and direct.
+	contentBuilder.WriteString(buildInstantAnswerContext(question))
+
+	// Look up publish dates and source languages from the search results, as scraped pages do not carry them.
+	publishDates := make(map[string]string)
+	sourceLanguages := make(map[string]string)
+	for _, searchBatch := range question.SearchResults {
+		for _, result := range searchBatch.Results {
+			if t, ok := search.ParsePublishDate(result.PublishDate); ok {
+				publishDates[result.URL] = t.Format("2006-01-02")
+			}
+			if lang, ok := result.Metadata["source_lang"].(string); ok {
+				sourceLanguages[result.URL] = lang
+			}
+		}
+	}
+
+	// Add web content, but limit total length.
+	currentLength := contentBuilder.Len()
+	webPageIndex := 1
+
+	for _, webBatch := range question.WebContents {
+		for _, content := range webBatch.Results {
+			if currentLength >= maxContentLength {
+				contentBuilder.WriteString("\nNote: Due to excessive content, only a portion of the web content is displayed.\n")
+				break
+			}
+
+			// If a single web page's content is too long, truncate it.
+			truncatedContent := content.Content
+			if len(truncatedContent) > maxSingleContent {
+				truncatedContent = truncatedContent[:maxSingleContent] + "...(content truncated)"
+			}
+
+			publishDate, ok := publishDates[content.URL]
+			if !ok {
+				publishDate = "unknown"
+			}
+
+			languageLine := ""
+			if lang, ok := sourceLanguages[content.URL]; ok {
+				languageLine = fmt.Sprintf("**Language**: %s\n", lang)
+			}
+
+			entryContent := fmt.Sprintf("## Source Web Page %d\n**Link**: %s\n**Title**: %s\n**Published**: %s\n%s**Content**:\n%s\n\n---\n\n",
+				webPageIndex, content.URL, content.Title, publishDate, languageLine, truncatedContent)
+
+			contentBuilder.WriteString(entryContent)
+			currentLength += len(entryContent)
+			webPageIndex++
+		}
+
+		if currentLength >= maxContentLength {
+			break
+		}
+	}
+
+	return contentBuilder.String()
+}
+
+// buildInstantAnswerContext renders the direct answers and infoboxes returned by the search engines
+// for a question, each once, or an empty string if there are none.
+func buildInstantAnswerContext(question *ResearchQuestion) string {
+	var builder strings.Builder
+	seen := make(map[string]bool)
+	index := 1
+
+	for _, searchBatch := range question.SearchResults {
+		for _, answer := range searchBatch.Answers {
+			if seen[answer.Answer] {
+				continue
+			}
+			seen[answer.Answer] = true
+
+			source := answer.URL
+			if source == "" {
+				source = answer.Engine
+			}
+			if source == "" {
+				source = "unknown"
+			}
+			builder.WriteString(fmt.Sprintf("## Instant Answer %d\n**Source**: %s\n**Answer**: %s\n\n---\n\n", index, source, answer.Answer))
+			index++
+		}
+
+		for _, infobox := range searchBatch.Infoboxes {
+			if seen[infobox.Title] {
+				continue
+			}
+			seen[infobox.Title] = true
+
+			builder.WriteString(fmt.Sprintf("## Infobox %d: %s\n", index, infobox.Title))
+			if infobox.URL != "" {
+				builder.WriteString(fmt.Sprintf("**Link**: %s\n", infobox.URL))
+			}
+			if infobox.Content != "" {
+				builder.WriteString(fmt.Sprintf("**Summary**: %s\n", infobox.Content))
+			}
+			for _, attribute := range infobox.Attributes {
+				builder.WriteString(fmt.Sprintf("- %s: %s\n", attribute.Label, attribute.Value))
+			}
+			builder.WriteString("\n---\n\n")
+			index++
+		}
+	}
+
+	return builder.String()
+}
+
+// withDateContext prepends the date the run treats as today to a prompt.
+func withDateContext(state *StreamingResearchState, prompt string) string {
+	asOf := state.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	return fmt.Sprintf(DateContextPromptTemplate, asOf.Format("Monday, January 2, 2006")) + prompt
+}
+
+// rankByRecency moves the results published before the recency window to the end of the list,
+// keeping the relative order of the remaining results, and marks them as stale in their metadata.
+// Results without a known publish date are not treated as stale.
+//
+// Parameters:
+//   - results: The search results to reorder in place.
+//   - asOf: The date the run treats as today.
+//   - recency: The recency preference of the run; no reordering happens if it is empty or unknown.
+//
+// Returns:
+//   - int: The number of stale results.
+func rankByRecency(results []*search.SearchResultItem, asOf time.Time, recency string) int {
+	window, ok := search.TimeRangeDuration(recency)
+	if !ok {
+		return 0
+	}
+
+	cutoff := asOf.Add(-window)
+	staleCount := 0
+	isStale := make(map[*search.SearchResultItem]bool)
+	for _, result := range results {
+		if published, ok := search.ParsePublishDate(result.PublishDate); ok && published.Before(cutoff) {
+			if result.Metadata == nil {
+				result.Metadata = make(map[string]interface{})
+			}
+			result.Metadata["stale"] = true
+			isStale[result] = true
+			staleCount++
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return !isStale[results[i]] && isStale[results[j]]
+	})
+
+	return staleCount
+}
+
+// parseClaims parses the claims of a corroboration response and flags the weakly corroborated ones.
+// Source URLs the model was not shown are dropped, and claims without text are skipped.
+//
+// Parameters:
+//   - content: The JSON response of the model.
+//   - knownURLs: The URLs of the sources shown to the model, keyed by canonical key.
+//   - canon: The canonicalizer the known URLs are keyed with.
+//   - minSources: The number of supporting sources a claim needs; DefaultMinCorroboratingSources if not positive.
+//
+// Returns:
+//   - []*Claim: The claims, in response order.
+//   - error: An error if the response is not valid JSON.
+func parseClaims(content string, knownURLs map[string]string, canon *urlcanon.Canonicalizer, minSources int) ([]*Claim, error) {
+	var claimData []struct {
+		Claim                string   `json:"claim"`
+		SupportingSources    []string `json:"supporting_sources"`
+		ContradictingSources []string `json:"contradicting_sources"`
+	}
+	if err := json.Unmarshal([]byte(content), &claimData); err != nil {
+		return nil, err
+	}
+
+	if minSources <= 0 {
+		minSources = DefaultMinCorroboratingSources
+	}
+
+	claims := make([]*Claim, 0, len(claimData))
+	for _, data := range claimData {
+		if strings.TrimSpace(data.Claim) == "" {
+			continue
+		}
+
+		claim := &Claim{
+			Claim:                data.Claim,
+			SupportingSources:    filterKnownURLs(data.SupportingSources, knownURLs, canon),
+			ContradictingSources: filterKnownURLs(data.ContradictingSources, knownURLs, canon),
+		}
+		claim.SingleSource = len(claim.SupportingSources) < minSources
+		claim.Conflicting = len(claim.ContradictingSources) > 0
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
+
+// filterKnownURLs keeps only the URLs whose canonical key belongs to the given set, dropping duplicates.
+// The known set maps canonical keys to URLs; the known spelling of each URL is returned.
+func filterKnownURLs(urls []string, known map[string]string, canon *urlcanon.Canonicalizer) []string {
+	result := make([]string, 0, len(urls))
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		urlKey := canon.Key(url)
+		if knownURL, ok := known[urlKey]; ok && !seen[urlKey] {
+			seen[urlKey] = true
+			result = append(result, knownURL)
+		}
+	}
+	return result
+}
+
+// formatFlaggedClaims renders the weakly corroborated claims of a question for the synthesis prompt.
+// It returns an empty string if none of the claims are flagged.
+func formatFlaggedClaims(claims []*Claim) string {
+	var builder strings.Builder
+	for _, claim := range claims {
+		if !claim.IsFlagged() {
+			continue
+		}
+		if builder.Len() == 0 {
+			builder.WriteString("\n\n### Claims Requiring Caution\n")
+		}
+		if claim.Conflicting {
+			builder.WriteString(fmt.Sprintf("- [conflicting] %s (supported by: %s; contradicted by: %s)\n",
+				claim.Claim, joinOrNone(claim.SupportingSources), joinOrNone(claim.ContradictingSources)))
+		} else {
+			builder.WriteString(fmt.Sprintf("- [single source] %s (supported by: %s)\n",
+				claim.Claim, joinOrNone(claim.SupportingSources)))
+		}
+	}
+	return builder.String()
+}
+
+// joinOrNone joins URLs with commas, or returns "none" for an empty list.
+func joinOrNone(urls []string) string {
+	if len(urls) == 0 {
+		return "none"
+	}
+	return strings.Join(urls, ", ")
+}
+
+// extractFlaggedClaims collects the claims of all completed questions that were flagged during corroboration.
+//
+// Parameters:
+//   - state: The current research state, containing all research questions.
+//
+// Returns:
+//   - []*Claim: The single-source or conflicting claims, in question order.
+func (agent *StreamingResearchAgent) extractFlaggedClaims(state *StreamingResearchState) []*Claim {
+	var flagged []*Claim
+
+	for _, q := range state.ResearchQuestions {
+		if q.Status != QuestionStatusCompleted {
+			continue
+		}
+		for _, claim := range q.Claims {
+			if claim.IsFlagged() {
+				flagged = append(flagged, claim)
+			}
+		}
+	}
+
+	return flagged
+}
+
+// extractSourceLanguages collects the language each source of the completed questions was found in.
+//
+// Parameters:
+//   - state: The current research state, containing all research questions.
+//
+// Returns:
+//   - map[string]string: The source language keyed by URL, or nil if no language was recorded.
+func (agent *StreamingResearchAgent) extractSourceLanguages(state *StreamingResearchState) map[string]string {
+	var languages map[string]string
+
+	for _, q := range state.ResearchQuestions {
+		if q.Status != QuestionStatusCompleted {
+			continue
+		}
+		for _, searchResult := range q.SearchResults {
+			for _, result := range searchResult.Results {
+				if lang, ok := result.Metadata["source_lang"].(string); ok && result.URL != "" {
+					if languages == nil {
+						languages = make(map[string]string)
+					}
+					languages[result.URL] = lang
+				}
+			}
+		}
+	}
+
+	return languages
+}
+
 // extractSources extracts the sources of information.
-// It collects the source URLs from all completed research questions for citation in the final answer.
+// It collects the source URLs from all completed research questions for citation in the final answer,
+// in canonical form so that different spellings of the same page are cited once.
 //
 // Parameters:
 //   - state: The current research state, containing all research questions.
@@ -933,14 +1773,21 @@ func calculateMaxQuestions(maxSteps, currentQuestionCount int) (int, int) {
 //   - []string: A deduplicated list of source URLs.
 func (agent *StreamingResearchAgent) extractSources(state *StreamingResearchState) []string {
 	var sources []string
+	seen := make(map[string]bool)
 
 	for _, q := range state.ResearchQuestions {
 		if q.Status == QuestionStatusCompleted {
 			for _, searchResult := range q.SearchResults {
 				for _, result := range searchResult.Results {
-					if result.URL != "" {
-						sources = append(sources, result.URL)
+					if result.URL == "" {
+						continue
+					}
+					urlKey := agent.canon.Key(result.URL)
+					if seen[urlKey] {
+						continue
 					}
+					seen[urlKey] = true
+					sources = append(sources, result.URL)
 				}
 			}
 		}
@@ -0,0 +1,250 @@
+package agent
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/adapters/web"
+	tools2 "github.com/anboat/strato-sdk/core/tools"
+	"github.com/anboat/strato-sdk/pkg/urlcanon"
+)
+
+// knownURLsFor keys the URLs by their canonical key, as the corroboration node does.
+func knownURLsFor(canon *urlcanon.Canonicalizer, urls ...string) map[string]string {
+	known := make(map[string]string, len(urls))
+	for _, url := range urls {
+		known[canon.Key(url)] = url
+	}
+	return known
+}
+
+func TestParseClaims(t *testing.T) {
+	canon := urlcanon.New(nil)
+	known := knownURLsFor(canon, "https://a.example/1", "https://b.example/2", "https://c.example/3")
+
+	content := `[
+		{"claim": "Two sources agree", "supporting_sources": ["https://a.example/1", "https://b.example/2"], "contradicting_sources": []},
+		{"claim": "Only one source", "supporting_sources": ["https://a.example/1"]},
+		{"claim": "Sources disagree", "supporting_sources": ["https://a.example/1", "https://b.example/2"], "contradicting_sources": ["https://c.example/3"]},
+		{"claim": "Invented and duplicate sources", "supporting_sources": ["https://invented.example/", "http://a.example/1", "https://a.example/1"]},
+		{"claim": "   ", "supporting_sources": ["https://a.example/1", "https://b.example/2"]},
+		{"claim": "No sources"}
+	]`
+
+	claims, err := parseClaims(content, known, canon, 2)
+	if err != nil {
+		t.Fatalf("parseClaims() error = %v", err)
+	}
+
+	want := []*Claim{
+		{Claim: "Two sources agree", SupportingSources: []string{"https://a.example/1", "https://b.example/2"}, ContradictingSources: []string{}},
+		{Claim: "Only one source", SupportingSources: []string{"https://a.example/1"}, ContradictingSources: []string{}, SingleSource: true},
+		{Claim: "Sources disagree", SupportingSources: []string{"https://a.example/1", "https://b.example/2"}, ContradictingSources: []string{"https://c.example/3"}, Conflicting: true},
+		{Claim: "Invented and duplicate sources", SupportingSources: []string{"https://a.example/1"}, ContradictingSources: []string{}, SingleSource: true},
+		{Claim: "No sources", SupportingSources: []string{}, ContradictingSources: []string{}, SingleSource: true},
+	}
+	if !reflect.DeepEqual(claims, want) {
+		for i, claim := range claims {
+			t.Logf("claims[%d] = %+v", i, *claim)
+		}
+		t.Errorf("parseClaims() returned %d claims, want %d as listed", len(claims), len(want))
+	}
+}
+
+func TestParseClaimsMinSources(t *testing.T) {
+	canon := urlcanon.New(nil)
+	known := knownURLsFor(canon, "https://a.example/1", "https://b.example/2")
+	content := `[{"claim": "Two sources", "supporting_sources": ["https://a.example/1", "https://b.example/2"]}]`
+
+	tests := []struct {
+		name       string
+		minSources int
+		want       bool
+	}{
+		{"default", 0, false},
+		{"one", 1, false},
+		{"three", 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := parseClaims(content, known, canon, tt.minSources)
+			if err != nil {
+				t.Fatalf("parseClaims() error = %v", err)
+			}
+			if got := claims[0].SingleSource; got != tt.want {
+				t.Errorf("parseClaims() SingleSource = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClaimsInvalidJSON(t *testing.T) {
+	for _, content := range []string{"", "not json", `{"claim": "an object"}`, "```json\n[]\n```"} {
+		if _, err := parseClaims(content, nil, urlcanon.New(nil), 2); err == nil {
+			t.Errorf("parseClaims(%q) error = nil, want an error", content)
+		}
+	}
+}
+
+func TestClaimIsFlagged(t *testing.T) {
+	tests := []struct {
+		claim Claim
+		want  bool
+	}{
+		{Claim{}, false},
+		{Claim{SingleSource: true}, true},
+		{Claim{Conflicting: true}, true},
+		{Claim{SingleSource: true, Conflicting: true}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.claim.IsFlagged(); got != tt.want {
+			t.Errorf("IsFlagged() of %+v = %v, want %v", tt.claim, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFlaggedClaims(t *testing.T) {
+	if got := formatFlaggedClaims([]*Claim{{Claim: "fine", SupportingSources: []string{"a", "b"}}}); got != "" {
+		t.Errorf("formatFlaggedClaims() without flagged claims = %q, want empty", got)
+	}
+
+	got := formatFlaggedClaims([]*Claim{
+		{Claim: "fine"},
+		{Claim: "lonely", SupportingSources: []string{"https://a.example"}, SingleSource: true},
+		{Claim: "disputed", ContradictingSources: []string{"https://c.example"}, SingleSource: true, Conflicting: true},
+	})
+	want := "\n\n### Claims Requiring Caution\n" +
+		"- [single source] lonely (supported by: https://a.example)\n" +
+		"- [conflicting] disputed (supported by: none; contradicted by: https://c.example)\n"
+	if got != want {
+		t.Errorf("formatFlaggedClaims() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractFlaggedClaims(t *testing.T) {
+	single := &Claim{Claim: "single", SingleSource: true}
+	conflicting := &Claim{Claim: "conflicting", Conflicting: true}
+	pending := &Claim{Claim: "pending", SingleSource: true}
+
+	state := &StreamingResearchState{ResearchQuestions: []*ResearchQuestion{
+		{ID: "q_0_1", Status: QuestionStatusCompleted, Claims: []*Claim{{Claim: "corroborated"}, single}},
+		{ID: "q_0_2", Status: QuestionStatusPending, Claims: []*Claim{pending}},
+		{ID: "q_0_3", Status: QuestionStatusCompleted},
+		{ID: "q_1_1", Status: QuestionStatusCompleted, Claims: []*Claim{conflicting}},
+	}}
+
+	agent := &StreamingResearchAgent{}
+	got := agent.extractFlaggedClaims(state)
+	if want := []*Claim{single, conflicting}; !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFlaggedClaims() = %v, want %v", claimTexts(got), claimTexts(want))
+	}
+
+	if got := agent.extractFlaggedClaims(&StreamingResearchState{}); got != nil {
+		t.Errorf("extractFlaggedClaims() without questions = %v, want nil", claimTexts(got))
+	}
+}
+
+// claimTexts returns the texts of the claims, for readable test failures.
+func claimTexts(claims []*Claim) string {
+	texts := make([]string, len(claims))
+	for i, claim := range claims {
+		texts[i] = claim.Claim
+	}
+	return "[" + strings.Join(texts, ", ") + "]"
+}
+
+func TestCalculateMaxQuestions(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxSteps   int
+		iterations int
+		existing   int
+		features   researchFeatures
+		wantTotal  int
+		wantNew    int
+	}{
+		{"default stages", 47, 3, 0, researchFeatures{}, 7, 7},
+		{"corroboration costs a step per question", 47, 3, 0, researchFeatures{corroboration: true}, 6, 6},
+		{"plan approval costs a step per iteration", 47, 3, 0, researchFeatures{planApproval: true}, 6, 6},
+		{"plan approval with more iterations", 47, 9, 0, researchFeatures{planApproval: true}, 5, 5},
+		{"existing questions", 47, 3, 4, researchFeatures{}, 7, 3},
+		{"more questions than fit", 47, 3, 9, researchFeatures{}, 7, 0},
+		{"too few steps", 10, 3, 0, researchFeatures{}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, added := calculateMaxQuestions(tt.maxSteps, tt.iterations, tt.existing, tt.features)
+			if total != tt.wantTotal || added != tt.wantNew {
+				t.Errorf("calculateMaxQuestions() = %d, %d, want %d, %d", total, added, tt.wantTotal, tt.wantNew)
+			}
+		})
+	}
+}
+
+func TestWithCorroboration(t *testing.T) {
+	if applyResearchOptions().features().corroboration {
+		t.Error("corroboration is enabled by default")
+	}
+	if !applyResearchOptions(WithCorroboration(true)).features().corroboration {
+		t.Error("WithCorroboration(true) did not enable corroboration")
+	}
+	if applyResearchOptions(WithCorroboration(true), WithCorroboration(false)).features().corroboration {
+		t.Error("WithCorroboration(false) did not disable corroboration")
+	}
+}
+
+func TestScrapeTargets(t *testing.T) {
+	agent := &StreamingResearchAgent{canon: urlcanon.New(nil)}
+	question := &ResearchQuestion{
+		SearchResults: []*tools2.SearchResponse{
+			{Results: []*search.SearchResultItem{{URL: "https://old.example/"}}},
+			{Results: []*search.SearchResultItem{
+				{URL: "https://a.example/"},
+				{URL: "https://b.example/", Title: "B", Content: "# B\n\nFull page"},
+				{URL: ""},
+				{URL: "https://a.example"},
+				{URL: "https://b.example/#section", Content: "duplicate"},
+				{URL: "https://c.example/", Content: "  \n"},
+				{URL: "https://scraped.example/", Content: "already scraped"},
+				{URL: "https://disallowed.example/"},
+				{URL: "https://failed.example/"},
+			}},
+		},
+		WebContents: []*tools2.WebScrapeResponse{{
+			Results: []*web.WebContent{{URL: "https://scraped.example"}},
+			Failures: []*tools2.WebScrapeFailure{
+				{URL: "https://disallowed.example/", Kind: string(web.ScrapeErrorDisallowed)},
+				{URL: "https://failed.example/", Kind: "timeout"},
+			},
+		}},
+	}
+
+	urls, provided := agent.scrapeTargets(question)
+
+	wantURLs := []string{"https://a.example/", "https://c.example/", "https://failed.example/"}
+	if !reflect.DeepEqual(urls, wantURLs) {
+		t.Errorf("scrapeTargets() urls = %v, want %v", urls, wantURLs)
+	}
+	wantProvided := []*web.WebContent{{URL: "https://b.example/", Title: "B", Content: "# B\n\nFull page"}}
+	if !reflect.DeepEqual(provided, wantProvided) {
+		t.Errorf("scrapeTargets() provided %d pages, want the content of https://b.example/", len(provided))
+	}
+}
+
+func TestScrapeTargetsAllProvided(t *testing.T) {
+	agent := &StreamingResearchAgent{canon: urlcanon.New(nil)}
+	question := &ResearchQuestion{SearchResults: []*tools2.SearchResponse{{Results: []*search.SearchResultItem{
+		{URL: "https://a.example/", Content: "A"},
+		{URL: "https://b.example/", Content: "B"},
+	}}}}
+
+	urls, provided := agent.scrapeTargets(question)
+	if len(urls) != 0 || len(provided) != 2 {
+		t.Errorf("scrapeTargets() = %v, %d pages, want no URLs and 2 pages", urls, len(provided))
+	}
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/anboat/strato-sdk/adapters/llm"
+	"github.com/anboat/strato-sdk/adapters/search"
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+// classifyTimeout bounds the model call of the LLM query classifier, so that a slow model
+// delays a search by at most a few seconds before the keyword rules take over.
+const classifyTimeout = 10 * time.Second
+
+// maxClassifications bounds the memoized classifications of the LLM query classifier; the memo
+// is cleared when it reaches the limit.
+const maxClassifications = 1024
+
+// classifyPrompt instructs the model to classify a search query.
+const classifyPrompt = `Classify the web search query into exactly one of these classes:
+- news: current events, recent announcements, breaking developments
+- social: public opinion, sentiment or discussion on social media
+- academic: scientific papers, studies, scholarly research
+- code: programming, software libraries, APIs, error messages
+- general: anything else
+
+Answer with the class name only.`
+
+// LLMQueryClassifier classifies search queries with a language model.
+// When the model fails or answers with an unknown class, it falls back to the keyword rules.
+// Classifications are memoized per normalized query, so that repeated queries skip the model call.
+type LLMQueryClassifier struct {
+	chatModel model.BaseChatModel
+	fallback  search.QueryClassifier
+
+	mu   sync.Mutex
+	memo map[string]string
+}
+
+// NewLLMQueryClassifier creates a query classifier that uses the given chat model.
+//
+// Parameters:
+//   - chatModel: The chat model used to classify queries; a small, fast model is sufficient.
+//   - fallback: The classifier used when the model fails; nil uses the built-in keyword rules.
+//
+// Returns:
+//   - *LLMQueryClassifier: The query classifier.
+func NewLLMQueryClassifier(chatModel model.BaseChatModel, fallback search.QueryClassifier) *LLMQueryClassifier {
+	if fallback == nil {
+		fallback = search.NewKeywordClassifier(nil)
+	}
+	return &LLMQueryClassifier{chatModel: chatModel, fallback: fallback, memo: make(map[string]string)}
+}
+
+// Classify implements search.QueryClassifier.
+func (c *LLMQueryClassifier) Classify(ctx context.Context, query string) (string, error) {
+	key := normalizeClassifyQuery(query)
+	c.mu.Lock()
+	class, ok := c.memo[key]
+	c.mu.Unlock()
+	if ok {
+		return class, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, classifyTimeout)
+	defer cancel()
+
+	response, err := c.chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(classifyPrompt),
+		schema.UserMessage(query),
+	})
+	if err != nil {
+		return c.fallback.Classify(ctx, query)
+	}
+
+	answer := strings.ToLower(strings.Trim(strings.TrimSpace(response.Content), ".\"'`"))
+	for _, class := range search.QueryClasses {
+		if answer == class {
+			c.remember(key, class)
+			return class, nil
+		}
+	}
+	return c.fallback.Classify(ctx, query)
+}
+
+// remember memoizes the model's classification of a normalized query. Fallback classifications
+// are not memoized, so that a query is sent to the model again once it recovers.
+func (c *LLMQueryClassifier) remember(key, class string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.memo) >= maxClassifications {
+		clear(c.memo)
+	}
+	c.memo[key] = class
+}
+
+// normalizeClassifyQuery normalizes a query for memoization by lowercasing it and collapsing whitespace.
+func normalizeClassifyQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// registerLLMQueryClassifier registers the LLM query classifier when the routing configuration selects it.
+func registerLLMQueryClassifier(routingConfig *types.SearchRoutingConfig) error {
+	if !routingConfig.Enabled || strings.ToLower(strings.TrimSpace(routingConfig.Classifier)) != search.ClassifierLLM {
+		return nil
+	}
+
+	var chatModel model.BaseChatModel
+	var err error
+	if routingConfig.Model != "" {
+		chatModel, err = llm.GetChatModel(context.Background(), routingConfig.Model)
+	} else {
+		chatModel, err = llm.GetDefaultChatModel(context.Background())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create query classifier model: %w", err)
+	}
+
+	search.RegisterQueryClassifier(search.ClassifierLLM, NewLLMQueryClassifier(chatModel, search.NewKeywordClassifierFromConfig(routingConfig)))
+	return nil
+}
@@ -11,13 +11,15 @@ import (
 
 	"github.com/anboat/strato-sdk/adapters/search"
 	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 )
 
 // Global variables to avoid repeated initialization.
 var (
-	searchStrategy  search.SearchStrategy
-	searchInitOnce  sync.Once
-	searchInitError error
+	searchStrategy   search.SearchStrategy
+	searchStrategyMu sync.RWMutex
+	searchInitOnce   sync.Once
+	searchInitError  error
 )
 
 // initSearchAdapters initializes the search adapters and strategy.
@@ -30,26 +32,51 @@ func initSearchAdapters() {
 			return
 		}
 
-		// 2. Create the search strategy from the configuration.
+		// 2. Register the LLM query classifier if search routing uses it.
+		if searchConfig := config.GetSearchConfig(); searchConfig != nil {
+			if err := registerLLMQueryClassifier(&searchConfig.Routing); err != nil {
+				searchInitError = err
+				return
+			}
+		}
+
+		// 3. Create the search strategy from the configuration.
 		strategy, err := search.NewDefaultSearchStrategyFromConfig()
 		if err != nil {
 			searchInitError = fmt.Errorf("failed to create search strategy: %w", err)
 			return
 		}
 
-		searchStrategy = strategy
+		setSearchStrategy(strategy)
 	})
 }
 
+// currentSearchStrategy returns the search strategy in use.
+func currentSearchStrategy() search.SearchStrategy {
+	searchStrategyMu.RLock()
+	defer searchStrategyMu.RUnlock()
+	return searchStrategy
+}
+
+// setSearchStrategy replaces the search strategy; searches already running finish with the old one.
+func setSearchStrategy(strategy search.SearchStrategy) {
+	searchStrategyMu.Lock()
+	defer searchStrategyMu.Unlock()
+	searchStrategy = strategy
+}
+
 // SearchRequest defines the parameters for a search request.
 // It uses jsonschema tags to define parameter constraints for the Eino framework.
 type SearchRequest struct {
-	Query      string `json:"query" jsonschema:"required,description=The search query string (required)."`
-	Num        int    `json:"num" jsonschema:"minimum=1,maximum=50,description=The number of results to return, default is 10."`
-	Lang       string `json:"lang" jsonschema:"description=The search language, e.g., zh-CN, en-US."`
-	Region     string `json:"region" jsonschema:"description=The search region, e.g., CN, US."`
-	SafeSearch string `json:"safe_search" jsonschema:"enum=off,enum=moderate,enum=strict,description=The safe search level."`
-	TimeRange  string `json:"time_range" jsonschema:"description=The time range for the search, e.g., past_day, past_week."`
+	Query       string `json:"query" jsonschema:"required,description=The search query string (required)."`
+	Num         int    `json:"num" jsonschema:"minimum=1,maximum=50,description=The number of results to return, default is 10."`
+	Lang        string `json:"lang" jsonschema:"description=The search language, e.g., zh-CN, en-US."`
+	Region      string `json:"region" jsonschema:"description=The search region, e.g., CN, US."`
+	SafeSearch  string `json:"safe_search" jsonschema:"enum=off,enum=moderate,enum=strict,description=The safe search level."`
+	TimeRange   string `json:"time_range" jsonschema:"enum=day,enum=week,enum=month,enum=year,description=Only return results published within this time range."`
+	Site        string `json:"site" jsonschema:"description=Only return results from this site, e.g., arxiv.org."`
+	FileType    string `json:"file_type" jsonschema:"description=Only return documents of this file type, e.g., pdf."`
+	BypassCache bool   `json:"bypass_cache" jsonschema:"description=Skip cached results and fetch fresh ones."`
 }
 
 // SearchResponse defines the structure of the search response.
@@ -58,10 +85,18 @@ type SearchResponse struct {
 	Message    string                     `json:"message,omitempty"`
 	Query      string                     `json:"query"`
 	Engine     string                     `json:"engine,omitempty"`
+	Route      string                     `json:"route,omitempty"`
 	Results    []*search.SearchResultItem `json:"results,omitempty"`
 	TotalCount int                        `json:"total_count"`
 	TimeTaken  int64                      `json:"time_taken_ms"`
 	Error      string                     `json:"error,omitempty"`
+
+	// Direct answers, query hints and diagnostics, for engines that provide them.
+	Answers             []*search.DirectAnswer     `json:"answers,omitempty"`
+	Infoboxes           []*search.Infobox          `json:"infoboxes,omitempty"`
+	Suggestions         []string                   `json:"suggestions,omitempty"`
+	Corrections         []string                   `json:"corrections,omitempty"`
+	UnresponsiveEngines []*search.EngineDiagnostic `json:"unresponsive_engines,omitempty"`
 }
 
 // searchFunc is the underlying implementation of the search tool.
@@ -93,7 +128,7 @@ func searchFunc(ctx context.Context, request *SearchRequest, opts ...tool.Option
 	searchRequest := buildSearchRequest(request)
 
 	// Execute the search using the configured strategy.
-	result, err := searchStrategy.Execute(ctx, searchRequest)
+	result, err := currentSearchStrategy().Execute(ctx, searchRequest)
 	if err != nil {
 		return &SearchResponse{
 			Success:   false,
@@ -125,13 +160,16 @@ func validateSearchRequest(request *SearchRequest) error {
 // buildSearchRequest builds the internal search.SearchRequest from the tool's SearchRequest.
 func buildSearchRequest(request *SearchRequest) *search.SearchRequest {
 	searchRequest := &search.SearchRequest{
-		Query:      request.Query,
-		Num:        request.Num,
-		Offset:     0, // Default to the first page.
-		Lang:       request.Lang,
-		Region:     request.Region,
-		SafeSearch: request.SafeSearch,
-		TimeRange:  request.TimeRange,
+		Query:       request.Query,
+		Num:         request.Num,
+		Offset:      0, // Default to the first page.
+		Lang:        request.Lang,
+		Region:      request.Region,
+		SafeSearch:  request.SafeSearch,
+		TimeRange:   search.NormalizeTimeRange(request.TimeRange),
+		Site:        request.Site,
+		FileType:    request.FileType,
+		BypassCache: request.BypassCache,
 	}
 
 	return searchRequest
@@ -143,8 +181,15 @@ func buildSearchResponse(result *search.SearchResponse, query string, startTime
 		Success:    true,
 		Query:      query,
 		Results:    result.Results,
+		Route:      result.Route,
 		TotalCount: result.TotalCount,
 		TimeTaken:  time.Since(startTime).Milliseconds(),
+
+		Answers:             result.Answers,
+		Infoboxes:           result.Infoboxes,
+		Suggestions:         result.Suggestions,
+		Corrections:         result.Corrections,
+		UnresponsiveEngines: result.UnresponsiveEngines,
 	}
 
 	// Extract the engine name from metadata.
@@ -186,3 +231,57 @@ func NewSearchTool() (tool.InvokableTool, error) {
 		searchFunc,
 	)
 }
+
+// SearchEngineHealth returns the current health of every configured search engine,
+// e.g., for dashboards. It returns an error if the search strategy does not track health.
+func SearchEngineHealth() (map[search.SearchEngine]resilience.Health, error) {
+	initSearchAdapters()
+	if searchInitError != nil {
+		return nil, searchInitError
+	}
+
+	// Look through decorators, such as the cache, for the strategy that tracks health.
+	strategy := currentSearchStrategy()
+	for {
+		if reporter, ok := strategy.(interface {
+			Health() map[search.SearchEngine]resilience.Health
+		}); ok {
+			return reporter.Health(), nil
+		}
+		wrapper, ok := strategy.(interface{ Unwrap() search.SearchStrategy })
+		if !ok {
+			return nil, fmt.Errorf("search strategy does not report engine health")
+		}
+		strategy = wrapper.Unwrap()
+	}
+}
+
+// ReloadSearchAdapters registers the search adapters again from the current configuration,
+// e.g., after config.UpdateConfig, and rebuilds the search strategy from it. Engines enabled in the
+// configuration are recreated with their new settings and engines that are no longer enabled are
+// unregistered; the new strategy uses the current fallback order, mixed engines, routes and cache
+// settings. Rebuilding the strategy resets the engines' circuit breakers and health and, for the
+// memory cache, the cached responses. Searches already running finish with the old strategy.
+// To take an engine out of rotation temporarily, use search.DisableSearchAdapter instead.
+func ReloadSearchAdapters() error {
+	initSearchAdapters()
+	if searchInitError != nil {
+		return searchInitError
+	}
+
+	if err := search.RegisterAllSearchAdapters(); err != nil {
+		return fmt.Errorf("failed to reload search adapters: %w", err)
+	}
+	if searchConfig := config.GetSearchConfig(); searchConfig != nil {
+		if err := registerLLMQueryClassifier(&searchConfig.Routing); err != nil {
+			return err
+		}
+	}
+
+	strategy, err := search.NewDefaultSearchStrategyFromConfig()
+	if err != nil {
+		return fmt.Errorf("failed to rebuild search strategy: %w", err)
+	}
+	setSearchStrategy(strategy)
+	return nil
+}
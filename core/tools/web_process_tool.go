@@ -11,13 +11,15 @@ import (
 
 	"github.com/anboat/strato-sdk/adapters/web"
 	"github.com/anboat/strato-sdk/config"
+	"github.com/anboat/strato-sdk/pkg/resilience"
 )
 
 // Global variables to avoid repeated initialization.
 var (
-	webStrategy  web.WebStrategy
-	webInitOnce  sync.Once
-	webInitError error
+	webStrategy   web.WebStrategy
+	webStrategyMu sync.RWMutex
+	webInitOnce   sync.Once
+	webInitError  error
 )
 
 // initWebAdapters initializes the web adapters and strategy.
@@ -37,10 +39,24 @@ func initWebAdapters() {
 			return
 		}
 
-		webStrategy = strategy
+		setWebStrategy(strategy)
 	})
 }
 
+// currentWebStrategy returns the web strategy in use.
+func currentWebStrategy() web.WebStrategy {
+	webStrategyMu.RLock()
+	defer webStrategyMu.RUnlock()
+	return webStrategy
+}
+
+// setWebStrategy replaces the web strategy; scrapes already running finish with the old one.
+func setWebStrategy(strategy web.WebStrategy) {
+	webStrategyMu.Lock()
+	defer webStrategyMu.Unlock()
+	webStrategy = strategy
+}
+
 // WebScrapeRequest defines the parameters for a web scraping request.
 // It uses jsonschema tags to define parameter constraints for the Eino framework.
 type WebScrapeRequest struct {
@@ -56,6 +72,18 @@ type WebScrapeResponse struct {
 	Message string            `json:"message,omitempty"`
 	Results []*web.WebContent `json:"results,omitempty"`
 	Error   string            `json:"error,omitempty"`
+
+	// Failures lists the URLs of a batch that could not be scraped by any scraper.
+	Failures []*WebScrapeFailure `json:"failures,omitempty"`
+}
+
+// WebScrapeFailure describes a URL that could not be scraped.
+type WebScrapeFailure struct {
+	URL        string `json:"url"`
+	Kind       string `json:"kind"`                  // timeout, http_status, blocked, empty, disallowed or other
+	StatusCode int    `json:"status_code,omitempty"` // HTTP status, for http_status and blocked
+	Scraper    string `json:"scraper,omitempty"`     // The last scraper that was tried
+	Error      string `json:"error"`
 }
 
 // webProcessFunc is the underlying implementation of the web processing tool.
@@ -84,31 +112,38 @@ func webProcessFunc(ctx context.Context, request *WebScrapeRequest) (*WebScrapeR
 
 	// Execute the scraping operation.
 	var results []*web.WebContent
+	var failures []*WebScrapeFailure
 	var scrapeErr error
+	strategy := currentWebStrategy()
 
 	if request.URL != "" {
 		// Single URL scrape.
-		result, err := webStrategy.Execute(ctx, request.URL, scrapeOptions)
+		result, err := strategy.Execute(ctx, request.URL, scrapeOptions)
 		if err != nil {
 			scrapeErr = err
+			failures = []*WebScrapeFailure{newWebScrapeFailure(&web.ScrapeResult{URL: request.URL, Err: web.NewScrapeError(request.URL, err)})}
 		} else {
 			results = []*web.WebContent{result}
 		}
 	} else if len(request.URLs) > 0 {
-		// Batch URL scrape.
-		results, scrapeErr = webStrategy.ExecuteMultiple(ctx, request.URLs, scrapeOptions)
+		// Batch URL scrape; URLs that fail on one scraper are retried on the next.
+		results, failures = splitScrapeResults(strategy.ExecuteBatch(ctx, request.URLs, scrapeOptions))
+		if len(results) == 0 && len(failures) > 0 {
+			scrapeErr = fmt.Errorf("all %d URLs failed, first error: %s", len(failures), failures[0].Error)
+		}
 	}
 
 	// Handle scraping errors.
 	if scrapeErr != nil {
 		return &WebScrapeResponse{
-			Success: false,
-			Error:   fmt.Sprintf("scraping failed: %v", scrapeErr),
+			Success:  false,
+			Error:    fmt.Sprintf("scraping failed: %v", scrapeErr),
+			Failures: failures,
 		}, nil
 	}
 
 	// Build the final response.
-	response := buildWebScrapeResponse(results, startTime)
+	response := buildWebScrapeResponse(results, failures, startTime)
 	return response, nil
 }
 
@@ -165,12 +200,44 @@ func buildScrapeOptions(request *WebScrapeRequest) *web.ScrapeOptions {
 	return options
 }
 
+// splitScrapeResults splits the results of a batch into the scraped pages and the failures,
+// keeping the order of the URLs.
+func splitScrapeResults(batch []*web.ScrapeResult) ([]*web.WebContent, []*WebScrapeFailure) {
+	results := make([]*web.WebContent, 0, len(batch))
+	var failures []*WebScrapeFailure
+	for _, result := range batch {
+		if result.OK() {
+			results = append(results, result.Content)
+			continue
+		}
+		failures = append(failures, newWebScrapeFailure(result))
+	}
+	return results, failures
+}
+
+// newWebScrapeFailure describes the failed result of a URL.
+func newWebScrapeFailure(result *web.ScrapeResult) *WebScrapeFailure {
+	failure := &WebScrapeFailure{URL: result.URL, Scraper: string(result.Scraper)}
+	if result.Err != nil {
+		failure.Kind = string(result.Err.Kind)
+		failure.StatusCode = result.Err.StatusCode
+		failure.Error = result.Err.Error()
+	}
+	return failure
+}
+
 // buildWebScrapeResponse builds the final WebScrapeResponse.
-func buildWebScrapeResponse(results []*web.WebContent, startTime time.Time) *WebScrapeResponse {
+func buildWebScrapeResponse(results []*web.WebContent, failures []*WebScrapeFailure, startTime time.Time) *WebScrapeResponse {
+	message := fmt.Sprintf("Successfully scraped %d pages in %dms", len(results), time.Since(startTime).Milliseconds())
+	if len(failures) > 0 {
+		message += fmt.Sprintf(", %d pages failed", len(failures))
+	}
+
 	response := &WebScrapeResponse{
-		Success: true,
-		Results: results,
-		Message: fmt.Sprintf("Successfully scraped %d pages in %dms", len(results), time.Since(startTime).Milliseconds()),
+		Success:  true,
+		Results:  results,
+		Message:  message,
+		Failures: failures,
 	}
 
 	return response
@@ -198,3 +265,44 @@ func NewWebProcessTool() (tool.InvokableTool, error) {
 		webProcessFunc,
 	)
 }
+
+// WebScraperHealth returns the current health of every configured web scraper,
+// e.g., for dashboards. It returns an error if the web strategy does not track health.
+func WebScraperHealth() (map[web.WebScraper]resilience.Health, error) {
+	initWebAdapters()
+	if webInitError != nil {
+		return nil, webInitError
+	}
+
+	reporter, ok := currentWebStrategy().(interface {
+		Health() map[web.WebScraper]resilience.Health
+	})
+	if !ok {
+		return nil, fmt.Errorf("web strategy does not report scraper health")
+	}
+	return reporter.Health(), nil
+}
+
+// ReloadWebAdapters registers the web adapters again from the current configuration,
+// e.g., after config.UpdateConfig, and rebuilds the web strategy from it. Scrapers enabled in the
+// configuration are recreated with their new settings and scrapers that are no longer enabled are
+// unregistered; the new strategy uses the current scraper order and settings. Rebuilding the
+// strategy resets the scrapers' circuit breakers and health. Scrapes already running finish with
+// the old strategy. To take a scraper out of rotation temporarily, use web.DisableWebAdapter instead.
+func ReloadWebAdapters() error {
+	initWebAdapters()
+	if webInitError != nil {
+		return webInitError
+	}
+
+	if err := web.RegisterAllWebAdapters(); err != nil {
+		return fmt.Errorf("failed to reload web adapters: %w", err)
+	}
+
+	strategy, err := web.NewDefaultWebStrategyFromConfig()
+	if err != nil {
+		return fmt.Errorf("failed to rebuild web strategy: %w", err)
+	}
+	setWebStrategy(strategy)
+	return nil
+}
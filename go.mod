@@ -10,9 +10,11 @@ require (
 	github.com/cloudwego/eino-ext/components/model/openai v0.0.0-20250616031540-9f38f72c63e9
 	github.com/cloudwego/eino-ext/components/model/qwen v0.0.0-20250616031540-9f38f72c63e9
 	github.com/cloudwego/eino-ext/libs/acl/openai v0.0.0-20250605072634-0f875e04269d
+	github.com/go-viper/mapstructure/v2 v2.2.1
 	github.com/ollama/ollama v0.5.12
 	github.com/spf13/viper v1.20.1
 	go.uber.org/zap v1.27.0
+	golang.org/x/net v0.33.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
@@ -43,7 +45,6 @@ require (
 	github.com/getkin/kin-openapi v0.118.0 // indirect
 	github.com/go-openapi/jsonpointer v0.21.1 // indirect
 	github.com/go-openapi/swag v0.23.1 // indirect
-	github.com/go-viper/mapstructure/v2 v2.2.1 // indirect
 	github.com/goph/emperror v0.17.2 // indirect
 	github.com/invopop/yaml v0.1.0 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
@@ -0,0 +1,265 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a call is rejected because the circuit breaker of the service is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState string
+
+// Circuit breaker states.
+const (
+	// BreakerClosed lets all calls through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects all calls until the cooldown has elapsed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe call through to test whether the service has recovered.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// Default circuit breaker settings.
+const (
+	DefaultFailureThreshold = 5
+	DefaultMinRequests      = 10
+	DefaultWindowSize       = 20
+	DefaultCooldown         = 30 * time.Second
+)
+
+// latencySmoothing is the weight of the latest call in the moving average latency.
+const latencySmoothing = 0.2
+
+// minLatencySamples is the number of successful calls needed before latency percentiles are reported.
+const minLatencySamples = 5
+
+// BreakerConfig configures a circuit breaker.
+type BreakerConfig struct {
+	// Enabled lets the breaker open; a disabled breaker only tracks health.
+	Enabled bool
+	// FailureThreshold is the number of consecutive failures that opens the breaker; zero uses DefaultFailureThreshold.
+	FailureThreshold int
+	// ErrorRateThreshold is the failure rate (0-1) within the window that opens the breaker; zero disables it.
+	ErrorRateThreshold float64
+	// MinRequests is the number of calls in the window before the error rate is considered; zero uses DefaultMinRequests.
+	MinRequests int
+	// WindowSize is the number of recent calls the error rate is computed over; zero uses DefaultWindowSize.
+	WindowSize int
+	// Cooldown is how long the breaker stays open before a probe is let through; zero uses DefaultCooldown.
+	Cooldown time.Duration
+}
+
+// Health is a snapshot of the health of a service, as tracked by its circuit breaker.
+type Health struct {
+	State               BreakerState  `json:"state"`                  // Current breaker state.
+	ConsecutiveFailures int           `json:"consecutive_failures"`   // Failures since the last success.
+	ErrorRate           float64       `json:"error_rate"`             // Failure rate over the recent calls.
+	Requests            int64         `json:"requests"`               // Total number of recorded calls.
+	Failures            int64         `json:"failures"`               // Total number of failed calls.
+	AvgLatency          time.Duration `json:"avg_latency"`            // Moving average latency of the calls.
+	LastLatency         time.Duration `json:"last_latency"`           // Latency of the last call.
+	P90Latency          time.Duration `json:"p90_latency,omitempty"`  // 90th percentile latency of the recent successful calls.
+	LastError           string        `json:"last_error,omitempty"`   // Error of the last failed call.
+	LastFailure         time.Time     `json:"last_failure,omitempty"` // Time of the last failed call.
+	OpenedAt            time.Time     `json:"opened_at,omitempty"`    // Time the breaker last opened.
+}
+
+// CircuitBreaker tracks the health of a service and stops calls to it while it is failing.
+// Only service failures count against the service: network errors, timeouts, and 408, 429
+// and 5xx responses (see IsRetryable). Other errors, such as a 404 for a single page, show
+// that the service is responding and count as successful calls. It is safe for concurrent use.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	config   BreakerConfig
+	state    BreakerState
+	probing  bool   // A half-open probe call is in flight.
+	outcomes []bool // Ring buffer of recent outcomes, true for failures.
+	next     int    // Next position in the ring buffer.
+	// Ring buffer of the latencies of recent successful calls.
+	latencies   []time.Duration
+	nextLatency int
+	health      Health
+}
+
+// NewCircuitBreaker creates a closed circuit breaker with the given configuration.
+func NewCircuitBreaker(config BreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = DefaultMinRequests
+	}
+	if config.WindowSize <= 0 {
+		config.WindowSize = DefaultWindowSize
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultCooldown
+	}
+
+	return &CircuitBreaker{
+		config:   config,
+		state:    BreakerClosed,
+		outcomes: make([]bool, 0, config.WindowSize),
+	}
+}
+
+// Ready reports whether the breaker would let a call through, without reserving the half-open probe.
+// It is used to order services, while Allow is called right before the actual call.
+func (b *CircuitBreaker) Ready() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		return time.Since(b.health.OpenedAt) >= b.config.Cooldown
+	case BreakerHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+// Allow reports whether a call may proceed. When the cooldown of an open breaker has elapsed,
+// it moves to half-open and lets a single probe call through.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.health.OpenedAt) < b.config.Cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record records the outcome of a call that was allowed by Allow.
+// A cancelled call is not counted, but releases the half-open probe.
+func (b *CircuitBreaker) Record(err error, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasProbe := b.state == BreakerHalfOpen
+	b.probing = false
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failed := IsRetryable(err)
+	b.health.Requests++
+	b.health.LastLatency = latency
+	if b.health.AvgLatency == 0 {
+		b.health.AvgLatency = latency
+	} else {
+		b.health.AvgLatency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(b.health.AvgLatency))
+	}
+
+	if len(b.outcomes) < b.config.WindowSize {
+		b.outcomes = append(b.outcomes, failed)
+	} else {
+		b.outcomes[b.next] = failed
+	}
+	b.next = (b.next + 1) % b.config.WindowSize
+
+	if !failed {
+		if len(b.latencies) < b.config.WindowSize {
+			b.latencies = append(b.latencies, latency)
+		} else {
+			b.latencies[b.nextLatency] = latency
+		}
+		b.nextLatency = (b.nextLatency + 1) % b.config.WindowSize
+
+		b.health.ConsecutiveFailures = 0
+		if wasProbe {
+			b.state = BreakerClosed
+			b.outcomes = b.outcomes[:0]
+			b.next = 0
+		}
+		return
+	}
+
+	b.health.Failures++
+	b.health.ConsecutiveFailures++
+	b.health.LastError = err.Error()
+	b.health.LastFailure = time.Now()
+
+	if !b.config.Enabled {
+		return
+	}
+	if wasProbe || b.health.ConsecutiveFailures >= b.config.FailureThreshold || b.errorRateExceeded() {
+		b.state = BreakerOpen
+		b.health.OpenedAt = time.Now()
+	}
+}
+
+// Health returns a snapshot of the health of the service.
+func (b *CircuitBreaker) Health() Health {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := b.health
+	health.State = b.state
+	health.ErrorRate = b.errorRate()
+	health.P90Latency, _ = b.latencyPercentile(0.9)
+	return health
+}
+
+// LatencyPercentile returns the latency percentile p (0-1) of the recent successful calls.
+// The second return value is false until enough calls have been observed.
+func (b *CircuitBreaker) LatencyPercentile(p float64) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.latencyPercentile(p)
+}
+
+// latencyPercentile computes a latency percentile; the caller must hold the lock.
+func (b *CircuitBreaker) latencyPercentile(p float64) (time.Duration, bool) {
+	if len(b.latencies) < minLatencySamples {
+		return 0, false
+	}
+	sorted := append([]time.Duration(nil), b.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	index = min(max(index, 0), len(sorted)-1)
+	return sorted[index], true
+}
+
+// errorRateExceeded reports whether the failure rate within the window exceeds the threshold.
+func (b *CircuitBreaker) errorRateExceeded() bool {
+	if b.config.ErrorRateThreshold <= 0 || len(b.outcomes) < b.config.MinRequests {
+		return false
+	}
+	return b.errorRate() >= b.config.ErrorRateThreshold
+}
+
+// errorRate returns the failure rate within the window.
+func (b *CircuitBreaker) errorRate() float64 {
+	if len(b.outcomes) == 0 {
+		return 0
+	}
+	failures := 0
+	for _, failed := range b.outcomes {
+		if failed {
+			failures++
+		}
+	}
+	return float64(failures) / float64(len(b.outcomes))
+}
@@ -0,0 +1,194 @@
+package resilience
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var (
+	errUnavailable = &StatusError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+	errNotFound    = &StatusError{StatusCode: http.StatusNotFound, Message: "not found"}
+)
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerConfig{Enabled: true, FailureThreshold: 3, Cooldown: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		if !breaker.Allow() {
+			t.Fatalf("call %d rejected before the threshold", i+1)
+		}
+		breaker.Record(errUnavailable, time.Millisecond)
+	}
+	if state := breaker.Health().State; state != BreakerClosed {
+		t.Fatalf("state after 2 failures = %s, want %s", state, BreakerClosed)
+	}
+
+	breaker.Allow()
+	breaker.Record(errUnavailable, time.Millisecond)
+	health := breaker.Health()
+	if health.State != BreakerOpen {
+		t.Fatalf("state after 3 failures = %s, want %s", health.State, BreakerOpen)
+	}
+	if health.ConsecutiveFailures != 3 || health.Failures != 3 || health.Requests != 3 {
+		t.Errorf("health = %+v", health)
+	}
+	if breaker.Allow() || breaker.Ready() {
+		t.Error("open breaker lets calls through during the cooldown")
+	}
+}
+
+func TestCircuitBreakerCountsOnlyServiceFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantState  BreakerState
+		wantCounts bool
+	}{
+		{name: "service failure", err: errUnavailable, wantState: BreakerOpen, wantCounts: true},
+		{name: "page not found", err: errNotFound, wantState: BreakerClosed, wantCounts: true},
+		{name: "cancelled", err: context.Canceled, wantState: BreakerClosed, wantCounts: false},
+		{name: "success", err: nil, wantState: BreakerClosed, wantCounts: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker(BreakerConfig{Enabled: true, FailureThreshold: 1, Cooldown: time.Hour})
+			breaker.Allow()
+			breaker.Record(tt.err, time.Millisecond)
+
+			health := breaker.Health()
+			if health.State != tt.wantState {
+				t.Errorf("state = %s, want %s", health.State, tt.wantState)
+			}
+			if counted := health.Requests == 1; counted != tt.wantCounts {
+				t.Errorf("requests = %d, counted = %v, want %v", health.Requests, counted, tt.wantCounts)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerDisabledOnlyTracksHealth(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 1})
+	for i := 0; i < 5; i++ {
+		if !breaker.Allow() {
+			t.Fatal("disabled breaker rejected a call")
+		}
+		breaker.Record(errUnavailable, time.Millisecond)
+	}
+	if health := breaker.Health(); health.State != BreakerClosed || health.Failures != 5 {
+		t.Errorf("health = %+v, want closed with 5 failures", health)
+	}
+}
+
+func TestCircuitBreakerErrorRate(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerConfig{
+		Enabled:            true,
+		FailureThreshold:   100,
+		ErrorRateThreshold: 0.5,
+		MinRequests:        4,
+		WindowSize:         4,
+		Cooldown:           time.Hour,
+	})
+
+	// Alternate failures and successes: no consecutive failures, but half of the calls fail.
+	outcomes := []error{errUnavailable, nil, errUnavailable}
+	for _, err := range outcomes {
+		breaker.Allow()
+		breaker.Record(err, time.Millisecond)
+	}
+	if state := breaker.Health().State; state != BreakerClosed {
+		t.Fatalf("state before MinRequests = %s, want %s", state, BreakerClosed)
+	}
+
+	breaker.Allow()
+	breaker.Record(nil, time.Millisecond)
+	breaker.Allow()
+	breaker.Record(errUnavailable, time.Millisecond)
+	health := breaker.Health()
+	if health.State != BreakerOpen {
+		t.Errorf("state = %s with error rate %.2f, want %s", health.State, health.ErrorRate, BreakerOpen)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	tests := []struct {
+		name      string
+		probeErr  error
+		wantState BreakerState
+	}{
+		{name: "probe succeeds", probeErr: nil, wantState: BreakerClosed},
+		{name: "probe fails", probeErr: errUnavailable, wantState: BreakerOpen},
+		{name: "probe cancelled", probeErr: context.Canceled, wantState: BreakerHalfOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cooldown := 20 * time.Millisecond
+			breaker := NewCircuitBreaker(BreakerConfig{Enabled: true, FailureThreshold: 1, Cooldown: cooldown})
+			breaker.Allow()
+			breaker.Record(errUnavailable, time.Millisecond)
+			if breaker.Allow() {
+				t.Fatal("open breaker let a call through before the cooldown")
+			}
+
+			time.Sleep(cooldown + 10*time.Millisecond)
+			if !breaker.Ready() {
+				t.Fatal("breaker not ready after the cooldown")
+			}
+			if !breaker.Allow() {
+				t.Fatal("breaker did not let the probe through after the cooldown")
+			}
+			if state := breaker.Health().State; state != BreakerHalfOpen {
+				t.Fatalf("state during the probe = %s, want %s", state, BreakerHalfOpen)
+			}
+			// Only a single probe is in flight at a time.
+			if breaker.Ready() || breaker.Allow() {
+				t.Fatal("half-open breaker let a second call through during the probe")
+			}
+
+			breaker.Record(tt.probeErr, time.Millisecond)
+			if state := breaker.Health().State; state != tt.wantState {
+				t.Errorf("state after the probe = %s, want %s", state, tt.wantState)
+			}
+
+			switch tt.wantState {
+			case BreakerClosed:
+				if !breaker.Allow() {
+					t.Error("closed breaker rejected a call")
+				}
+			case BreakerOpen:
+				if breaker.Allow() {
+					t.Error("breaker reopened by a failed probe let a call through")
+				}
+			case BreakerHalfOpen:
+				// A cancelled probe releases the slot for the next probe.
+				if !breaker.Allow() {
+					t.Error("cancelled probe did not release the probe slot")
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerLatency(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerConfig{WindowSize: 10})
+	if _, ok := breaker.LatencyPercentile(0.9); ok {
+		t.Error("LatencyPercentile() reported a value without samples")
+	}
+
+	for i := 1; i <= 10; i++ {
+		breaker.Allow()
+		breaker.Record(nil, time.Duration(i)*10*time.Millisecond)
+	}
+	// Service failures do not count towards the latency percentiles.
+	breaker.Allow()
+	breaker.Record(errUnavailable, time.Hour)
+
+	p90, ok := breaker.LatencyPercentile(0.9)
+	if !ok || p90 != 90*time.Millisecond {
+		t.Errorf("LatencyPercentile(0.9) = %v, %v, want 90ms", p90, ok)
+	}
+	if health := breaker.Health(); health.P90Latency != p90 || health.LastLatency != time.Hour {
+		t.Errorf("health = %+v", health)
+	}
+}
@@ -0,0 +1,130 @@
+package resilience
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultHedgeDelay is the hedge delay used when none is configured and no latency percentile is known.
+const DefaultHedgeDelay = 2 * time.Second
+
+// hedgePercentile is the latency percentile used as the hedge delay in adaptive mode.
+const hedgePercentile = 0.9
+
+// HedgeConfig configures hedged requests across fallback services.
+type HedgeConfig struct {
+	// Enabled starts the next service in parallel when the current one is slow to answer.
+	Enabled bool
+	// Delay is how long a service may take before the next one is started; zero uses DefaultHedgeDelay.
+	Delay time.Duration
+	// Adaptive uses the p90 latency of the service as the delay once enough calls have been observed,
+	// with Delay as the delay until then.
+	Adaptive bool
+}
+
+// DelayFor returns the hedge delay of a service tracked by the given circuit breaker,
+// or zero, which never hedges, if hedging is disabled.
+func (c HedgeConfig) DelayFor(breaker *CircuitBreaker) time.Duration {
+	if !c.Enabled {
+		return 0
+	}
+
+	delay := c.Delay
+	if delay <= 0 {
+		delay = DefaultHedgeDelay
+	}
+	if c.Adaptive && breaker != nil {
+		if p90, ok := breaker.LatencyPercentile(hedgePercentile); ok {
+			delay = p90
+		}
+	}
+	return delay
+}
+
+// Hedge calls a list of alternatives, such as services in fallback order, and returns the first success.
+// Alternative i+1 is started as soon as alternative i fails, or, if delay(i) is positive, once alternative
+// i has not answered within delay(i); the slower call then keeps running in parallel. When an alternative
+// succeeds, the calls still running are cancelled through their context. With a delay that is never
+// positive, Hedge behaves like a plain sequential fallback.
+//
+// Parameters:
+//   - ctx: A context.Context that bounds all calls.
+//   - n: The number of alternatives.
+//   - delay: Returns the hedge delay of the alternative at the given index.
+//   - fn: Calls the alternative at the given index.
+//
+// Returns:
+//   - T: The result of the first successful call.
+//   - int: The index of the alternative that succeeded, or -1 if none did.
+//   - error: The error of the last call that failed if all failed, or the context error.
+func Hedge[T any](ctx context.Context, n int, delay func(index int) time.Duration, fn func(ctx context.Context, index int) (T, error)) (T, int, error) {
+	var zero T
+	if n <= 0 {
+		return zero, -1, nil
+	}
+
+	// Cancel the calls that are still running once Hedge returns.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		index  int
+		result T
+		err    error
+	}
+	// Buffered for every alternative, so that cancelled calls never block.
+	outcomes := make(chan outcome, n)
+
+	var hedgeTimer *time.Timer
+	var hedgeC <-chan time.Time
+	defer func() {
+		if hedgeTimer != nil {
+			hedgeTimer.Stop()
+		}
+	}()
+
+	started, running := 0, 0
+	start := func() {
+		index := started
+		started++
+		running++
+		go func() {
+			result, err := fn(ctx, index)
+			outcomes <- outcome{index: index, result: result, err: err}
+		}()
+
+		// Arm the hedge timer of the alternative that was just started.
+		if hedgeTimer != nil {
+			hedgeTimer.Stop()
+		}
+		hedgeC = nil
+		if d := delay(index); d > 0 && started < n {
+			hedgeTimer = time.NewTimer(d)
+			hedgeC = hedgeTimer.C
+		}
+	}
+
+	start()
+	var lastErr error
+	for running > 0 {
+		select {
+		case o := <-outcomes:
+			running--
+			if o.err == nil {
+				return o.result, o.index, nil
+			}
+			lastErr = o.err
+			if ctx.Err() != nil {
+				return zero, -1, lastErr
+			}
+			if started < n {
+				start()
+			}
+		case <-hedgeC:
+			start()
+		case <-ctx.Done():
+			return zero, -1, ctx.Err()
+		}
+	}
+	return zero, -1, lastErr
+}
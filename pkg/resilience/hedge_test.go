@@ -0,0 +1,160 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgeDelayFor(t *testing.T) {
+	warm := NewCircuitBreaker(BreakerConfig{})
+	for i := 0; i < 10; i++ {
+		warm.Allow()
+		warm.Record(nil, 300*time.Millisecond)
+	}
+	cold := NewCircuitBreaker(BreakerConfig{})
+
+	tests := []struct {
+		name    string
+		config  HedgeConfig
+		breaker *CircuitBreaker
+		want    time.Duration
+	}{
+		{name: "disabled", config: HedgeConfig{Delay: time.Second}, breaker: warm, want: 0},
+		{name: "default delay", config: HedgeConfig{Enabled: true}, breaker: warm, want: DefaultHedgeDelay},
+		{name: "fixed delay", config: HedgeConfig{Enabled: true, Delay: time.Second}, breaker: warm, want: time.Second},
+		{name: "adaptive", config: HedgeConfig{Enabled: true, Delay: time.Second, Adaptive: true}, breaker: warm, want: 300 * time.Millisecond},
+		{name: "adaptive without samples", config: HedgeConfig{Enabled: true, Delay: time.Second, Adaptive: true}, breaker: cold, want: time.Second},
+		{name: "adaptive without breaker", config: HedgeConfig{Enabled: true, Delay: time.Second, Adaptive: true}, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.DelayFor(tt.breaker); got != tt.want {
+				t.Errorf("DelayFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHedgeSequentialFallback(t *testing.T) {
+	failure := errors.New("failed")
+	tests := []struct {
+		name      string
+		fail      []bool
+		wantIndex int
+		wantCalls int32
+		wantErr   error
+	}{
+		{name: "first succeeds", fail: []bool{false, false, false}, wantIndex: 0, wantCalls: 1},
+		{name: "falls back", fail: []bool{true, true, false}, wantIndex: 2, wantCalls: 3},
+		{name: "all fail", fail: []bool{true, true}, wantIndex: -1, wantCalls: 2, wantErr: failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls, running, maxRunning atomic.Int32
+			result, index, err := Hedge(context.Background(), len(tt.fail), func(int) time.Duration { return 0 },
+				func(ctx context.Context, i int) (int, error) {
+					calls.Add(1)
+					if n := running.Add(1); n > maxRunning.Load() {
+						maxRunning.Store(n)
+					}
+					defer running.Add(-1)
+					if tt.fail[i] {
+						return 0, failure
+					}
+					return i * 10, nil
+				})
+
+			if index != tt.wantIndex || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Hedge() = %d, %d, %v, want index %d and error %v", result, index, err, tt.wantIndex, tt.wantErr)
+			}
+			if err == nil && result != index*10 {
+				t.Errorf("result = %d, want the result of alternative %d", result, index)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls.Load(), tt.wantCalls)
+			}
+			if maxRunning.Load() > 1 {
+				t.Errorf("%d alternatives ran at the same time without a hedge delay", maxRunning.Load())
+			}
+		})
+	}
+}
+
+func TestHedgeStartsNextWhenSlow(t *testing.T) {
+	slowCancelled := make(chan struct{})
+	start := time.Now()
+	result, index, err := Hedge(context.Background(), 2, func(int) time.Duration { return 20 * time.Millisecond },
+		func(ctx context.Context, i int) (string, error) {
+			if i == 0 {
+				// The slow alternative keeps running until the hedge wins, then it is cancelled.
+				<-ctx.Done()
+				close(slowCancelled)
+				return "", ctx.Err()
+			}
+			return "fast", nil
+		})
+
+	if err != nil || index != 1 || result != "fast" {
+		t.Fatalf("Hedge() = %q, %d, %v, want the hedged alternative", result, index, err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("hedge started after %v, before the hedge delay", elapsed)
+	}
+	select {
+	case <-slowCancelled:
+	case <-time.After(time.Second):
+		t.Error("the slow alternative was not cancelled after the hedge won")
+	}
+}
+
+func TestHedgeKeepsSlowerWinner(t *testing.T) {
+	// The first alternative answers after the hedge started, but before the hedged one.
+	result, index, err := Hedge(context.Background(), 2, func(int) time.Duration { return 10 * time.Millisecond },
+		func(ctx context.Context, i int) (int, error) {
+			delay := 30 * time.Millisecond
+			if i == 1 {
+				delay = time.Second
+			}
+			select {
+			case <-time.After(delay):
+				return i, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		})
+	if err != nil || index != 0 || result != 0 {
+		t.Errorf("Hedge() = %d, %d, %v, want the first alternative", result, index, err)
+	}
+}
+
+func TestHedgeContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, index, err := Hedge(ctx, 2, func(int) time.Duration { return 0 },
+		func(ctx context.Context, i int) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+	if index != -1 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Hedge() = %d, %v, want %v", index, err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Hedge() took %v, want it to stop with the context", elapsed)
+	}
+}
+
+func TestHedgeNoAlternatives(t *testing.T) {
+	_, index, err := Hedge(context.Background(), 0, func(int) time.Duration { return 0 },
+		func(ctx context.Context, i int) (int, error) {
+			t.Error("alternative called")
+			return 0, nil
+		})
+	if index != -1 || err != nil {
+		t.Errorf("Hedge() = %d, %v, want -1 and no error", index, err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package resilience provides building blocks that protect calls to external services,
+// such as search engines and web scrapers, from overload and transient failures.
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token-bucket rate limiter. Tokens are added at a fixed rate up to the
+// burst size, and every request consumes one token. It is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // Tokens added per second.
+	burst  float64   // Maximum number of tokens in the bucket.
+	tokens float64   // Tokens currently available; negative when waiters have reserved future tokens.
+	last   time.Time // Time of the last refill.
+}
+
+// NewRateLimiter creates a rate limiter that allows requestsPerSecond requests per second
+// with bursts of up to burst requests. A non-positive burst is treated as 1.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request is allowed or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n requests are allowed or the context is done.
+// If the context is done first, the reserved tokens are returned to the bucket.
+func (l *RateLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 || l.rate <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(float64(n))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(float64(n))
+		return ctx.Err()
+	}
+}
+
+// reserve takes n tokens from the bucket and returns how long the caller must wait until they are available.
+func (l *RateLimiter) reserve(n float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	l.tokens -= n
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release returns n reserved tokens to the bucket.
+func (l *RateLimiter) release(n float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+n)
+}
+
+// sharedLimiter is a rate limiter in the shared registry, together with the settings it was created with.
+type sharedLimiter struct {
+	limiter           *RateLimiter
+	requestsPerSecond float64
+	burst             int
+}
+
+// sharedLimiters holds the rate limiters shared by all users of the same key.
+var sharedLimiters = struct {
+	mu       sync.Mutex
+	limiters map[string]*sharedLimiter
+}{limiters: make(map[string]*sharedLimiter)}
+
+// SharedRateLimiter returns the rate limiter registered under the given key, creating it if needed,
+// so that every adapter instance of the same service shares a single request budget.
+// If the settings of an existing limiter differ, it is replaced by a limiter with the new settings.
+// It returns nil, which never blocks, if requestsPerSecond is not positive.
+func SharedRateLimiter(key string, requestsPerSecond float64, burst int) *RateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+
+	sharedLimiters.mu.Lock()
+	defer sharedLimiters.mu.Unlock()
+
+	if shared, ok := sharedLimiters.limiters[key]; ok && shared.requestsPerSecond == requestsPerSecond && shared.burst == burst {
+		return shared.limiter
+	}
+
+	limiter := NewRateLimiter(requestsPerSecond, burst)
+	sharedLimiters.limiters[key] = &sharedLimiter{
+		limiter:           limiter,
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+	}
+	return limiter
+}
@@ -0,0 +1,107 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(10, 3)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst of 3 took %v, want no wait", elapsed)
+	}
+
+	// The bucket is empty: the next request waits for a token, 100ms at 10 requests per second.
+	start = time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond || elapsed > 300*time.Millisecond {
+		t.Errorf("request after the burst waited %v, want about 100ms", elapsed)
+	}
+}
+
+func TestRateLimiterRate(t *testing.T) {
+	limiter := NewRateLimiter(50, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// The first request uses the burst, the other 5 wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond || elapsed > 400*time.Millisecond {
+		t.Errorf("6 requests at 50 per second took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestRateLimiterCancelReleasesTokens(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.WaitN(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitN() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The cancelled request returned its token: the next one waits for a single token, not two.
+	limiter.mu.Lock()
+	tokens := limiter.tokens
+	limiter.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens after the cancelled wait = %.2f, want the reservation returned", tokens)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	var nilLimiter *RateLimiter
+	tests := []struct {
+		name    string
+		limiter *RateLimiter
+	}{
+		{name: "nil", limiter: nilLimiter},
+		{name: "zero rate", limiter: NewRateLimiter(0, 1)},
+		{name: "shared with zero rate", limiter: SharedRateLimiter("test-unlimited", 0, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			for i := 0; i < 100; i++ {
+				if err := tt.limiter.Wait(context.Background()); err != nil {
+					t.Fatalf("Wait() error = %v", err)
+				}
+			}
+			if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+				t.Errorf("100 unlimited requests took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	first := SharedRateLimiter("test-shared", 5, 2)
+	if second := SharedRateLimiter("test-shared", 5, 2); second != first {
+		t.Error("SharedRateLimiter() returned a new limiter for the same key and settings")
+	}
+	if other := SharedRateLimiter("test-shared-other", 5, 2); other == first {
+		t.Error("SharedRateLimiter() shared a limiter between keys")
+	}
+	if changed := SharedRateLimiter("test-shared", 10, 2); changed == first {
+		t.Error("SharedRateLimiter() kept the limiter after its settings changed")
+	}
+}
@@ -0,0 +1,209 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default retry settings.
+const (
+	// DefaultBaseDelay is the backoff before the first retry.
+	DefaultBaseDelay = 500 * time.Millisecond
+	// DefaultMaxDelay caps the exponential backoff between retries.
+	DefaultMaxDelay = 10 * time.Second
+	// DefaultMaxRetryAfter is the longest Retry-After delay that is waited for; a service that asks
+	// for a longer pause is treated as unavailable so that the caller can fall back instead.
+	DefaultMaxRetryAfter = 30 * time.Second
+)
+
+// StatusError is returned by adapters when an external service responds with an unexpected HTTP status.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// RetryAfter is the delay requested by the Retry-After header, zero if absent.
+	RetryAfter time.Duration
+	// Message describes the error, usually including the response body.
+	Message string
+}
+
+// NewStatusError creates a StatusError from an HTTP response and an error message.
+func NewStatusError(resp *http.Response, message string) *StatusError {
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Message:    message,
+	}
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, given either in seconds or as an HTTP date.
+// It returns zero if the value is empty, invalid or in the past.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// IsRetryable reports whether an error is transient: a network error, an unexpected end of
+// the response, a timeout, or an HTTP 408, 429 or 5xx status (except 501 Not Implemented).
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusRequestTimeout, statusErr.StatusCode == http.StatusTooManyRequests:
+			return true
+		case statusErr.StatusCode == http.StatusNotImplemented:
+			return false
+		default:
+			return statusErr.StatusCode >= 500
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// RetryPolicy configures how an operation is retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; zero disables retries.
+	MaxRetries int
+	// AttemptTimeout bounds each attempt; zero means attempts are only bounded by the caller's context.
+	AttemptTimeout time.Duration
+	// BaseDelay is the backoff before the first retry; zero uses DefaultBaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff; zero uses DefaultMaxDelay.
+	MaxDelay time.Duration
+	// MaxRetryAfter is the longest Retry-After delay that is honored; zero uses DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+	// OnRetry, if set, is called before each retry with the number of the failed attempt (starting at 1),
+	// its error and the delay before the retry.
+	OnRetry func(attempt int, err error, delay time.Duration)
+	// BeforeAttempt, if set, is called before each attempt with its number (starting at 0), outside of
+	// the attempt timeout, e.g. to wait for a turn to call the service. An error ends Retry with that error.
+	BeforeAttempt func(ctx context.Context, attempt int) error
+}
+
+// Retry runs fn until it succeeds, fails with an error that is not retryable, or the policy's
+// retries are used up. Each attempt runs with the policy's attempt timeout, retries wait with
+// exponential backoff and jitter, and a Retry-After delay requested by the service is honored.
+//
+// Parameters:
+//   - ctx: A context.Context that bounds all attempts and waits.
+//   - policy: The retry policy.
+//   - fn: The operation to run; it receives the context of the attempt.
+//
+// Returns:
+//   - T: The result of the successful attempt.
+//   - error: The error of the last attempt, or the context error if the context ended while waiting.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		if policy.BeforeAttempt != nil {
+			if err := policy.BeforeAttempt(ctx, attempt); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+
+		result, err := runAttempt(ctx, policy.AttemptTimeout, fn)
+		if err == nil {
+			return result, nil
+		}
+
+		// Stop when retries are used up, the caller's context has ended, or the error is permanent.
+		if attempt >= policy.MaxRetries || ctx.Err() != nil || !IsRetryable(err) {
+			return result, err
+		}
+
+		delay, ok := policy.delay(attempt, err)
+		if !ok {
+			return result, err
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt+1, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// runAttempt runs a single attempt with the given timeout.
+func runAttempt[T any](ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return fn(attemptCtx)
+}
+
+// delay returns the wait before the next retry: the exponential backoff with jitter, or the
+// Retry-After delay requested by the service if it is longer. It returns false if the requested
+// delay exceeds MaxRetryAfter.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+	maxRetryAfter := p.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = DefaultMaxRetryAfter
+	}
+
+	// Exponential backoff with "equal jitter": half fixed, half random.
+	backoff := base << min(attempt, 30)
+	if backoff <= 0 || backoff > maxDelay {
+		backoff = maxDelay
+	}
+	backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		if statusErr.RetryAfter > maxRetryAfter {
+			return 0, false
+		}
+		backoff = max(backoff, statusErr.RetryAfter)
+	}
+
+	return backoff, true
+}
@@ -0,0 +1,255 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain error", err: errors.New("bad request"), want: false},
+		{name: "408", err: &StatusError{StatusCode: http.StatusRequestTimeout}, want: true},
+		{name: "429", err: &StatusError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "500", err: &StatusError{StatusCode: http.StatusInternalServerError}, want: true},
+		{name: "503 wrapped", err: fmt.Errorf("search failed: %w", &StatusError{StatusCode: http.StatusServiceUnavailable}), want: true},
+		{name: "501", err: &StatusError{StatusCode: http.StatusNotImplemented}, want: false},
+		{name: "404", err: &StatusError{StatusCode: http.StatusNotFound}, want: false},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: true},
+		{name: "cancelled", err: context.Canceled, want: false},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "5", want: 5 * time.Second},
+		{value: " 5 ", want: 5 * time.Second},
+		{value: "0", want: 0},
+		{value: "-3", want: 0},
+		{value: "soon", want: 0},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+	}
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	transient := &StatusError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+	permanent := &StatusError{StatusCode: http.StatusBadRequest, Message: "bad request"}
+
+	tests := []struct {
+		name       string
+		maxRetries int
+		errs       []error // Errors of the successive attempts; attempts beyond them succeed.
+		wantCalls  int
+		wantErr    error
+	}{
+		{name: "first attempt succeeds", maxRetries: 3, wantCalls: 1},
+		{name: "succeeds after transient failures", maxRetries: 3, errs: []error{transient, transient}, wantCalls: 3},
+		{name: "retries used up", maxRetries: 2, errs: []error{transient, transient, transient, transient}, wantCalls: 3, wantErr: transient},
+		{name: "permanent error is not retried", maxRetries: 3, errs: []error{permanent}, wantCalls: 1, wantErr: permanent},
+		{name: "retries disabled", maxRetries: 0, errs: []error{transient}, wantCalls: 1, wantErr: transient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{MaxRetries: tt.maxRetries, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+			calls := 0
+			result, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return 0, tt.errs[calls-1]
+				}
+				return calls, nil
+			})
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && result != tt.wantCalls {
+				t.Errorf("result = %d, want %d", result, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryAttemptTimeout(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 1, AttemptTimeout: 20 * time.Millisecond, BaseDelay: time.Millisecond}
+	calls := 0
+	result, err := Retry(context.Background(), policy, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "ok", nil
+	})
+	if err != nil || result != "ok" {
+		t.Fatalf("Retry() = %q, %v, want ok", result, err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2: a timed out attempt is retried", calls)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	var delays []time.Duration
+	policy.OnRetry = func(attempt int, err error, delay time.Duration) {
+		delays = append(delays, delay)
+	}
+
+	calls := 0
+	start := time.Now()
+	_, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond}
+		}
+		return calls, nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if len(delays) != 1 || delays[0] != 50*time.Millisecond {
+		t.Errorf("retry delays = %v, want [50ms]", delays)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Retry() returned after %v, before the Retry-After delay", elapsed)
+	}
+}
+
+func TestRetryCapsRetryAfter(t *testing.T) {
+	tooLong := &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxRetryAfter: time.Second}
+
+	calls := 0
+	start := time.Now()
+	_, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, tooLong
+	})
+	if !errors.Is(err, tooLong) {
+		t.Errorf("error = %v, want %v", err, tooLong)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1: a Retry-After beyond the cap is not waited for", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Retry() took %v, want an immediate return", elapsed)
+	}
+}
+
+func TestRetryStopsWhenContextEnds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	policy := RetryPolicy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: time.Second}
+	calls := 0
+	start := time.Now()
+	_, err := Retry(ctx, policy, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, &StatusError{StatusCode: http.StatusBadGateway}
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Retry() took %v, want it to stop with the context", elapsed)
+	}
+}
+
+func TestRetryBeforeAttempt(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, AttemptTimeout: 20 * time.Millisecond, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	var attempts []int
+	policy.BeforeAttempt = func(ctx context.Context, attempt int) error {
+		attempts = append(attempts, attempt)
+		// The wait is not bounded by the attempt timeout.
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}
+
+	calls := 0
+	_, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if calls == 1 {
+			return 0, &StatusError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return calls, nil
+	})
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if len(attempts) != 2 || attempts[0] != 0 || attempts[1] != 1 {
+		t.Errorf("BeforeAttempt attempts = %v, want [0 1]", attempts)
+	}
+
+	stop := errors.New("disallowed")
+	policy.BeforeAttempt = func(ctx context.Context, attempt int) error { return stop }
+	calls = 0
+	_, err = Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+		return calls, nil
+	})
+	if !errors.Is(err, stop) || calls != 0 {
+		t.Errorf("Retry() = %v after %d calls, want %v before any call", err, calls, stop)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 400 * time.Millisecond}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{attempt: 0, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 1, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 2, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{attempt: 10, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{attempt: 100, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay, ok := policy.delay(tt.attempt, errors.New("transient"))
+			if !ok || delay < tt.min || delay > tt.max {
+				t.Fatalf("delay(%d) = %v, %v, want between %v and %v", tt.attempt, delay, ok, tt.min, tt.max)
+			}
+		}
+	}
+}
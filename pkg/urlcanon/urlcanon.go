@@ -0,0 +1,261 @@
+// Package urlcanon canonicalizes URLs so that different spellings of the same page,
+// such as http/https, "www." hosts, trailing slashes, fragments, tracking parameters,
+// AMP and mobile variants, are recognized as one page.
+package urlcanon
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+// defaultTrackingParams are the query parameters stripped by default.
+// A trailing "*" matches every parameter with that prefix.
+var defaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"gclsrc",
+	"dclid",
+	"msclkid",
+	"yclid",
+	"twclid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+	"_gl",
+	"_hsenc",
+	"_hsmi",
+	"mkt_tok",
+	"ref_src",
+	"ref_url",
+	"spm",
+	"amp",
+}
+
+// mobileHostPrefixes are host prefixes of mobile variants of a site.
+var mobileHostPrefixes = []string{"m.", "mobile."}
+
+// Canonicalizer normalizes URLs according to a set of rules.
+// It is safe for concurrent use.
+type Canonicalizer struct {
+	rules       types.CanonicalizationConfig
+	stripExact  map[string]bool
+	stripPrefix []string
+	keep        map[string]bool
+}
+
+// New creates a canonicalizer with the given rules. A nil config applies every normalization.
+func New(cfg *types.CanonicalizationConfig) *Canonicalizer {
+	c := &Canonicalizer{
+		stripExact: make(map[string]bool),
+		keep:       make(map[string]bool),
+	}
+	if cfg != nil {
+		c.rules = *cfg
+	}
+
+	params := append(append([]string{}, defaultTrackingParams...), c.rules.StripParams...)
+	for _, param := range params {
+		param = strings.ToLower(strings.TrimSpace(param))
+		if param == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			c.stripPrefix = append(c.stripPrefix, prefix)
+			continue
+		}
+		c.stripExact[param] = true
+	}
+	for _, param := range c.rules.KeepParams {
+		c.keep[strings.ToLower(strings.TrimSpace(param))] = true
+	}
+
+	// The AMP query flag only marks the AMP variant of a page.
+	if c.rules.KeepAMP && !c.keep["amp"] {
+		c.keep["amp"] = true
+	}
+
+	return c
+}
+
+// Canonicalize returns the canonical form of a URL: a lowercase host without default port,
+// "www." prefix, mobile or AMP variant, a path without trailing slash or AMP suffix, sorted
+// query parameters without tracking parameters, and no fragment, as allowed by the rules.
+// The result identifies the page for comparison only: many sites do not serve the rewritten
+// URL, so fetch and cite the original URL instead. URLs that are not absolute http(s) URLs
+// are returned trimmed but otherwise unchanged.
+func (c *Canonicalizer) Canonicalize(rawURL string) string {
+	u, ok := c.parse(rawURL)
+	if !ok {
+		return strings.TrimSpace(rawURL)
+	}
+	return u.String()
+}
+
+// Key returns the key under which URLs are deduplicated: the canonical URL,
+// without the scheme unless the rules keep it. Two URLs with the same key refer to the same page.
+func (c *Canonicalizer) Key(rawURL string) string {
+	u, ok := c.parse(rawURL)
+	if !ok {
+		return strings.TrimSpace(rawURL)
+	}
+	if c.rules.KeepScheme {
+		return u.String()
+	}
+	u.Scheme = ""
+	return strings.TrimPrefix(u.String(), "//")
+}
+
+// parse parses and normalizes an absolute http(s) URL.
+func (c *Canonicalizer) parse(rawURL string) (*url.URL, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, false
+	}
+
+	if !c.rules.KeepAMP {
+		if target, ok := ampCacheTarget(u); ok {
+			return c.parse(target)
+		}
+	}
+
+	u.Host = c.normalizeHost(u.Scheme, u.Host)
+	u.Path = c.normalizePath(u.Path)
+	u.RawPath = ""
+	u.RawQuery = c.normalizeQuery(u.Query())
+	u.ForceQuery = false
+	if !c.rules.KeepFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	return u, true
+}
+
+// normalizeHost lowercases the host and removes the default port, "www." prefix,
+// mobile prefix and AMP prefix, as allowed by the rules.
+func (c *Canonicalizer) normalizeHost(scheme, host string) string {
+	host = strings.ToLower(host)
+
+	if hostname, port, err := net.SplitHostPort(host); err == nil {
+		if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+			host = hostname
+		}
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	if !c.rules.KeepWWW {
+		host = trimHostPrefix(host, "www.")
+	}
+	if !c.rules.KeepMobile {
+		for _, prefix := range mobileHostPrefixes {
+			host = trimHostPrefix(host, prefix)
+		}
+	}
+	if !c.rules.KeepAMP {
+		host = trimHostPrefix(host, "amp.")
+	}
+
+	return host
+}
+
+// normalizePath removes AMP path markers and the trailing slash, as allowed by the rules.
+func (c *Canonicalizer) normalizePath(path string) string {
+	if !c.rules.KeepAMP {
+		switch {
+		case strings.HasSuffix(path, ".amp.html"):
+			path = strings.TrimSuffix(path, ".amp.html") + ".html"
+		case strings.HasSuffix(path, "/amp") || strings.HasSuffix(path, "/amp/"):
+			path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), "/amp")
+		case strings.HasPrefix(path, "/amp/"):
+			path = strings.TrimPrefix(path, "/amp")
+		}
+	}
+
+	if !c.rules.KeepTrailingSlash {
+		path = strings.TrimRight(path, "/")
+	}
+	return path
+}
+
+// normalizeQuery removes tracking parameters and encodes the remaining ones sorted by key.
+func (c *Canonicalizer) normalizeQuery(query url.Values) string {
+	for key := range query {
+		if c.isTrackingParam(key) {
+			query.Del(key)
+		}
+	}
+	return query.Encode()
+}
+
+// isTrackingParam reports whether a query parameter is stripped by the rules.
+func (c *Canonicalizer) isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if c.keep[key] {
+		return false
+	}
+	if c.stripExact[key] {
+		return true
+	}
+	for _, prefix := range c.stripPrefix {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ampCacheTarget returns the original URL of a page served from an AMP cache,
+// e.g., https://www.google.com/amp/s/example.com/page or
+// https://example-com.cdn.ampproject.org/c/s/example.com/page.
+func ampCacheTarget(u *url.URL) (string, bool) {
+	host := strings.ToLower(u.Hostname())
+
+	var rest string
+	switch {
+	case strings.HasSuffix(host, ".cdn.ampproject.org"):
+		// The path starts with a content type marker such as /c/, /v/ or /i/.
+		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+		if len(parts) != 2 {
+			return "", false
+		}
+		rest = parts[1]
+	case (host == "google.com" || strings.HasSuffix(host, ".google.com")) && strings.HasPrefix(u.Path, "/amp/"):
+		rest = strings.TrimPrefix(u.Path, "/amp/")
+	default:
+		return "", false
+	}
+
+	scheme := "http"
+	if target, ok := strings.CutPrefix(rest, "s/"); ok {
+		scheme = "https"
+		rest = target
+	}
+	if rest == "" {
+		return "", false
+	}
+
+	target := scheme + "://" + rest
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	return target, true
+}
+
+// trimHostPrefix removes a prefix from a host name, unless only a bare suffix would remain.
+func trimHostPrefix(host, prefix string) string {
+	trimmed, ok := strings.CutPrefix(host, prefix)
+	if !ok || !strings.Contains(trimmed, ".") {
+		return host
+	}
+	return trimmed
+}
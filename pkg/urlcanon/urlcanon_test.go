@@ -0,0 +1,194 @@
+package urlcanon
+
+import (
+	"testing"
+
+	"github.com/anboat/strato-sdk/config/types"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "already canonical", url: "https://example.com/page", want: "https://example.com/page"},
+		{name: "surrounding space", url: "  https://example.com/page  ", want: "https://example.com/page"},
+		{name: "uppercase scheme and host", url: "HTTPS://Example.COM/Page", want: "https://example.com/Page"},
+		{name: "www host", url: "https://www.example.com/page", want: "https://example.com/page"},
+		{name: "trailing dot host", url: "https://example.com./page", want: "https://example.com/page"},
+		{name: "trailing slash", url: "https://example.com/page/", want: "https://example.com/page"},
+		{name: "root path", url: "https://example.com/", want: "https://example.com"},
+		{name: "fragment", url: "https://example.com/page#section", want: "https://example.com/page"},
+		{name: "empty query", url: "https://example.com/page?", want: "https://example.com/page"},
+
+		{name: "default https port", url: "https://example.com:443/page", want: "https://example.com/page"},
+		{name: "default http port", url: "http://example.com:80/page", want: "http://example.com/page"},
+		{name: "http port on https", url: "https://example.com:80/page", want: "https://example.com:80/page"},
+		{name: "custom port", url: "https://example.com:8443/page", want: "https://example.com:8443/page"},
+
+		{name: "utm parameters", url: "https://example.com/page?utm_source=x&utm_medium=y&id=1", want: "https://example.com/page?id=1"},
+		{name: "click ids", url: "https://example.com/page?fbclid=a&gclid=b&msclkid=c", want: "https://example.com/page"},
+		{name: "tracking parameter case", url: "https://example.com/page?UTM_Source=x&q=go", want: "https://example.com/page?q=go"},
+		{name: "sorted parameters", url: "https://example.com/search?q=go&a=1", want: "https://example.com/search?a=1&q=go"},
+		{name: "parameter prefix is not stripped", url: "https://example.com/page?utmost=1", want: "https://example.com/page?utmost=1"},
+
+		{name: "mobile host", url: "https://m.example.com/page", want: "https://example.com/page"},
+		{name: "long mobile host", url: "https://mobile.example.com/page", want: "https://example.com/page"},
+		{name: "bare mobile domain", url: "https://m.com/page", want: "https://m.com/page"},
+		{name: "amp host", url: "https://amp.example.com/page", want: "https://example.com/page"},
+		{name: "amp path suffix", url: "https://example.com/news/story/amp", want: "https://example.com/news/story"},
+		{name: "amp path suffix with slash", url: "https://example.com/news/story/amp/", want: "https://example.com/news/story"},
+		{name: "amp path prefix", url: "https://example.com/amp/news/story", want: "https://example.com/news/story"},
+		{name: "amp html", url: "https://example.com/news/story.amp.html", want: "https://example.com/news/story.html"},
+		{name: "amp query flag", url: "https://example.com/news/story?amp=1", want: "https://example.com/news/story"},
+		{name: "google amp cache", url: "https://www.google.com/amp/s/www.example.com/news/story/amp", want: "https://example.com/news/story"},
+		{name: "google amp cache over http", url: "https://www.google.com/amp/example.com/page", want: "http://example.com/page"},
+		{name: "ampproject cache", url: "https://example-com.cdn.ampproject.org/c/s/example.com/page?id=1&utm_source=x", want: "https://example.com/page?id=1"},
+		{name: "google non-amp path", url: "https://www.google.com/search?q=go", want: "https://google.com/search?q=go"},
+
+		{name: "relative url", url: "/page?utm_source=x", want: "/page?utm_source=x"},
+		{name: "ftp url", url: "ftp://Example.com/file/", want: "ftp://Example.com/file/"},
+		{name: "mailto", url: "mailto:someone@example.com", want: "mailto:someone@example.com"},
+		{name: "not a url", url: " not a url ", want: "not a url"},
+		{name: "empty", url: "", want: ""},
+	}
+
+	canon := New(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canon.Canonicalize(tt.url); got != tt.want {
+				t.Errorf("Canonicalize(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	canon := New(nil)
+	same := [][]string{
+		{
+			"https://example.com/page",
+			"http://www.example.com/page/",
+			"https://m.example.com/page?utm_source=newsletter#top",
+			"https://example.com:443/amp/page",
+			"https://www.google.com/amp/s/example.com/page",
+		},
+		{
+			"https://example.com/search?a=1&q=go",
+			"https://EXAMPLE.com/search?q=go&a=1&fbclid=xyz",
+		},
+	}
+	for _, group := range same {
+		want := canon.Key(group[0])
+		for _, url := range group[1:] {
+			if got := canon.Key(url); got != want {
+				t.Errorf("Key(%q) = %q, want %q as for %q", url, got, want, group[0])
+			}
+		}
+	}
+
+	different := [][2]string{
+		{"https://example.com/page", "https://example.com/other"},
+		{"https://example.com/page?id=1", "https://example.com/page?id=2"},
+		{"https://example.com:8080/page", "https://example.com/page"},
+		{"https://example.com/page", "https://example.org/page"},
+	}
+	for _, pair := range different {
+		if canon.Key(pair[0]) == canon.Key(pair[1]) {
+			t.Errorf("Key(%q) = Key(%q) = %q, want different keys", pair[0], pair[1], canon.Key(pair[0]))
+		}
+	}
+
+	if got := canon.Key("https://www.example.com/page/"); got != "example.com/page" {
+		t.Errorf("Key() = %q, want the canonical URL without scheme", got)
+	}
+	if got := canon.Key("ftp://example.com/file"); got != "ftp://example.com/file" {
+		t.Errorf("Key() of a non-http URL = %q, want it unchanged", got)
+	}
+}
+
+func TestCanonicalizeRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules types.CanonicalizationConfig
+		url   string
+		want  string
+		key   string
+	}{
+		{
+			name:  "keep scheme",
+			rules: types.CanonicalizationConfig{KeepScheme: true},
+			url:   "http://www.example.com/page",
+			want:  "http://example.com/page",
+			key:   "http://example.com/page",
+		},
+		{
+			name:  "keep www",
+			rules: types.CanonicalizationConfig{KeepWWW: true},
+			url:   "https://www.example.com/page",
+			want:  "https://www.example.com/page",
+			key:   "www.example.com/page",
+		},
+		{
+			name:  "keep fragment",
+			rules: types.CanonicalizationConfig{KeepFragment: true},
+			url:   "https://example.com/page#section",
+			want:  "https://example.com/page#section",
+			key:   "example.com/page#section",
+		},
+		{
+			name:  "keep trailing slash",
+			rules: types.CanonicalizationConfig{KeepTrailingSlash: true},
+			url:   "https://example.com/page/",
+			want:  "https://example.com/page/",
+			key:   "example.com/page/",
+		},
+		{
+			name:  "keep amp",
+			rules: types.CanonicalizationConfig{KeepAMP: true},
+			url:   "https://amp.example.com/amp/page?amp=1",
+			want:  "https://amp.example.com/amp/page?amp=1",
+			key:   "amp.example.com/amp/page?amp=1",
+		},
+		{
+			name:  "keep amp cache",
+			rules: types.CanonicalizationConfig{KeepAMP: true},
+			url:   "https://www.google.com/amp/s/example.com/page",
+			want:  "https://google.com/amp/s/example.com/page",
+			key:   "google.com/amp/s/example.com/page",
+		},
+		{
+			name:  "keep mobile",
+			rules: types.CanonicalizationConfig{KeepMobile: true},
+			url:   "https://m.example.com/page",
+			want:  "https://m.example.com/page",
+			key:   "m.example.com/page",
+		},
+		{
+			name:  "strip extra params",
+			rules: types.CanonicalizationConfig{StripParams: []string{"pk_*", "Session"}},
+			url:   "https://example.com/page?pk_campaign=x&session=1&id=2",
+			want:  "https://example.com/page?id=2",
+			key:   "example.com/page?id=2",
+		},
+		{
+			name:  "keep params",
+			rules: types.CanonicalizationConfig{KeepParams: []string{"utm_source"}},
+			url:   "https://example.com/page?utm_source=x&utm_medium=y",
+			want:  "https://example.com/page?utm_source=x",
+			key:   "example.com/page?utm_source=x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canon := New(&tt.rules)
+			if got := canon.Canonicalize(tt.url); got != tt.want {
+				t.Errorf("Canonicalize(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+			if got := canon.Key(tt.url); got != tt.key {
+				t.Errorf("Key(%q) = %q, want %q", tt.url, got, tt.key)
+			}
+		})
+	}
+}